@@ -4,28 +4,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/zapr"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	zaputil "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/reddit/achilles-sdk/pkg/logging"
+	"github.com/reddit/achilles-sdk/pkg/meta"
 )
 
 const (
 	errNoValidKubeContext      = "kubeconfig context must be specified when not in cluster"
 	errKubeContextSetInCluster = "kubeconfig context can not be specified when in cluster"
+
+	// requiredCRDsPollInterval is the interval at which discovery is polled for required CRDs
+	requiredCRDsPollInterval = 5 * time.Second
 )
 
 // Options for starting a custom controller
@@ -83,6 +95,25 @@ type Options struct {
 	// The duration that non-leader candidates will  wait to force acquire leadership.
 	// This is measured against time of last observed ack. Default is 15 seconds.
 	LeaderElectionLeaseDuration time.Duration
+
+	// CacheLabelSelector restricts the manager's cache to objects matching the label selector, keyed by object type
+	// (e.g. &corev1.Secret{}). Objects of types without a selector are cached unfiltered.
+	// NOTE: objects excluded by the selector are invisible to the manager's client and to watches, so selectors should
+	// only be set for types that carry the controller's labels (e.g. see meta.SetRedditLabels).
+	CacheLabelSelector map[client.Object]labels.Selector
+
+	// ReconciledObjects are the types of objects reconciled by the manager's controllers (e.g. &v1alpha1.MyClaim{}).
+	// Starting the manager fails if CacheLabelSelector filters any of these types, since reconciled objects are often
+	// created by users without the controller's labels.
+	ReconciledObjects []client.Object
+
+	// RequiredCRDs are the types of custom resources (reconciled or managed by the manager's controllers) that must be
+	// served by the kube-apiserver before the controllers are started. Starting the manager waits until all of them are
+	// served, e.g. while CRDs are installed alongside the controller, and fails if they aren't within RequiredCRDsTimeout.
+	RequiredCRDs []schema.GroupVersionKind
+
+	// RequiredCRDsTimeout is the maximum duration to wait for RequiredCRDs to be served. Defaults to 5 minutes.
+	RequiredCRDsTimeout time.Duration
 }
 
 func (o *Options) AddToFlags(flags *pflag.FlagSet) {
@@ -109,6 +140,8 @@ func (o *Options) AddToFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.LeaderElectionNamespace, "leader-election-namespace", "", "Namespace in which the leader election resource will be created")
 	flags.DurationVar(&o.LeaderElectionRenewDeadline, "renew-deadline", 10*time.Second, "Renew deadline for leader election controller. Must be set to ensure the resource lock has an appropriate client timeout. If set too low, a single slow response from the API server can result in losing leadership. Defaults to 10s")
 	flags.DurationVar(&o.LeaderElectionLeaseDuration, "lease-duration", 15*time.Second, "Duration that non-leader candidates will wait to force acquire leadership. This is measured against time of last observed ack. Default is 15 seconds.")
+
+	flags.DurationVar(&o.RequiredCRDsTimeout, "required-crds-timeout", 5*time.Minute, "Maximum duration to wait on startup for the controller's required CRDs to be installed")
 }
 
 // StartFunc is a function for starting a controller manager
@@ -137,6 +170,21 @@ func Start(
 		return fmt.Errorf("building manager: %w", err)
 	}
 
+	if len(opts.RequiredCRDs) > 0 {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("building discovery client: %w", err)
+		}
+
+		timeout := opts.RequiredCRDsTimeout
+		if timeout == 0 {
+			timeout = 5 * time.Minute
+		}
+		if err := waitForCRDs(ctx, log, discoveryClient, opts.RequiredCRDs, requiredCRDsPollInterval, timeout); err != nil {
+			return err
+		}
+	}
+
 	if err := startFunc(ctx, mgr); err != nil {
 		return fmt.Errorf("running start func: %w", err)
 	}
@@ -149,21 +197,71 @@ func Start(
 	return nil
 }
 
+// waitForCRDs polls discovery until all of the specified GVKs are served, returning an error listing the GVKs that
+// aren't served if the timeout elapses first.
+// Discovery errors are logged and retried, since the kube-apiserver may be briefly unavailable while CRDs are installed.
+func waitForCRDs(
+	ctx context.Context,
+	log *zap.SugaredLogger,
+	discoveryClient discovery.DiscoveryInterface,
+	gvks []schema.GroupVersionKind,
+	interval time.Duration,
+	timeout time.Duration,
+) error {
+	var missing []string
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		missing = nil
+		for _, gvk := range gvks {
+			served, err := meta.ServerSupports(discoveryClient, gvk)
+			if err != nil {
+				log.Warnf("checking whether %s is served: %s", gvk, err)
+			}
+			if !served {
+				missing = append(missing, gvk.String())
+			}
+		}
+		if len(missing) > 0 {
+			log.Infof("waiting for required CRDs to be served: %s", strings.Join(missing, ", "))
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("waiting for required CRDs: %w", ctx.Err())
+		}
+		return fmt.Errorf("required CRDs not served after %s, ensure they are installed: %s", timeout, strings.Join(missing, ", "))
+	}
+
+	log.Info("required CRDs are served")
+	return nil
+}
+
 func buildManager(
 	cfg *rest.Config,
 	log *zap.SugaredLogger,
 	schemes runtime.SchemeBuilder,
 	opts *Options,
 ) (manager.Manager, error) {
+	// the manager uses the client-go scheme by default
+	if schemes != nil {
+		if err := schemes.AddToScheme(clientgoscheme.Scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	cacheOpts, err := buildCacheOptions(clientgoscheme.Scheme, opts)
+	if err != nil {
+		return nil, fmt.Errorf("building cache options: %w", err)
+	}
+
 	mgr, err := manager.New(
 		cfg,
 		manager.Options{
-			HealthProbeBindAddress: opts.HealthAddr,
-			Metrics:                server.Options{BindAddress: opts.MetricsAddr},
-			Logger:                 zapr.NewLogger(log.Desugar()),
-			Cache: cache.Options{
-				SyncPeriod: &opts.SyncPeriod,
-			},
+			HealthProbeBindAddress:  opts.HealthAddr,
+			Metrics:                 server.Options{BindAddress: opts.MetricsAddr},
+			Logger:                  zapr.NewLogger(log.Desugar()),
+			Cache:                   cacheOpts,
 			LeaderElection:          opts.LeaderElection,
 			LeaderElectionID:        opts.LeaderElectionID,
 			LeaderElectionNamespace: opts.LeaderElectionNamespace,
@@ -183,12 +281,41 @@ func buildManager(
 		return nil, fmt.Errorf("adding readyz: %w", err)
 	}
 
-	if schemes != nil {
-		if err := schemes.AddToScheme(mgr.GetScheme()); err != nil {
-			return nil, err
+	return mgr, nil
+}
+
+// buildCacheOptions builds the manager's cache options, returning an error if a reconciled object type is filtered
+// by a label selector.
+func buildCacheOptions(scheme *runtime.Scheme, o *Options) (cache.Options, error) {
+	cacheOpts := cache.Options{
+		SyncPeriod: &o.SyncPeriod,
+	}
+	if len(o.CacheLabelSelector) == 0 {
+		return cacheOpts, nil
+	}
+
+	reconciledGVKs := map[schema.GroupVersionKind]struct{}{}
+	for _, obj := range o.ReconciledObjects {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return cache.Options{}, fmt.Errorf("getting GVK for reconciled object %T: %w", obj, err)
 		}
+		reconciledGVKs[gvk] = struct{}{}
 	}
-	return mgr, nil
+
+	cacheOpts.ByObject = make(map[client.Object]cache.ByObject, len(o.CacheLabelSelector))
+	for obj, selector := range o.CacheLabelSelector {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return cache.Options{}, fmt.Errorf("getting GVK for cache label selector object %T: %w", obj, err)
+		}
+		if _, ok := reconciledGVKs[gvk]; ok {
+			return cache.Options{}, fmt.Errorf("cache label selector %q must not be set for reconciled object %s", selector, gvk)
+		}
+		cacheOpts.ByObject[obj] = cache.ByObject{Label: selector}
+	}
+
+	return cacheOpts, nil
 }
 
 func buildRestConfig(o *Options) (*rest.Config, error) {
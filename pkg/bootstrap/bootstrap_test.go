@@ -1,10 +1,27 @@
 package bootstrap
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+	"github.com/reddit/achilles-sdk/pkg/test"
 )
 
 var _ = DescribeTable("buildRestConfig should fail",
@@ -21,6 +38,135 @@ var _ = DescribeTable("buildRestConfig should fail",
 		true, "foo", errKubeContextSetInCluster),
 )
 
+var _ = Describe("buildCacheOptions", func() {
+	selector := labels.SelectorFromSet(labels.Set{"app": "achilles"})
+
+	It("should set label selectors by object", func() {
+		cacheOpts, err := buildCacheOptions(internalscheme.MustNewScheme(), &Options{
+			CacheLabelSelector: map[client.Object]labels.Selector{&corev1.Secret{}: selector},
+			ReconciledObjects:  []client.Object{&v1alpha1.TestClaim{}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cacheOpts.ByObject).To(HaveLen(1))
+		for obj, byObject := range cacheOpts.ByObject {
+			Expect(obj).To(BeAssignableToTypeOf(&corev1.Secret{}))
+			Expect(byObject.Label).To(Equal(selector))
+		}
+	})
+
+	It("should fail if a reconciled object is filtered", func() {
+		_, err := buildCacheOptions(internalscheme.MustNewScheme(), &Options{
+			CacheLabelSelector: map[client.Object]labels.Selector{&v1alpha1.TestClaim{}: selector},
+			ReconciledObjects:  []client.Object{&v1alpha1.TestClaim{}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("must not be set for reconciled object")))
+	})
+})
+
+var _ = Describe("waitForCRDs", func() {
+	testClaimGVK := schema.GroupVersionKind{Group: "test.infrared.reddit.com", Version: "v1alpha1", Kind: "TestClaim"}
+	testClaimResources := &metav1.APIResourceList{
+		GroupVersion: testClaimGVK.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "testclaims", Kind: testClaimGVK.Kind}},
+	}
+
+	It("should wait until the CRDs are served", func() {
+		discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+		// the CRD is installed on the third discovery request
+		var requests int
+		discoveryClient.AddReactor("get", "resource", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			requests++
+			if requests == 3 {
+				discoveryClient.Resources = []*metav1.APIResourceList{testClaimResources}
+			}
+			return false, nil, nil
+		})
+
+		err := waitForCRDs(context.Background(), zap.NewNop().Sugar(), discoveryClient, []schema.GroupVersionKind{testClaimGVK}, time.Millisecond, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(Equal(3))
+	})
+
+	It("should fail if the CRDs aren't served within the timeout", func() {
+		discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{
+			Resources: []*metav1.APIResourceList{testClaimResources},
+		}}
+		testClaimedGVK := testClaimGVK.GroupVersion().WithKind("TestClaimed")
+
+		err := waitForCRDs(context.Background(), zap.NewNop().Sugar(), discoveryClient, []schema.GroupVersionKind{testClaimGVK, testClaimedGVK}, time.Millisecond, 50*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("required CRDs not served after 50ms")))
+		Expect(err).To(MatchError(ContainSubstring(testClaimedGVK.String())))
+	})
+})
+
+var _ = Describe("CacheLabelSelector", Ordered, func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		testEnv *test.TestEnv
+	)
+
+	BeforeAll(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+
+		var err error
+		testEnv, err = test.NewEnvTestBuilder(ctx).
+			WithScheme(internalscheme.MustNewScheme()).
+			Start()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		cancel()
+		Expect(testEnv.Stop()).To(Succeed())
+	})
+
+	It("should exclude objects not matching the selector from the cache", func() {
+		matching := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "matching",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "achilles"},
+			},
+		}
+		notMatching := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "not-matching",
+				Namespace: "default",
+			},
+		}
+		Expect(testEnv.Client.Create(ctx, matching)).To(Succeed())
+		Expect(testEnv.Client.Create(ctx, notMatching)).To(Succeed())
+
+		cacheOpts, err := buildCacheOptions(testEnv.Client.Scheme(), &Options{
+			CacheLabelSelector: map[client.Object]labels.Selector{
+				&corev1.Secret{}: labels.SelectorFromSet(labels.Set{"app": "achilles"}),
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		cacheOpts.Scheme = testEnv.Client.Scheme()
+
+		c, err := cache.New(testEnv.Cfg, cacheOpts)
+		Expect(err).ToNot(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(func(g Gomega) {
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(matching), &corev1.Secret{})).To(Succeed())
+		}).Should(Succeed())
+
+		err = c.Get(ctx, client.ObjectKeyFromObject(notMatching), &corev1.Secret{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		secrets := &corev1.SecretList{}
+		Expect(c.List(ctx, secrets, client.InNamespace("default"))).To(Succeed())
+		Expect(secrets.Items).To(HaveLen(1))
+		Expect(secrets.Items[0].Name).To(Equal(matching.Name))
+	})
+})
+
 func TestBootstrap(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Bootstrap")
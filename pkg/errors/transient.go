@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"errors"
+	"net"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// IsTransient returns true if the error is likely caused by a transient failure communicating with the kube-apiserver,
+// e.g. a DNS lookup failure, a refused or reset connection, or a timeout, such that retrying the request is likely to succeed.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	return utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsHTTP2ConnectionLost(err) ||
+		utilnet.IsProbableEOF(err) ||
+		utilnet.IsTimeout(err) ||
+		kerrors.IsServerTimeout(err) ||
+		kerrors.IsTimeout(err) ||
+		kerrors.IsServiceUnavailable(err) ||
+		kerrors.IsTooManyRequests(err)
+}
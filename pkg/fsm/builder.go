@@ -1,12 +1,18 @@
 package fsm
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/iancoleman/strcase"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -14,17 +20,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"github.com/reddit/achilles-sdk-api/api"
 	apitypes "github.com/reddit/achilles-sdk-api/pkg/types"
+	"github.com/reddit/achilles-sdk/pkg/fsm/events"
 	fsmhandler "github.com/reddit/achilles-sdk/pkg/fsm/handler"
 	"github.com/reddit/achilles-sdk/pkg/fsm/internal"
 	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
 	fsmtypes "github.com/reddit/achilles-sdk/pkg/fsm/types"
 	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/logging"
 	"github.com/reddit/achilles-sdk/pkg/meta"
 	"github.com/reddit/achilles-sdk/pkg/ratelimiter"
 )
@@ -61,9 +72,41 @@ type Builder[T any, Obj apitypes.FSMResource[T]] struct {
 	watches                 []watch
 	watchRemoteKinds        []watchRemoteKind
 	watchRawSources         []source.Source
+	eventChannels           []eventChannel
 	opts                    []buildOption
 	maxConcurrentReconciles int
 	reconcilerOptions       fsmtypes.ReconcilerOptions[T, Obj]
+	redditLabelKeys         map[schema.GroupVersionKind][]string
+	name                    string
+
+	managedResourceRefGracePeriod time.Duration
+	maxStatesPerReconcile         int
+	maxConditionMessageLength     int
+	minRequeueInterval            time.Duration
+	transientErrorGracePeriod     time.Duration
+	reconcileFilter               *fsmtypes.ReconcileFilter
+	forEventPredicates            []predicate.Predicate
+	traceIDsFunc                  logging.TraceIDsFunc
+	customMetricsRegisterer       prometheus.Registerer
+	priorityFunc                  func(client.Object) int
+	triggerLogWindow              time.Duration
+	ignoreChildTriggers           bool
+	unservableTypeCheckInterval   time.Duration
+	unservableTypeFailFast        bool
+	enqueueChildDeletions         bool
+	withoutDefaultOwnerRefs       bool
+	shadowClient                  client.Client
+	versionAnnotation             string
+	pauseSwitch                   *atomic.Bool
+	defaultReadyFuncs             []fsmtypes.CustomResourceReadyFunc
+	readyEvents                   bool
+	reconcileReadCache            bool
+	propagateChildReadiness       bool
+	readyConditionType            api.ConditionType
+	rateLimiterOpts               []ratelimiter.ManagedRateLimiterOption
+
+	// errs are the configuration errors accumulated by builder methods, returned by Validate
+	errs []error
 
 	// skipNameValidation is used to skip name validation for the controller,
 	// should only be used for testing purposes.
@@ -73,6 +116,8 @@ type Builder[T any, Obj apitypes.FSMResource[T]] struct {
 type managedType struct {
 	gvk        schema.GroupVersionKind
 	predicates ctrlbuilder.Predicates
+	// resyncPeriod, if positive, is the interval at which all objects of the type are resynced
+	resyncPeriod time.Duration
 }
 
 type watch struct {
@@ -90,6 +135,38 @@ type watchRemoteKind struct {
 	triggerType fsmhandler.TriggerType
 }
 
+type eventChannel struct {
+	ch          <-chan event.GenericEvent
+	mapFn       func(client.Object) []reconcile.Request
+	triggerType fsmhandler.TriggerType
+}
+
+// source returns a channel source whose events are mapped to requests and wrapped with the FSM observed event handler.
+// The source stops once the channel is closed.
+func (e eventChannel) source(
+	log *zap.SugaredLogger,
+	scheme *runtime.Scheme,
+	name string,
+	metrics *metrics.Metrics,
+	opts ...fsmhandler.ObservedEventHandlerOption,
+) source.Source {
+	mapFn := e.mapFn
+	return source.Channel(
+		e.ch,
+		fsmhandler.NewObservedEventHandler(
+			log,
+			scheme,
+			name,
+			metrics,
+			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+				return mapFn(o)
+			}),
+			e.triggerType,
+			opts...,
+		),
+	)
+}
+
 // NewBuilder returns a builder that builds a function wiring up a logical FSM controller to a manager.
 // Obj is the object being reconciled and initialState is the initial state in the finite state machine,
 func NewBuilder[T any, Obj apitypes.FSMResource[T]](
@@ -113,7 +190,7 @@ func (b *Builder[T, Obj]) Manages(
 		if b.scheme.Recognizes(gvk) {
 			b.managedTypes = append(b.managedTypes, managedType{gvk: gvk})
 		} else {
-			panic(fmt.Sprintf("%s is not registered with runtime scheme", gvk))
+			b.errs = append(b.errs, unregisteredTypeError(gvk))
 		}
 	}
 	return b
@@ -130,11 +207,51 @@ func (b *Builder[T, Obj]) ManagesWithPredicate(
 			predicates: ctrlbuilder.WithPredicates(predicates...),
 		})
 	} else {
-		panic(fmt.Sprintf("%s is not registered with runtime scheme", gvk))
+		b.errs = append(b.errs, unregisteredTypeError(gvk))
+	}
+	return b
+}
+
+// ManagesWithResync adds a managed resource type to the controller whose objects are resynced every resyncPeriod, i.e.
+// their owners are enqueued for reconciliation, independently of the manager's SyncPeriod. This is useful for types
+// frequently changed out-of-band, whose drift should be corrected more promptly than other types.
+//
+// The manager's shared cache has a single sync period for all informers, so rather than configuring a dedicated cache,
+// objects of the type are periodically replayed from the shared cache, which doesn't incur requests to the kube-apiserver.
+// Replayed objects bypass the predicates, which only apply to watch events.
+func (b *Builder[T, Obj]) ManagesWithResync(
+	gvk schema.GroupVersionKind,
+	resyncPeriod time.Duration,
+	predicates ...predicate.Predicate,
+) *Builder[T, Obj] {
+	if !b.scheme.Recognizes(gvk) {
+		b.errs = append(b.errs, unregisteredTypeError(gvk))
+		return b
+	}
+	if resyncPeriod <= 0 {
+		b.errs = append(b.errs, fmt.Errorf("resync period %s of managed type %s must be positive", resyncPeriod, gvk))
+		return b
 	}
+	b.managedTypes = append(b.managedTypes, managedType{
+		gvk:          gvk,
+		predicates:   ctrlbuilder.WithPredicates(predicates...),
+		resyncPeriod: resyncPeriod,
+	})
 	return b
 }
 
+// Validate returns an error if the builder is misconfigured, e.g. if a managed type isn't registered with the scheme.
+// Build's SetupFunc returns the same error, failing the controller's setup, so calling Validate is only necessary
+// for surfacing misconfigurations earlier (e.g. in unit tests).
+func (b *Builder[T, Obj]) Validate() error {
+	return errors.Join(b.errs...)
+}
+
+// unregisteredTypeError returns the error for a managed type that isn't registered with the scheme.
+func unregisteredTypeError(gvk schema.GroupVersionKind) error {
+	return fmt.Errorf("managed type %s is not registered with runtime scheme", gvk)
+}
+
 // WithControllerHandle adds a ControllerFunc.
 func (b *Builder[T, Obj]) WithControllerHandle(fn ControllerFunc) *Builder[T, Obj] {
 	b.controllerFns = append(b.controllerFns, fn)
@@ -148,12 +265,229 @@ func (b *Builder[T, Obj]) WithFinalizerState(state *fsmtypes.State[Obj]) *Builde
 	return b
 }
 
+// WithName overrides the controller name, which otherwise defaults to the kebab-cased kind of the reconciled object.
+// The name is used for the controller, its logger, managed resource labels, and the "controller" label of metrics.
+// This is useful for running multiple controllers for the same kind.
+func (b *Builder[T, Obj]) WithName(name string) *Builder[T, Obj] {
+	b.name = name
+	return b
+}
+
 // WithReconcilerOptions sets reconciler options.
 func (b *Builder[T, Obj]) WithReconcilerOptions(reconcilerOptions fsmtypes.ReconcilerOptions[T, Obj]) *Builder[T, Obj] {
 	b.reconcilerOptions = reconcilerOptions
 	return b
 }
 
+// WithoutRedditLabels disables application of the standard Reddit labels on managed resources of the given types.
+// This is useful for resource types that reject unknown labels. Owner references are still applied.
+func (b *Builder[T, Obj]) WithoutRedditLabels(gvks ...schema.GroupVersionKind) *Builder[T, Obj] {
+	for _, gvk := range gvks {
+		b.WithRedditLabelKeys(gvk)
+	}
+	return b
+}
+
+// WithRedditLabelKeys restricts the standard Reddit labels applied on managed resources of the given type to the listed label keys.
+// If no keys are provided, Reddit labels are not applied to the given type. Owner references are still applied.
+func (b *Builder[T, Obj]) WithRedditLabelKeys(gvk schema.GroupVersionKind, keys ...string) *Builder[T, Obj] {
+	if b.redditLabelKeys == nil {
+		b.redditLabelKeys = map[schema.GroupVersionKind][]string{}
+	}
+	b.redditLabelKeys[gvk] = keys
+	return b
+}
+
+// WithManagedResourceRefGracePeriod retains managed resource refs in status for the given duration after their objects
+// are first observed missing, rather than pruning them immediately. This is useful for managed resources that may be
+// temporarily absent, e.g. due to eventual consistency of a remote cache. Refs for objects explicitly deleted
+// by the controller are pruned immediately. Objects retaining refs are requeued once the grace period elapses,
+// so that the refs are pruned on time.
+func (b *Builder[T, Obj]) WithManagedResourceRefGracePeriod(gracePeriod time.Duration) *Builder[T, Obj] {
+	b.managedResourceRefGracePeriod = gracePeriod
+	return b
+}
+
+// WithMaxStatesPerReconcile limits the number of states executed in a single reconcile. Once the limit is reached,
+// the reconciler requeues immediately and resumes from the following state, unless the object's generation changed in the interim.
+// Paused reconciles leave the object's status conditions unchanged, so its readiness doesn't flap while resuming.
+// This bounds reconcile latency and load on the kube-apiserver for FSMs with many states. Values <= 0 disable the limit.
+func (b *Builder[T, Obj]) WithMaxStatesPerReconcile(n int) *Builder[T, Obj] {
+	b.maxStatesPerReconcile = n
+	return b
+}
+
+// WithMaxConditionMessageLength truncates the messages of status conditions written by the reconciler to n characters,
+// followed by an ellipsis, so that long error messages don't bloat the object in etcd. Values <= 0 disable truncation.
+func (b *Builder[T, Obj]) WithMaxConditionMessageLength(n int) *Builder[T, Obj] {
+	b.maxConditionMessageLength = n
+	return b
+}
+
+// WithMinRequeueInterval raises the duration of requeues requested by transitions to at least the given interval,
+// guarding against tight requeue loops when durations are computed from external data and end up zero or negative.
+// Requeues with exponential backoff (e.g. RequeueResultWithBackoff) and the requeues resuming reconciles paused by
+// WithMaxStatesPerReconcile are unaffected. Values <= 0 disable the floor.
+func (b *Builder[T, Obj]) WithMinRequeueInterval(interval time.Duration) *Builder[T, Obj] {
+	b.minRequeueInterval = interval
+	return b
+}
+
+// WithTransientErrorGracePeriod leaves the reconciled object's status conditions unchanged while its reconciles fail with transient errors,
+// e.g. DNS or connection failures reaching the kube-apiserver, for up to the given duration. Such reconciles are still requeued with backoff.
+// Errors persisting beyond the grace period are surfaced on status conditions. Values <= 0 surface transient errors immediately.
+func (b *Builder[T, Obj]) WithTransientErrorGracePeriod(gracePeriod time.Duration) *Builder[T, Obj] {
+	b.transientErrorGracePeriod = gracePeriod
+	return b
+}
+
+// WithReconcileFilter restricts reconciliation to objects matching the filter, e.g. objects in certain namespaces or with
+// certain labels, allowing objects to be sharded across controller deployments. Unlike the suspend label, the filter is
+// configured by the controller rather than on the objects. Objects that start matching the filter are reconciled,
+// while objects that stop matching it are no longer reconciled and their metrics are deleted.
+func (b *Builder[T, Obj]) WithReconcileFilter(filter fsmtypes.ReconcileFilter) *Builder[T, Obj] {
+	b.reconcileFilter = &filter
+	return b
+}
+
+// WithForPredicate filters events on the reconciled object with the given predicate, without affecting the watches of managed
+// resources or other watched types (unlike WithEventFilter, which applies to all watches). Multiple predicates must all
+// admit an event. Triggers are only observed (i.e. logged and recorded in the trigger metric) for admitted events.
+func (b *Builder[T, Obj]) WithForPredicate(predicate predicate.Predicate) *Builder[T, Obj] {
+	b.forEventPredicates = append(b.forEventPredicates, predicate)
+	return b
+}
+
+// WithLogTraceIDs adds the trace and span IDs returned by traceIDs to all logs of a reconcile, alongside the reconcile ID,
+// correlating logs with traces when tracing is enabled. Trace IDs are omitted from logs if the reconcile context doesn't carry a span.
+func (b *Builder[T, Obj]) WithLogTraceIDs(traceIDs logging.TraceIDsFunc) *Builder[T, Obj] {
+	b.traceIDsFunc = traceIDs
+	return b
+}
+
+// WithCustomMetricsRegisterer enables transitions to record user-defined metrics through metrics.CustomMetricsFromContext,
+// registered with the given registerer (e.g. sigs.k8s.io/controller-runtime/pkg/metrics.Registry).
+// Custom metrics are labeled with the group, version, and kind of the reconciled object.
+func (b *Builder[T, Obj]) WithCustomMetricsRegisterer(registerer prometheus.Registerer) *Builder[T, Obj] {
+	b.customMetricsRegisterer = registerer
+	return b
+}
+
+// WithPriorityFunc configures the controller with a priority queue, such that under backlog, objects of higher priority
+// (as returned by priorityFunc) are reconciled before those of lower priority. Objects of equal priority are reconciled
+// in the order in which they were enqueued. Objects that can't be fetched from the cache, e.g. because they were deleted,
+// have priority 0.
+func (b *Builder[T, Obj]) WithPriorityFunc(priorityFunc func(obj client.Object) int) *Builder[T, Obj] {
+	b.priorityFunc = priorityFunc
+	return b
+}
+
+// WithoutDefaultOwnerRefs prevents the controller reference to the reconciled object from being set by default on all
+// managed resources, e.g. for controllers managing shared infrastructure that must not be garbage collected along with the reconciled object.
+// Owner references explicitly requested for an output (e.g. through io.WithOwnerRef or io.WithControllerRef) are still set.
+func (b *Builder[T, Obj]) WithoutDefaultOwnerRefs() *Builder[T, Obj] {
+	b.withoutDefaultOwnerRefs = true
+	return b
+}
+
+// WithVersionAnnotation records the given controller version (e.g. its build version) on the reconciled object and its
+// managed resources in the "infrared.reddit.com/reconciled-by-version" annotation, for identifying which build of the
+// controller last reconciled them. Managed resources aren't patched solely to update the annotation, so it's updated
+// whenever they're otherwise changed.
+func (b *Builder[T, Obj]) WithVersionAnnotation(version string) *Builder[T, Obj] {
+	b.versionAnnotation = version
+	return b
+}
+
+// WithDefaultReadyFuncs registers custom resource readiness checks (e.g. for CRDs commonly managed by the controller)
+// that are applied by fsmtypes.GetUnreadyResources and fsmtypes.TransitionWhenReady in all transitions of this controller.
+// Readiness checks passed per call augment, rather than replace, the defaults.
+func (b *Builder[T, Obj]) WithDefaultReadyFuncs(funcs ...fsmtypes.CustomResourceReadyFunc) *Builder[T, Obj] {
+	b.defaultReadyFuncs = append(b.defaultReadyFuncs, funcs...)
+	return b
+}
+
+// WithReconcileReadCache memoizes Gets through io.ClientApplicators for the duration of each reconcile, so that
+// objects read in multiple states are only fetched once. Writes through the ClientApplicator invalidate the written object,
+// while changes made by other actors are observed by the next reconcile.
+func (b *Builder[T, Obj]) WithReconcileReadCache() *Builder[T, Obj] {
+	b.reconcileReadCache = true
+	return b
+}
+
+// WithReadyEvents records a "Ready" event whenever the reconciled object's ready condition transitions to True.
+// Reconciles leaving the object ready don't record further events, while objects flapping between ready and unready
+// record an event upon each transition to ready.
+// Events are recorded with the manager's event recorder, so this only applies to controllers set up through Build.
+// For reconcilers constructed otherwise, set fsmtypes.ReconcilerOptions.ReadyEventRecorder.
+func (b *Builder[T, Obj]) WithReadyEvents() *Builder[T, Obj] {
+	b.readyEvents = true
+	return b
+}
+
+// WithPauseSwitch pauses reconciliation while the given switch is true, e.g. for maintenance windows toggled at runtime.
+// Reconciles of a paused controller complete without changes and aren't requeued. Objects are reconciled again upon their
+// next event or resync once the switch is set to false.
+func (b *Builder[T, Obj]) WithPauseSwitch(paused *atomic.Bool) *Builder[T, Obj] {
+	b.pauseSwitch = paused
+	return b
+}
+
+// WithShadowApplicator additionally applies outputs to the given client on a best-effort basis, e.g. for mirroring
+// managed resources to a secondary cluster. Deleted outputs are also deleted from the shadow. Shadow failures are logged
+// and don't fail the reconcile. Shadow objects don't have owner references, since their owners only exist in the primary cluster.
+func (b *Builder[T, Obj]) WithShadowApplicator(c client.Client) *Builder[T, Obj] {
+	b.shadowClient = c
+	return b
+}
+
+// WithReadyConditionType sets the type of the top-level status condition rolling up all other status conditions,
+// for APIs whose canonical readiness condition isn't "Ready" (e.g. "Available"). The readiness metric reports the
+// condition of this type. Defaults to "Ready".
+func (b *Builder[T, Obj]) WithReadyConditionType(t api.ConditionType) *Builder[T, Obj] {
+	b.readyConditionType = t
+	return b
+}
+
+// WithChildReadinessPropagation surfaces the cause of non-ready managed resources on the reconciled object's "Ready"
+// status condition. When the object isn't ready, the reason and message of its most severe non-ready managed resource
+// are appended to the condition's message.
+func (b *Builder[T, Obj]) WithChildReadinessPropagation() *Builder[T, Obj] {
+	b.propagateChildReadiness = true
+	return b
+}
+
+// WithTriggerLogWindow coalesces debug logs of identical event triggers received within the given window into a single log
+// with the number of triggers, reducing log volume when bursts of events on managed resources enqueue the same object.
+// Triggers enqueueing different objects are logged separately. Values <= 0 log every trigger.
+func (b *Builder[T, Obj]) WithTriggerLogWindow(window time.Duration) *Builder[T, Obj] {
+	b.triggerLogWindow = window
+	return b
+}
+
+// WithIgnoreChildTriggers skips reconciles triggered by events on managed resources for reconciled objects annotated with
+// `infrared.reddit.com/ignore-child-triggers: "true"` (see handler.IgnoreChildTriggersAnnotationKey). Annotated objects still
+// reconcile on events on themselves and through periodic resyncs. If enqueueOnDelete is true, deletions of managed
+// resources still trigger reconciles of annotated objects, e.g. so that deleted managed resources are recreated.
+func (b *Builder[T, Obj]) WithIgnoreChildTriggers(enqueueOnDelete bool) *Builder[T, Obj] {
+	b.ignoreChildTriggers = true
+	b.enqueueChildDeletions = enqueueOnDelete
+	return b
+}
+
+// WithUnservableTypeDetection checks every interval, through discovery, that the types managed by the controller are
+// served by the kube-apiserver. Types that stop being served at runtime, e.g. because their CRD was deleted, are
+// reported through a rate-limited error log and the "achilles_managed_type_unservable" metric, rather than only through
+// continuously erroring watches and opaquely failing reconciles. Types are only considered unservable once several
+// consecutive checks report them not served, so that transient discovery blips are tolerated.
+// If failFast is true, the manager is stopped with an error once a managed type is unservable, e.g. so that the
+// controller's process exits rather than running degraded. Values <= 0 disable detection.
+func (b *Builder[T, Obj]) WithUnservableTypeDetection(interval time.Duration, failFast bool) *Builder[T, Obj] {
+	b.unservableTypeCheckInterval = interval
+	b.unservableTypeFailFast = failFast
+	return b
+}
+
 // WithMaxConcurrentReconciles sets the maxConcurrentReconciles option for controller-runtime. Defaults to 1 if not specified or when a value <= 0 is passed.
 // controller-runtime ensures a single object is not reconciled by multiple reconcilers concurrently. If your controller manages global state (e.g. caches attached to the controller struct), you need to ensure it is thread safe before increasing the concurrency.
 func (b *Builder[T, Obj]) WithMaxConcurrentReconciles(maxConcurrentReconciles int) *Builder[T, Obj] {
@@ -161,6 +495,21 @@ func (b *Builder[T, Obj]) WithMaxConcurrentReconciles(maxConcurrentReconciles in
 	return b
 }
 
+// WithBackoff sets the base and maximum delay of the per-item exponential backoff of failed reconciles, e.g. to start with
+// a larger base delay for controllers talking to slow external providers. Defaults to a base of 1s and a maximum of 60s.
+// The base delay must be positive and not exceed the maximum delay.
+func (b *Builder[T, Obj]) WithBackoff(baseDelay, maxDelay time.Duration) *Builder[T, Obj] {
+	if baseDelay <= 0 || baseDelay > maxDelay {
+		b.errs = append(b.errs, fmt.Errorf("backoff base delay %s must be positive and not exceed max delay %s", baseDelay, maxDelay))
+		return b
+	}
+	b.rateLimiterOpts = []ratelimiter.ManagedRateLimiterOption{
+		ratelimiter.WithBackoffBaseDelay(baseDelay),
+		ratelimiter.WithBackoffMaxDelay(maxDelay),
+	}
+	return b
+}
+
 // Watches adds a custom watch to the controller.
 func (b *Builder[T, Obj]) Watches(
 	object client.Object,
@@ -177,6 +526,12 @@ func (b *Builder[T, Obj]) Watches(
 	return b
 }
 
+// OnDataChange is a Watches option for watches on ConfigMaps or Secrets that triggers reconciles only when their data changes,
+// not when only their metadata does (e.g. labels or annotations). Creations and deletions always trigger reconciles.
+func OnDataChange() ctrlbuilder.WatchesOption {
+	return ctrlbuilder.WithPredicates(fsmhandler.DataChangedPredicate())
+}
+
 // WatchesRemoteKind adds a new watch to the controller for a specific kind located in a remote cluster.
 // The remote cluster is specified through cache.Cache.
 func (b *Builder[T, Obj]) WatchesRemoteKind(
@@ -205,6 +560,22 @@ func (b *Builder[T, Obj]) WatchesRawSource(src source.Source) *Builder[T, Obj] {
 	return b
 }
 
+// WithEventChannel adds a new watch to the controller for events originating outside the cluster (e.g. webhooks or pubsub),
+// delivered through the supplied channel. Each event's object is mapped to reconcile requests through mapFn.
+// Unlike WatchesRawSource, the event handler is wrapped with the FSM handler. The watch stops once the channel is closed.
+func (b *Builder[T, Obj]) WithEventChannel(
+	ch <-chan event.GenericEvent,
+	mapFn func(client.Object) []reconcile.Request,
+	triggerType fsmhandler.TriggerType,
+) *Builder[T, Obj] {
+	b.eventChannels = append(b.eventChannels, eventChannel{
+		ch:          ch,
+		mapFn:       mapFn,
+		triggerType: triggerType,
+	})
+	return b
+}
+
 // WithEventFilter adds a custom event filter to the controller.
 func (b *Builder[T, Obj]) WithEventFilter(
 	predicate predicate.Predicate,
@@ -213,6 +584,20 @@ func (b *Builder[T, Obj]) WithEventFilter(
 	return b
 }
 
+// WithNoPeriodicResync prevents objects from being reconciled on the periodic resync of the controller's informers
+// (see the manager's SyncPeriod), for batch-style controllers that reconcile each object until done and then idle.
+// Reconciles are still triggered by creations, deletions, and genuine changes of the reconciled object and its watched
+// resources, as well as by requeues requested by the FSM. Resyncs of raw sources (e.g. WatchesRemoteKind) are not filtered.
+func (b *Builder[T, Obj]) WithNoPeriodicResync() *Builder[T, Obj] {
+	b.opts = append(b.opts, withEventFilter(noPeriodicResyncPredicate()))
+	return b
+}
+
+// noPeriodicResyncPredicate drops update events emitted by informer resyncs, which are identified by an unchanged resource version.
+func noPeriodicResyncPredicate() predicate.Predicate {
+	return predicate.ResourceVersionChangedPredicate{}
+}
+
 // WithSkipNameValidation allows the caller to skip name validation for the controller.
 // This is useful for testing purposes.
 func (b *Builder[T, Obj]) WithSkipNameValidation() *Builder[T, Obj] {
@@ -227,8 +612,17 @@ func (b *Builder[T, Obj]) Reconciler(
 	c client.Client,
 	metrics *metrics.Metrics,
 ) reconcile.TypedReconciler[ctrl.Request] {
-	objGVK := meta.MustTypedObjectRefFromObject(b.obj, scheme)
-	name := strcase.ToKebab(objGVK.Kind)
+	return b.reconciler(log, scheme, c, metrics, nil)
+}
+
+func (b *Builder[T, Obj]) reconciler(
+	log *zap.SugaredLogger,
+	scheme *runtime.Scheme,
+	c client.Client,
+	metrics *metrics.Metrics,
+	eventRecorder *events.EventRecorder,
+) internal.Reconciler {
+	name := b.controllerName(scheme)
 	log = log.Named(name)
 
 	clientApplicator := &io.ClientApplicator{
@@ -236,9 +630,12 @@ func (b *Builder[T, Obj]) Reconciler(
 		Applicator: io.NewAPIPatchingApplicator(c),
 	}
 
-	managedGVKs := make([]schema.GroupVersionKind, len(b.managedTypes))
-	for i, managedType := range b.managedTypes {
-		managedGVKs[i] = managedType.gvk
+	opts := b.buildReconcilerOptions()
+	if eventRecorder != nil {
+		if b.readyEvents {
+			opts.ReadyEventRecorder = eventRecorder
+		}
+		opts.RequeueEventRecorder = eventRecorder
 	}
 
 	return internal.NewFSMReconciler(
@@ -248,10 +645,241 @@ func (b *Builder[T, Obj]) Reconciler(
 		scheme,
 		b.initialState,
 		b.finalizerState,
-		managedGVKs,
+		b.managedGVKs(),
 		metrics,
-		b.reconcilerOptions,
+		opts,
+	)
+}
+
+// StateResult is the result of evaluating a single FSM state.
+type StateResult struct {
+	// State is the name of the state.
+	State string
+	// Result is the result returned by the state's transition.
+	Result fsmtypes.Result
+	// Outputs is the output set populated by the state's transition. Outputs are never applied.
+	Outputs *fsmtypes.OutputSet
+	// Condition is the state's status condition as it would be set by a reconcile, empty if the state has no condition
+	// or the reconcile was skipped.
+	Condition api.Condition
+}
+
+// EvaluateStates performs a dry evaluation of the FSM against obj, returning the sequence of states entered and their results.
+// States are evaluated as they are in a reconcile, starting from the initial state (or the finalizer state if obj is deleted)
+// and stopping at the first state that doesn't complete, requests a requeue, or skips the reconcile. obj isn't mutated.
+//
+// Unlike a reconcile, outputs are not applied, status is not updated, and the maximum number of states per reconcile is ignored.
+// Transitions are executed in a read-only context (see io.NewReadOnlyContext), in which writes through an io.ClientApplicator
+// fail without reaching the kube-apiserver, so transitions should read through a ClientApplicator over a snapshot of the
+// cluster (e.g. a fake client). Transitions with side effects beyond the OutputSet, such as writing directly through
+// a client other than a ClientApplicator, are unsupported, as those writes are performed as usual.
+func (b *Builder[T, Obj]) EvaluateStates(ctx context.Context, obj Obj) ([]StateResult, error) {
+	obj, ok := obj.DeepCopyObject().(Obj)
+	if !ok {
+		return nil, fmt.Errorf("copying %T", obj)
+	}
+
+	// custom metrics aren't recorded for dry evaluations
+	opts := b.buildReconcilerOptions()
+	opts.MetricsOptions.CustomMetricsRegisterer = nil
+
+	// outputs aren't applied, so the reconciler's client is never used
+	r := internal.NewFSMReconciler(
+		b.controllerName(b.scheme),
+		zap.NewNop().Sugar(),
+		nil,
+		b.scheme,
+		b.initialState,
+		b.finalizerState,
+		b.managedGVKs(),
+		&metrics.Metrics{},
+		opts,
 	)
+
+	evaluated, err := r.Evaluate(ctx, obj)
+	results := make([]StateResult, len(evaluated))
+	for i, e := range evaluated {
+		results[i] = StateResult{State: e.State, Result: e.Result, Outputs: e.Outputs, Condition: e.Condition}
+	}
+	return results, err
+}
+
+// managedGVKs returns the GVKs of the managed resource types.
+func (b *Builder[T, Obj]) managedGVKs() []schema.GroupVersionKind {
+	gvks := make([]schema.GroupVersionKind, len(b.managedTypes))
+	for i, managedType := range b.managedTypes {
+		gvks[i] = managedType.gvk
+	}
+	return gvks
+}
+
+// controllerName returns the name of the controller, defaulting to the kebab-cased kind of the reconciled object.
+func (b *Builder[T, Obj]) controllerName(scheme *runtime.Scheme) string {
+	if b.name != "" {
+		return b.name
+	}
+	return strcase.ToKebab(meta.MustGVKForObject(b.obj, scheme).Kind)
+}
+
+// buildReconcilerOptions returns the reconciler options merged with options set through builder methods.
+func (b *Builder[T, Obj]) buildReconcilerOptions() fsmtypes.ReconcilerOptions[T, Obj] {
+	opts := b.reconcilerOptions
+
+	if len(b.redditLabelKeys) > 0 {
+		redditLabelKeys := make(map[schema.GroupVersionKind][]string, len(opts.RedditLabelKeys)+len(b.redditLabelKeys))
+		for gvk, keys := range opts.RedditLabelKeys {
+			redditLabelKeys[gvk] = keys
+		}
+		for gvk, keys := range b.redditLabelKeys {
+			redditLabelKeys[gvk] = keys
+		}
+		opts.RedditLabelKeys = redditLabelKeys
+	}
+
+	if b.managedResourceRefGracePeriod > 0 {
+		opts.ManagedResourceRefGracePeriod = b.managedResourceRefGracePeriod
+	}
+
+	if b.maxStatesPerReconcile > 0 {
+		opts.MaxStatesPerReconcile = b.maxStatesPerReconcile
+	}
+
+	if b.maxConditionMessageLength > 0 {
+		opts.MaxConditionMessageLength = b.maxConditionMessageLength
+	}
+
+	if b.minRequeueInterval > 0 {
+		opts.MinRequeueInterval = b.minRequeueInterval
+	}
+
+	if b.transientErrorGracePeriod > 0 {
+		opts.TransientErrorGracePeriod = b.transientErrorGracePeriod
+	}
+
+	if b.reconcileFilter != nil {
+		opts.ReconcileFilter = b.reconcileFilter
+	}
+
+	if b.traceIDsFunc != nil {
+		opts.TraceIDsFunc = b.traceIDsFunc
+	}
+
+	if b.customMetricsRegisterer != nil {
+		opts.MetricsOptions.CustomMetricsRegisterer = b.customMetricsRegisterer
+	}
+
+	if b.withoutDefaultOwnerRefs {
+		opts.WithoutDefaultOwnerRefs = true
+	}
+
+	if b.versionAnnotation != "" {
+		opts.VersionAnnotation = b.versionAnnotation
+	}
+	if b.pauseSwitch != nil {
+		opts.PauseSwitch = b.pauseSwitch
+	}
+	if len(b.defaultReadyFuncs) > 0 {
+		opts.DefaultReadyFuncs = b.defaultReadyFuncs
+	}
+	if b.reconcileReadCache {
+		opts.ReconcileReadCache = true
+	}
+
+	if b.shadowClient != nil {
+		opts.ShadowApplicator = &io.ClientApplicator{
+			Client:     b.shadowClient,
+			Applicator: io.NewAPIPatchingApplicator(b.shadowClient),
+		}
+	}
+
+	if b.propagateChildReadiness {
+		opts.PropagateChildReadiness = true
+	}
+
+	if b.readyConditionType != "" {
+		opts.ReadyConditionType = b.readyConditionType
+	}
+
+	return opts
+}
+
+// forPredicates returns the predicates for events on the reconciled object.
+func (b *Builder[T, Obj]) forPredicates(
+	log *zap.SugaredLogger,
+	scheme *runtime.Scheme,
+	name string,
+	metrics *metrics.Metrics,
+) []predicate.Predicate {
+	var predicates []predicate.Predicate
+	if b.reconcileFilter != nil {
+		// evaluated first so that triggers are only observed for objects matching the filter
+		predicates = append(predicates, reconcileFilterPredicate(*b.reconcileFilter))
+	}
+	predicates = append(predicates, b.forEventPredicates...)
+	return append(predicates, fsmhandler.NewForObservePredicate(log, scheme, name, metrics))
+}
+
+// reconcileFilterPredicate drops events for objects not matching the filter. Updates are admitted if either the old or
+// new object matches, so that objects that stop matching the filter are reconciled once more for their metrics to be deleted.
+func reconcileFilterPredicate(filter fsmtypes.ReconcileFilter) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return filter.Matches(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return filter.Matches(e.ObjectOld) || filter.Matches(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return filter.Matches(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return filter.Matches(e.Object)
+		},
+	}
+}
+
+// newQueue returns a constructor for the controller's workqueue, or nil for controller-runtime's default workqueue
+// if no priority func is configured.
+func (b *Builder[T, Obj]) newQueue(
+	c client.Reader,
+) func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	if b.priorityFunc == nil {
+		return nil
+	}
+	return func(controllerName string, rl workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		return internal.NewPriorityQueue(controllerName, rl, func(req reconcile.Request) int {
+			obj := Obj(new(T))
+			if err := c.Get(context.Background(), req.NamespacedName, obj); err != nil {
+				return 0
+			}
+			return b.priorityFunc(obj)
+		})
+	}
+}
+
+// withCoalescedQueue instruments the controller's workqueue with the "achilles_trigger_coalesced_total" metric.
+// The workqueue constructed by opts.NewQueue is wrapped if set, else controller-runtime's default workqueue.
+func withCoalescedQueue(opts controller.Options, mgr ctrl.Manager, metrics *metrics.Metrics) controller.Options {
+	newQueue := opts.NewQueue
+	usePriorityQueue := ptr.Deref(mgr.GetControllerOptions().UsePriorityQueue, false)
+	opts.NewQueue = func(controllerName string, rl workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		var q workqueue.TypedRateLimitingInterface[reconcile.Request]
+		switch {
+		case newQueue != nil:
+			q = newQueue(controllerName, rl)
+		case usePriorityQueue:
+			q = priorityqueue.New(controllerName, func(o *priorityqueue.Opts[reconcile.Request]) {
+				o.RateLimiter = rl
+			})
+		default:
+			// equivalent to controller-runtime's default workqueue
+			q = workqueue.NewTypedRateLimitingQueueWithConfig(rl, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+				Name: controllerName,
+			})
+		}
+		return internal.NewCoalescedQueue(q, func() { metrics.RecordTriggerCoalesced(controllerName) })
+	}
+	return opts
 }
 
 func (b *Builder[T, Obj]) Build() SetupFunc {
@@ -261,9 +889,13 @@ func (b *Builder[T, Obj]) Build() SetupFunc {
 		rl workqueue.TypedRateLimiter[reconcile.Request],
 		metrics *metrics.Metrics,
 	) error {
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("validating controller: %w", err)
+		}
+
 		scheme := mgr.GetScheme()
 		objGVK := meta.MustTypedObjectRefFromObject(b.obj, scheme)
-		name := strcase.ToKebab(objGVK.Kind)
+		name := b.controllerName(scheme)
 		log = log.Named(name)
 
 		c := &io.ClientApplicator{
@@ -271,21 +903,32 @@ func (b *Builder[T, Obj]) Build() SetupFunc {
 			Applicator: io.NewAPIPatchingApplicator(mgr.GetClient()),
 		}
 
-		managedGVKs := make([]schema.GroupVersionKind, len(b.managedTypes))
-		for i, managedType := range b.managedTypes {
-			managedGVKs[i] = managedType.gvk
-		}
+		managedGVKs := b.managedGVKs()
+
+		// records ready events (if enabled) and the events of requeue results signaling an event
+		r := b.reconciler(log, scheme, c, metrics, events.NewEventRecorder(name, mgr, metrics))
 
-		r := b.Reconciler(log, scheme, c, metrics)
+		handlerOpts := []fsmhandler.ObservedEventHandlerOption{fsmhandler.WithTriggerLogWindow(b.triggerLogWindow)}
+		ownerHandlerOpts := []fsmhandler.ObservedEventHandlerOption{fsmhandler.WithTriggerLogWindow(b.triggerLogWindow)}
+		if b.ignoreChildTriggers {
+			newObj := func() client.Object { return Obj(new(T)) }
+			ownerHandlerOpts = append(ownerHandlerOpts, fsmhandler.WithIgnoreChildTriggers(mgr.GetClient(), newObj, b.enqueueChildDeletions))
+		}
 
 		builder := ctrl.NewControllerManagedBy(mgr).
-			WithOptions(controller.Options{
+			WithOptions(withCoalescedQueue(controller.Options{
 				SkipNameValidation:      ptr.To(b.skipNameValidation),
-				RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(rl),
+				RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(rl, b.rateLimiterOpts...),
 				MaxConcurrentReconciles: b.maxConcurrentReconciles,
-			}).
+				NewQueue:                b.newQueue(mgr.GetClient()),
+			}, mgr, metrics)).
 			// equivalent to calling `builder.For` but uses an event handler that debug logs the event trigger
-			For(b.obj, ctrlbuilder.WithPredicates(fsmhandler.NewForObservePredicate(log, scheme, name, metrics)))
+			For(b.obj, ctrlbuilder.WithPredicates(b.forPredicates(log, scheme, name, metrics)...))
+
+		// only override controller-runtime's default controller name (the lowercased kind) if explicitly requested
+		if b.name != "" {
+			builder.Named(b.name)
+		}
 
 		// watch managed types
 		for _, managedType := range b.managedTypes {
@@ -294,19 +937,24 @@ func (b *Builder[T, Obj]) Build() SetupFunc {
 			if err != nil {
 				return fmt.Errorf("constructing new object of type %s: %s", gvk, err)
 			}
+			ownerHandler := fsmhandler.NewObservedEventHandler(log, scheme, name, metrics, handler.EnqueueRequestForOwner(scheme, mgr.GetRESTMapper(), b.obj, handler.OnlyControllerOwner()), fsmhandler.TriggerTypeChild, ownerHandlerOpts...)
 			// equivalent to calling `builder.Owns` but uses an event handler that debug logs the event trigger
-			builder.Watches(
-				o,
-				fsmhandler.NewObservedEventHandler(log, scheme, name, metrics, handler.EnqueueRequestForOwner(scheme, mgr.GetRESTMapper(), b.obj, handler.OnlyControllerOwner()), fsmhandler.TriggerTypeChild),
-				managedType.predicates,
-			)
+			builder.Watches(o, ownerHandler, managedType.predicates)
+
+			if managedType.resyncPeriod > 0 {
+				src, err := newResyncSource(log, scheme, mgr.GetClient(), gvk, managedType.resyncPeriod, ownerHandler)
+				if err != nil {
+					return err
+				}
+				builder.WatchesRawSource(src)
+			}
 		}
 
 		// wire up custom watches
 		for _, w := range b.watches {
 			builder.Watches(
 				w.object,
-				fsmhandler.NewObservedEventHandler(log, scheme, name, metrics, w.handler, w.triggerType),
+				fsmhandler.NewObservedEventHandler(log, scheme, name, metrics, w.handler, w.triggerType, handlerOpts...),
 				w.opts...,
 			)
 		}
@@ -315,7 +963,7 @@ func (b *Builder[T, Obj]) Build() SetupFunc {
 			src := source.Kind(
 				w.cache,
 				w.obj,
-				fsmhandler.NewObservedEventHandler(log, scheme, name, metrics, w.handler, w.triggerType),
+				fsmhandler.NewObservedEventHandler(log, scheme, name, metrics, w.handler, w.triggerType, handlerOpts...),
 				w.predicates...,
 			)
 
@@ -326,6 +974,13 @@ func (b *Builder[T, Obj]) Build() SetupFunc {
 			builder.WatchesRawSource(w)
 		}
 
+		for _, e := range b.eventChannels {
+			builder.WatchesRawSource(e.source(log, scheme, name, metrics, handlerOpts...))
+		}
+
+		// enqueues objects requeued through OutputSet.RequeueRef
+		builder.WatchesRawSource(r.RequeueSource())
+
 		// custom controller builder options
 		for _, opt := range b.opts {
 			opt(builder)
@@ -341,6 +996,17 @@ func (b *Builder[T, Obj]) Build() SetupFunc {
 			fn(con)
 		}
 
+		if b.unservableTypeCheckInterval > 0 && len(managedGVKs) > 0 {
+			discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+			if err != nil {
+				return fmt.Errorf("constructing discovery client: %w", err)
+			}
+			detector := newUnservableTypeDetector(log, discoveryClient, name, managedGVKs, metrics, b.unservableTypeCheckInterval, b.unservableTypeFailFast)
+			if err := mgr.Add(detector); err != nil {
+				return fmt.Errorf("adding unservable type detector: %w", err)
+			}
+		}
+
 		metrics.InitializeForGVK(objGVK.GroupVersionKind())
 
 		return nil
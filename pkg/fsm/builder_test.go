@@ -0,0 +1,724 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	fsmhandler "github.com/reddit/achilles-sdk/pkg/fsm/handler"
+	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
+	fsmtypes "github.com/reddit/achilles-sdk/pkg/fsm/types"
+	internalscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+var scheme = internalscheme.MustNewScheme()
+
+type testState = fsmtypes.State[*v1alpha1.TestClaim]
+
+func TestBuilder_WithName(t *testing.T) {
+	const customName = "custom-name"
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	initialState := &testState{
+		Name: "apply-outputs",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testState, fsmtypes.Result) {
+			out.Apply(cm.DeepCopy())
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"}}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(core).Sugar()
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(claim).
+		WithStatusSubresource(claim).
+		Build()
+	c := &io.ClientApplicator{Client: fakeClient, Applicator: io.NewAPIPatchingApplicator(fakeClient)}
+
+	reg := prometheus.NewRegistry()
+	m := metrics.MustMakeMetrics(scheme, reg)
+	m.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	r := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).
+		Manages(corev1.SchemeGroupVersion.WithKind("ConfigMap")).
+		WithName(customName).
+		Reconciler(log, scheme, c, m)
+
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	// logger is named after the override
+	if logs.Len() == 0 {
+		t.Fatalf("expected log entries")
+	}
+	for _, entry := range logs.All() {
+		if entry.LoggerName != customName {
+			t.Errorf("unexpected logger name %q, want %q", entry.LoggerName, customName)
+		}
+	}
+
+	// managed resources are labeled with the override
+	actualCM := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actualCM); err != nil {
+		t.Fatalf("getting configmap: %s", err)
+	}
+	if got := actualCM.GetLabels()[meta.ManagedByKey]; got != customName {
+		t.Errorf("unexpected %s label %q, want %q", meta.ManagedByKey, got, customName)
+	}
+
+	// metrics use the override for the "controller" label, trigger metrics for deleted objects are cleaned up by controller name
+	deletedKey := types.NamespacedName{Name: "deleted", Namespace: "default"}
+	m.RecordTrigger(v1alpha1.TestClaimGroupVersionKind, deletedKey, "create", "self", customName)
+	if count, err := testutil.GatherAndCount(reg, "achilles_trigger"); err != nil || count != 1 {
+		t.Fatalf("expected 1 trigger metric, got %d (err: %v)", count, err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: deletedKey}); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if count, err := testutil.GatherAndCount(reg, "achilles_trigger"); err != nil || count != 0 {
+		t.Errorf("expected trigger metric to be deleted, got %d (err: %v)", count, err)
+	}
+}
+
+func TestEventChannel_Source(t *testing.T) {
+	ch := make(chan event.GenericEvent)
+	expected := reconcile.Request{NamespacedName: types.NamespacedName{Name: "mapped", Namespace: "default"}}
+
+	e := eventChannel{
+		ch: ch,
+		mapFn: func(o client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "mapped", Namespace: o.GetNamespace()}}}
+		},
+		triggerType: fsmhandler.TriggerTypeRelative,
+	}
+	src := e.source(zaptest.NewLogger(t).Sugar(), scheme, "test", metrics.MustMakeMetrics(scheme, prometheus.NewRegistry()))
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := src.Start(ctx, q); err != nil {
+		t.Fatalf("starting source: %s", err)
+	}
+
+	ch <- event.GenericEvent{Object: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "default"}}}
+
+	actual, shutdown := q.Get()
+	if shutdown {
+		t.Fatalf("queue unexpectedly shut down")
+	}
+	if actual != expected {
+		t.Errorf("unexpected request %s, want %s", actual, expected)
+	}
+	q.Done(actual)
+
+	// closing the channel stops the source without panicking
+	close(ch)
+}
+
+func TestClaimBuilder_MaxConcurrentReconciles(t *testing.T) {
+	tcs := []struct {
+		name            string
+		shared          int
+		claim           int
+		claimed         int
+		expectedClaim   int
+		expectedClaimed int
+	}{
+		{
+			name: "unset",
+			// controller-runtime defaults to 1
+			expectedClaim:   0,
+			expectedClaimed: 0,
+		},
+		{
+			name:            "shared",
+			shared:          2,
+			expectedClaim:   2,
+			expectedClaimed: 2,
+		},
+		{
+			name:            "independent",
+			shared:          2,
+			claim:           8,
+			claimed:         1,
+			expectedClaim:   8,
+			expectedClaimed: 1,
+		},
+		{
+			name:            "claimed only",
+			claimed:         4,
+			expectedClaim:   0,
+			expectedClaimed: 4,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewClaimBuilder(&v1alpha1.TestClaimed{}, &v1alpha1.TestClaim{}, nil, scheme).
+				WithMaxConcurrentReconciles(tc.shared).
+				WithClaimMaxConcurrentReconciles(tc.claim).
+				WithClaimedMaxConcurrentReconciles(tc.claimed)
+
+			rl := workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]()
+			if actual := b.claimControllerOptions(rl).MaxConcurrentReconciles; actual != tc.expectedClaim {
+				t.Errorf("expected claim controller max concurrent reconciles %d, got %d", tc.expectedClaim, actual)
+			}
+			if actual := b.claimedControllerOptions(rl).MaxConcurrentReconciles; actual != tc.expectedClaimed {
+				t.Errorf("expected claimed controller max concurrent reconciles %d, got %d", tc.expectedClaimed, actual)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithPriorityFunc(t *testing.T) {
+	newClaim := func(name, env string) *v1alpha1.TestClaim {
+		return &v1alpha1.TestClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"env": env}},
+		}
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newClaim("dev-1", "dev"), newClaim("prod-1", "prod"), newClaim("dev-2", "dev"), newClaim("prod-2", "prod")).
+		Build()
+
+	b := NewBuilder(&v1alpha1.TestClaim{}, &testState{Name: "initial"}, scheme)
+	if b.newQueue(c) != nil {
+		t.Fatal("expected default queue if no priority func is configured")
+	}
+
+	b.WithPriorityFunc(func(obj client.Object) int {
+		if obj.GetLabels()["env"] == "prod" {
+			return 10
+		}
+		return 0
+	})
+
+	q := b.newQueue(c)("test-priority", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	// objects that don't exist have the default priority
+	for _, name := range []string{"dev-1", "prod-1", "dev-2", "missing", "prod-2"} {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}})
+	}
+
+	var actual []string
+	for range 5 {
+		req, shutdown := q.Get()
+		if shutdown {
+			t.Fatal("unexpected queue shutdown")
+		}
+		actual = append(actual, req.Name)
+		q.Done(req)
+	}
+
+	// higher priorities first, equal priorities in FIFO order
+	expected := []string{"prod-1", "prod-2", "dev-1", "dev-2", "missing"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected dequeue order (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithDefaultReadyFuncs(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default"}}
+	claim := &v1alpha1.TestClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"},
+		Status:     v1alpha1.TestClaimStatus{ResourceRefs: []api.TypedObjectRef{*meta.MustTypedObjectRefFromObject(secret, scheme)}},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(claim, secret).
+		WithStatusSubresource(claim).
+		Build()
+	c := &io.ClientApplicator{Client: fakeClient, Applicator: io.NewAPIPatchingApplicator(fakeClient)}
+	log := zaptest.NewLogger(t).Sugar()
+
+	var ready bool
+	initialState := &testState{
+		Name: "wait-for-ready",
+		Transition: func(ctx context.Context, obj *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testState, fsmtypes.Result) {
+			// no ready funcs are passed per call
+			next, result := fsmtypes.TransitionWhenReady[*v1alpha1.TestClaim](c, scheme, log, nil)(ctx, obj, out)
+			ready = result.IsDone()
+			return next, result
+		},
+	}
+
+	m := metrics.MustMakeMetrics(scheme, prometheus.NewRegistry())
+	m.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+	r := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).
+		Manages(corev1.SchemeGroupVersion.WithKind("Secret")).
+		WithDefaultReadyFuncs(fsmtypes.MakeCustomReadyFunc(func(*corev1.Secret) bool { return true })).
+		Reconciler(log, scheme, c, m)
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if !ready {
+		t.Error("expected secret to be ready through the controller's default ready funcs")
+	}
+}
+
+// controllerOptionsManager is a manager that only provides controller options
+type controllerOptionsManager struct {
+	ctrl.Manager
+	options config.Controller
+}
+
+func (m *controllerOptionsManager) GetControllerOptions() config.Controller {
+	return m.options
+}
+
+func TestWithCoalescedQueue(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	priorityBuilder := NewBuilder(&v1alpha1.TestClaim{}, &testState{Name: "initial"}, scheme).
+		WithPriorityFunc(func(client.Object) int { return 0 })
+
+	tcs := []struct {
+		name             string
+		newQueue         func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request]
+		usePriorityQueue bool
+	}{
+		{
+			name: "default queue",
+		},
+		{
+			name:             "priority queue",
+			usePriorityQueue: true,
+		},
+		{
+			name:     "priority func",
+			newQueue: priorityBuilder.newQueue(c),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			m := metrics.MustMakeMetrics(scheme, reg)
+			mgr := &controllerOptionsManager{options: config.Controller{UsePriorityQueue: ptr.To(tc.usePriorityQueue)}}
+
+			opts := withCoalescedQueue(controller.Options{NewQueue: tc.newQueue}, mgr, m)
+			q := opts.NewQueue("test-coalesced", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+
+			expectCoalesced := func(expected int) {
+				t.Helper()
+				expectedMetric := fmt.Sprintf(`
+# HELP achilles_trigger_coalesced_total Total number of triggers per controller enqueueing a request already waiting in the workqueue, which are coalesced into a single reconcile.
+# TYPE achilles_trigger_coalesced_total counter
+achilles_trigger_coalesced_total{controller="test-coalesced"} %d
+`, expected)
+				if err := testutil.GatherAndCompare(reg, strings.NewReader(expectedMetric), "achilles_trigger_coalesced_total"); err != nil {
+					t.Error(err)
+				}
+			}
+
+			foo := reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+			bar := reconcile.Request{NamespacedName: types.NamespacedName{Name: "bar", Namespace: "default"}}
+
+			// rapid duplicate triggers are coalesced
+			for range 5 {
+				q.Add(foo)
+			}
+			q.Add(bar)
+			expectCoalesced(4)
+
+			// requests are no longer waiting once dequeued
+			for range 2 {
+				req, shutdown := q.Get()
+				if shutdown {
+					t.Fatal("unexpected queue shutdown")
+				}
+				q.Done(req)
+			}
+			q.Add(foo)
+			expectCoalesced(4)
+			q.Add(foo)
+			expectCoalesced(5)
+		})
+	}
+}
+
+func TestClaimBuilder_WithReconcilerOptions(t *testing.T) {
+	const conditionType = api.ConditionType("Provisioned")
+	initialState := &fsmtypes.State[*v1alpha1.TestClaimed]{
+		Name:      "provision",
+		Condition: api.Condition{Type: conditionType},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaimed, _ *fsmtypes.OutputSet) (*fsmtypes.State[*v1alpha1.TestClaimed], fsmtypes.Result) {
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	b := NewClaimBuilder(&v1alpha1.TestClaimed{}, &v1alpha1.TestClaim{}, initialState, scheme).
+		WithReconcilerOptions(fsmtypes.ReconcilerOptions[v1alpha1.TestClaimed, *v1alpha1.TestClaimed]{
+			MetricsOptions: fsmtypes.MetricsOptions{ConditionTypes: []api.ConditionType{conditionType}},
+		})
+
+	claimed := &v1alpha1.TestClaimed{ObjectMeta: metav1.ObjectMeta{Name: "claimed", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(claimed).WithStatusSubresource(claimed).Build()
+	reg := prometheus.NewRegistry()
+	m := metrics.MustMakeMetrics(scheme, reg)
+	m.InitializeForGVK(meta.MustGVKForObject(claimed, scheme))
+
+	r := b.claimedReconciler("test-claimed", zaptest.NewLogger(t).Sugar(), &io.ClientApplicator{Client: c, Applicator: io.NewAPIPatchingApplicator(c)}, scheme, m)
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claimed)}); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	// the custom condition type is instrumented
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %s", err)
+	}
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "achilles_resource_readiness" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["type"] == string(conditionType) && labels["status"] == "True" && metric.GetGauge().GetValue() == 1 {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected readiness metric for condition type %s with status True", conditionType)
+	}
+}
+
+func TestBuilder_EvaluateStates(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+
+	var terminalState, awaitState, provisionState *testState
+	terminalState = &testState{Name: "terminal"}
+	awaitState = &testState{
+		Name:      "await",
+		Condition: api.Condition{Type: "Awaited"},
+		Transition: func(_ context.Context, claim *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testState, fsmtypes.Result) {
+			switch claim.Spec.TestField {
+			case "ready":
+				return terminalState, fsmtypes.DoneResult()
+			case "other":
+				return nil, fsmtypes.SkipResult("HandledElsewhere")
+			default:
+				return nil, fsmtypes.RequeueResultWithBackoff("waiting for test field")
+			}
+		},
+	}
+	provisionState = &testState{
+		Name:      "provision",
+		Condition: api.Condition{Type: "Provisioned"},
+		Transition: func(_ context.Context, claim *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testState, fsmtypes.Result) {
+			claim.Status.TestField = "mutated"
+			out.Apply(cm.DeepCopy())
+			return awaitState, fsmtypes.DoneResultWithStatusCondition(fsmtypes.ResultStatusCondition{
+				Status: corev1.ConditionFalse,
+				Reason: "Degraded",
+			})
+		},
+	}
+
+	builder := NewBuilder(&v1alpha1.TestClaim{}, provisionState, scheme)
+
+	cases := []struct {
+		name               string
+		testField          string
+		expectedStates     []string
+		expectedDone       []bool
+		expectedConditions []corev1.ConditionStatus
+	}{
+		{
+			name:               "requeues",
+			expectedStates:     []string{"provision", "await"},
+			expectedDone:       []bool{true, false},
+			expectedConditions: []corev1.ConditionStatus{corev1.ConditionFalse, corev1.ConditionFalse},
+		},
+		{
+			name:               "completes",
+			testField:          "ready",
+			expectedStates:     []string{"provision", "await", "terminal"},
+			expectedDone:       []bool{true, true, true},
+			expectedConditions: []corev1.ConditionStatus{corev1.ConditionFalse, corev1.ConditionTrue, ""},
+		},
+		{
+			name:               "skips",
+			testField:          "other",
+			expectedStates:     []string{"provision", "await"},
+			expectedDone:       []bool{true, true},
+			expectedConditions: []corev1.ConditionStatus{corev1.ConditionFalse, ""},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default", Generation: 2}}
+			claim.Spec.TestField = tc.testField
+
+			results, err := builder.EvaluateStates(context.Background(), claim)
+			if err != nil {
+				t.Fatalf("evaluating states: %s", err)
+			}
+
+			var states []string
+			var done []bool
+			var conditions []corev1.ConditionStatus
+			for _, r := range results {
+				states = append(states, r.State)
+				done = append(done, r.Result.IsDone())
+				conditions = append(conditions, r.Condition.Status)
+			}
+			if diff := cmp.Diff(tc.expectedStates, states); diff != "" {
+				t.Errorf("unexpected states: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedDone, done); diff != "" {
+				t.Errorf("unexpected done results: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedConditions, conditions); diff != "" {
+				t.Errorf("unexpected condition statuses: (-want +got)\n%s", diff)
+			}
+
+			// custom status conditions are set as they are in a reconcile
+			if provisioned := results[0].Condition; provisioned.Reason != "Degraded" || provisioned.ObservedGeneration != claim.Generation {
+				t.Errorf("expected custom status condition observing generation %d, got %v", claim.Generation, provisioned)
+			}
+			if outputs := results[0].Outputs.ListAppliedOutputs(); len(outputs) != 1 || outputs[0].Object.GetName() != cm.Name {
+				t.Errorf("expected output %s, got %v", cm.Name, outputs)
+			}
+			if claim.Status.TestField != "" {
+				t.Errorf("expected evaluated object not to be mutated, got status test field %q", claim.Status.TestField)
+			}
+		})
+	}
+}
+
+func TestBuilder_EvaluateStatesReadOnly(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clientApplicator := &io.ClientApplicator{Client: c, Applicator: io.NewAPIPatchingApplicator(c)}
+
+	initialState := &testState{
+		Name: "write",
+		Transition: func(ctx context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testState, fsmtypes.Result) {
+			if err := clientApplicator.Apply(ctx, cm.DeepCopy()); err != nil {
+				return nil, fsmtypes.ErrorResult(err)
+			}
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"}}
+	results, err := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).EvaluateStates(context.Background(), claim)
+	if err != nil {
+		t.Fatalf("evaluating states: %s", err)
+	}
+
+	// writes through a ClientApplicator are rejected
+	if len(results) != 1 || !errors.As(results[0].Result.Err, &io.WriteInReadOnlyContext{}) {
+		t.Fatalf("expected a single result with a read-only error, got %v", results)
+	}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected ConfigMap not to be created, got %v", err)
+	}
+}
+
+func TestReconcileFilterPredicate(t *testing.T) {
+	p := reconcileFilterPredicate(fsmtypes.ReconcileFilter{
+		Selector: labels.SelectorFromSet(labels.Set{"tenant": "a"}),
+	})
+
+	matching := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Labels: map[string]string{"tenant": "a"}}}
+	other := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Labels: map[string]string{"tenant": "b"}}}
+
+	cases := []struct {
+		name     string
+		actual   bool
+		expected bool
+	}{
+		{name: "create matching", actual: p.Create(event.CreateEvent{Object: matching}), expected: true},
+		{name: "create non-matching", actual: p.Create(event.CreateEvent{Object: other}), expected: false},
+		{name: "update starts matching", actual: p.Update(event.UpdateEvent{ObjectOld: other, ObjectNew: matching}), expected: true},
+		{name: "update stops matching", actual: p.Update(event.UpdateEvent{ObjectOld: matching, ObjectNew: other}), expected: true},
+		{name: "update non-matching", actual: p.Update(event.UpdateEvent{ObjectOld: other, ObjectNew: other}), expected: false},
+		{name: "delete non-matching", actual: p.Delete(event.DeleteEvent{Object: other}), expected: false},
+		{name: "generic matching", actual: p.Generic(event.GenericEvent{Object: matching}), expected: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithForPredicate(t *testing.T) {
+	tenantA := predicate.NewPredicateFuncs(func(o client.Object) bool { return o.GetLabels()["tenant"] == "a" })
+	b := NewBuilder(&v1alpha1.TestClaim{}, &testState{Name: "initial"}, scheme).
+		Manages(corev1.SchemeGroupVersion.WithKind("Secret")).
+		WithForPredicate(tenantA)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	m := metrics.MustMakeMetrics(scheme, prometheus.NewRegistry())
+	m.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+	predicates := b.forPredicates(zap.New(core).Sugar(), scheme, "test-claim", m)
+
+	// predicates are evaluated in order until one rejects the event, as by controller-runtime
+	admits := func(o client.Object) bool {
+		for _, p := range predicates {
+			if !p.Create(event.CreateEvent{Object: o}) {
+				return false
+			}
+		}
+		return true
+	}
+
+	matching := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "matching", Labels: map[string]string{"tenant": "a"}}}
+	other := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"tenant": "b"}}}
+	if !admits(matching) {
+		t.Error("expected event of matching object to be admitted")
+	}
+	if admits(other) {
+		t.Error("expected event of non-matching object to be filtered")
+	}
+
+	// only admitted events are observed
+	if logs.Len() != 1 || logs.All()[0].ContextMap()["request"] != "/matching" {
+		t.Errorf("expected a single observed trigger for the matching object, got %v", logs.All())
+	}
+
+	// unlike WithEventFilter, the predicate isn't applied to all watches
+	if len(b.opts) != 0 {
+		t.Errorf("expected no controller-wide event filters, got %d", len(b.opts))
+	}
+}
+
+func TestNoPeriodicResyncPredicate(t *testing.T) {
+	p := noPeriodicResyncPredicate()
+
+	claim := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default", ResourceVersion: "1"}}
+	changed := claim.DeepCopy()
+	changed.ResourceVersion = "2"
+	changed.Spec.TestField = "changed"
+
+	if p.Update(event.UpdateEvent{ObjectOld: claim, ObjectNew: claim.DeepCopy()}) {
+		t.Error("expected resync of unchanged object to be filtered")
+	}
+	if !p.Update(event.UpdateEvent{ObjectOld: claim, ObjectNew: changed}) {
+		t.Error("expected spec change to trigger a reconcile")
+	}
+	if !p.Create(event.CreateEvent{Object: claim}) {
+		t.Error("expected creation to trigger a reconcile")
+	}
+	if !p.Delete(event.DeleteEvent{Object: claim}) {
+		t.Error("expected deletion to trigger a reconcile")
+	}
+}
+
+func TestBuilder_Validate(t *testing.T) {
+	unregistered := schema.GroupVersionKind{Group: "unregistered.reddit.com", Version: "v1", Kind: "Unregistered"}
+	initialState := &testState{Name: "state"}
+
+	t.Run("valid", func(t *testing.T) {
+		builder := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).
+			Manages(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		if err := builder.Validate(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("unregistered managed types", func(t *testing.T) {
+		builder := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).
+			Manages(corev1.SchemeGroupVersion.WithKind("ConfigMap"), unregistered).
+			ManagesWithPredicate(unregistered.GroupVersion().WithKind("OtherUnregistered"))
+
+		err := builder.Validate()
+		for _, expected := range []string{
+			"managed type unregistered.reddit.com/v1, Kind=Unregistered is not registered with runtime scheme",
+			"managed type unregistered.reddit.com/v1, Kind=OtherUnregistered is not registered with runtime scheme",
+		} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected error containing %q, got %v", expected, err)
+			}
+		}
+
+		// the controller's setup fails before the manager is used
+		if err := builder.Build()(nil, zaptest.NewLogger(t).Sugar(), nil, nil); err == nil || !strings.Contains(err.Error(), "Kind=Unregistered") {
+			t.Errorf("expected setup to fail with validation error, got %v", err)
+		}
+	})
+
+	t.Run("non-positive resync period", func(t *testing.T) {
+		builder := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).
+			ManagesWithResync(corev1.SchemeGroupVersion.WithKind("ConfigMap"), 0)
+		if err := builder.Validate(); err == nil || !strings.Contains(err.Error(), "resync period 0s of managed type /v1, Kind=ConfigMap must be positive") {
+			t.Errorf("expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("claim builder", func(t *testing.T) {
+		builder := NewClaimBuilder(&v1alpha1.TestClaimed{}, &v1alpha1.TestClaim{}, &fsmtypes.State[*v1alpha1.TestClaimed]{Name: "state"}, scheme).
+			Manages(unregistered)
+		if err := builder.Validate(); err == nil || !strings.Contains(err.Error(), "Kind=Unregistered") {
+			t.Errorf("expected validation error, got %v", err)
+		}
+	})
+}
+
+func TestBuilder_WithBackoff(t *testing.T) {
+	initialState := &testState{Name: "state"}
+
+	if err := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).WithBackoff(time.Second, time.Minute).Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	for _, delays := range [][2]time.Duration{{time.Minute, time.Second}, {0, time.Minute}} {
+		err := NewBuilder(&v1alpha1.TestClaim{}, initialState, scheme).WithBackoff(delays[0], delays[1]).Validate()
+		if err == nil || !strings.Contains(err.Error(), "backoff base delay") {
+			t.Errorf("expected validation error for backoff %v, got %v", delays, err)
+		}
+	}
+}
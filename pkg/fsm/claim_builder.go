@@ -2,6 +2,7 @@ package fsm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/iancoleman/strcase"
@@ -14,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -42,8 +44,16 @@ type ClaimBuilder[T any, U any, ClaimedType apitypes.ClaimedType[T], ClaimType a
 	watches                 []watch
 	watchRemoteKinds        []watchRemoteKind
 	watchRawSources         []source.Source
+	eventChannels           []eventChannel
 	opts                    []buildOption
 	maxConcurrentReconciles int
+	reconcilerOptions       types.ReconcilerOptions[T, ClaimedType]
+
+	claimMaxConcurrentReconciles   int
+	claimedMaxConcurrentReconciles int
+
+	// errs are the configuration errors accumulated by builder methods, returned by Validate
+	errs []error
 }
 
 // NewClaimBuilder returns a builder that builds a function wiring up a logical FSM controller to a manager.
@@ -71,12 +81,18 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Manages(
 		if b.scheme.Recognizes(gvk) {
 			b.managedTypes = append(b.managedTypes, gvk)
 		} else {
-			panic(fmt.Sprintf("%s is not registered with runtime scheme", gvk))
+			b.errs = append(b.errs, unregisteredTypeError(gvk))
 		}
 	}
 	return b
 }
 
+// Validate returns an error if the builder is misconfigured, e.g. if a managed type isn't registered with the scheme.
+// Build's SetupFunc returns the same error.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Validate() error {
+	return errors.Join(b.errs...)
+}
+
 // WithControllerHandle adds a ControllerFunc.
 func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithControllerHandle(fn ControllerFunc) *ClaimBuilder[T, U, ClaimedType, ClaimType] {
 	b.controllerFns = append(b.controllerFns, fn)
@@ -99,13 +115,38 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) BeforeDelete(
 	return b
 }
 
+// WithReconcilerOptions sets reconciler options for the claimed reconciler. The claim reconciler isn't affected.
+// Since the claim reconciler creates and deletes claimed objects, CreateIfNotFound recreates claimed objects deleted
+// along with their claim, so it should only be set for claimed objects outliving their claims.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithReconcilerOptions(
+	reconcilerOptions types.ReconcilerOptions[T, ClaimedType],
+) *ClaimBuilder[T, U, ClaimedType, ClaimType] {
+	b.reconcilerOptions = reconcilerOptions
+	return b
+}
+
 // WithMaxConcurrentReconciles sets the maxConcurrentReconciles option for controller-runtime. Defaults to 1 if not specified or when a value <= 0 is passed.
+// The value applies to both the claim and claimed controllers, unless overridden with WithClaimMaxConcurrentReconciles or WithClaimedMaxConcurrentReconciles.
 // controller-runtime ensures a single object is not reconciled by multiple reconcilers concurrently. If your controller manages global state (e.g. caches attached to the controller struct), you need to ensure it is thread safe before increasing the concurrency.
 func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithMaxConcurrentReconciles(maxConcurrentReconciles int) *ClaimBuilder[T, U, ClaimedType, ClaimType] {
 	b.maxConcurrentReconciles = maxConcurrentReconciles
 	return b
 }
 
+// WithClaimMaxConcurrentReconciles sets the maxConcurrentReconciles option for the claim controller, overriding the value
+// set with WithMaxConcurrentReconciles. Values <= 0 fall back to the value set with WithMaxConcurrentReconciles.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithClaimMaxConcurrentReconciles(maxConcurrentReconciles int) *ClaimBuilder[T, U, ClaimedType, ClaimType] {
+	b.claimMaxConcurrentReconciles = maxConcurrentReconciles
+	return b
+}
+
+// WithClaimedMaxConcurrentReconciles sets the maxConcurrentReconciles option for the claimed controller, overriding the value
+// set with WithMaxConcurrentReconciles. Values <= 0 fall back to the value set with WithMaxConcurrentReconciles.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithClaimedMaxConcurrentReconciles(maxConcurrentReconciles int) *ClaimBuilder[T, U, ClaimedType, ClaimType] {
+	b.claimedMaxConcurrentReconciles = maxConcurrentReconciles
+	return b
+}
+
 // Watches adds a custom watch to the controller.
 func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Watches(
 	object client.Object,
@@ -150,6 +191,22 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WatchesRawSource(src source
 	return b
 }
 
+// WithEventChannel adds a new watch to the controller for events originating outside the cluster (e.g. webhooks or pubsub),
+// delivered through the supplied channel. Each event's object is mapped to reconcile requests through mapFn.
+// Unlike WatchesRawSource, the event handler is wrapped with the FSM handler. The watch stops once the channel is closed.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithEventChannel(
+	ch <-chan event.GenericEvent,
+	mapFn func(client.Object) []reconcile.Request,
+	triggerType fsmhandler.TriggerType,
+) *ClaimBuilder[T, U, ClaimedType, ClaimType] {
+	b.eventChannels = append(b.eventChannels, eventChannel{
+		ch:          ch,
+		mapFn:       mapFn,
+		triggerType: triggerType,
+	})
+	return b
+}
+
 // WithEventFilter adds a custom event filter to the controller.
 func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) WithEventFilter(
 	predicate predicate.Predicate,
@@ -165,6 +222,10 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Build() SetupFunc {
 		rl workqueue.TypedRateLimiter[reconcile.Request],
 		metrics *metrics.Metrics,
 	) error {
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("validating controller: %w", err)
+		}
+
 		objGVK := meta.MustTypedObjectRefFromObject(b.obj, mgr.GetScheme())
 		name := strcase.ToKebab(objGVK.Kind)
 		log = log.Named(name)
@@ -180,10 +241,7 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Build() SetupFunc {
 		claimReconciler := internal.NewClaimReconciler(b.obj, b.claim, c, scheme, log, b.beforeDelete)
 		if err := ctrl.NewControllerManagedBy(mgr).
 			Named(claimName).
-			WithOptions(controller.Options{
-				RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(rl),
-				MaxConcurrentReconciles: b.maxConcurrentReconciles,
-			}).
+			WithOptions(withCoalescedQueue(b.claimControllerOptions(rl), mgr, metrics)).
 			// equivalent to calling `builder.For` but uses an event handler that debug logs the event trigger
 			For(
 				b.claim,
@@ -207,24 +265,11 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Build() SetupFunc {
 			return fmt.Errorf("creating claim controller: %w", err)
 		}
 
-		r := internal.NewFSMReconciler(
-			name,
-			log,
-			c,
-			scheme,
-			b.initialState,
-			b.finalizerState,
-			b.managedTypes,
-			metrics,
-			types.ReconcilerOptions[T, ClaimedType]{}, // TODO expose a builder method for setting ReconcilerOptions once a relevant one exists
-		)
+		r := b.claimedReconciler(name, log, c, scheme, metrics)
 
 		// claimed reconciler
 		claimedBuilder := ctrl.NewControllerManagedBy(mgr).
-			WithOptions(controller.Options{
-				RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(rl),
-				MaxConcurrentReconciles: b.maxConcurrentReconciles,
-			}).
+			WithOptions(withCoalescedQueue(b.claimedControllerOptions(rl), mgr, metrics)).
 			Watches(
 				b.claim,
 				fsmhandler.NewObservedEventHandler(
@@ -289,6 +334,13 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Build() SetupFunc {
 			claimedBuilder.WatchesRawSource(w)
 		}
 
+		for _, e := range b.eventChannels {
+			claimedBuilder.WatchesRawSource(e.source(log, scheme, name, metrics))
+		}
+
+		// enqueues objects requeued through OutputSet.RequeueRef
+		claimedBuilder.WatchesRawSource(r.RequeueSource())
+
 		// custom controller builder options
 		for _, opt := range b.opts {
 			opt(claimedBuilder)
@@ -310,3 +362,49 @@ func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) Build() SetupFunc {
 		return nil
 	}
 }
+
+// claimedReconciler returns the FSM reconciler for the claimed object.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) claimedReconciler(
+	name string,
+	log *zap.SugaredLogger,
+	c *io.ClientApplicator,
+	scheme *runtime.Scheme,
+	metrics *metrics.Metrics,
+) internal.Reconciler {
+	return internal.NewFSMReconciler(
+		name,
+		log,
+		c,
+		scheme,
+		b.initialState,
+		b.finalizerState,
+		b.managedTypes,
+		metrics,
+		b.reconcilerOptions,
+	)
+}
+
+// claimControllerOptions returns the controller options for the claim controller.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) claimControllerOptions(rl workqueue.TypedRateLimiter[reconcile.Request]) controller.Options {
+	return controller.Options{
+		RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(rl),
+		MaxConcurrentReconciles: maxConcurrentReconciles(b.claimMaxConcurrentReconciles, b.maxConcurrentReconciles),
+	}
+}
+
+// claimedControllerOptions returns the controller options for the claimed controller.
+func (b *ClaimBuilder[T, U, ClaimedType, ClaimType]) claimedControllerOptions(rl workqueue.TypedRateLimiter[reconcile.Request]) controller.Options {
+	return controller.Options{
+		RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(rl),
+		MaxConcurrentReconciles: maxConcurrentReconciles(b.claimedMaxConcurrentReconciles, b.maxConcurrentReconciles),
+	}
+}
+
+// maxConcurrentReconciles returns the override if positive, otherwise the shared value.
+// If neither is positive, controller-runtime defaults to 1.
+func maxConcurrentReconciles(override, shared int) int {
+	if override > 0 {
+		return override
+	}
+	return shared
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// maxBufferedEvents is the number of buffered events beyond which the buffer is flushed before the next interval,
+// bounding memory usage for bursts of events.
+const maxBufferedEvents = 1000
+
+var (
+	_ record.EventRecorder = &eventBatcher{}
+	_ manager.Runnable     = &eventBatcher{}
+)
+
+// eventBatcher is a record.EventRecorder that buffers events and flushes them to the underlying recorder periodically.
+// Identical events (same involved object, type, reason, and message) buffered within the same batch are recorded once.
+type eventBatcher struct {
+	recorder record.EventRecorder
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending []bufferedEvent
+	// seen tracks the events pending in the current batch, for deduplication
+	seen map[bufferedEventKey]struct{}
+}
+
+type bufferedEvent struct {
+	// obj is a copy of the involved object taken when the event was buffered, callers may continue mutating the original
+	obj runtime.Object
+	key bufferedEventKey
+}
+
+type bufferedEventKey struct {
+	object    string
+	eventType string
+	reason    string
+	message   string
+}
+
+func newEventBatcher(recorder record.EventRecorder, interval time.Duration) *eventBatcher {
+	return &eventBatcher{
+		recorder: recorder,
+		interval: interval,
+		seen:     map[bufferedEventKey]struct{}{},
+	}
+}
+
+// Event buffers the event until the next flush, unless an identical event is already buffered.
+// The involved object is copied, so it may be mutated once Event returns.
+func (b *eventBatcher) Event(obj runtime.Object, eventType, reason, message string) {
+	key := bufferedEventKey{eventType: eventType, reason: reason, message: message}
+	if o, ok := obj.(client.Object); ok {
+		key.object = objectKey(o)
+	}
+
+	b.mu.Lock()
+	if _, ok := b.seen[key]; ok {
+		b.mu.Unlock()
+		return
+	}
+	b.seen[key] = struct{}{}
+	// the object is recorded asynchronously on flush, so it's copied to avoid racing with the caller's mutations
+	b.pending = append(b.pending, bufferedEvent{obj: obj.DeepCopyObject(), key: key})
+	full := len(b.pending) >= maxBufferedEvents
+	b.mu.Unlock()
+
+	if full {
+		b.flush()
+	}
+}
+
+// Eventf buffers the event until the next flush, unless an identical event is already buffered.
+func (b *eventBatcher) Eventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	b.Event(obj, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf records the event immediately, annotated events aren't buffered.
+func (b *eventBatcher) AnnotatedEventf(obj runtime.Object, annotations map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	b.recorder.AnnotatedEventf(obj, annotations, eventType, reason, messageFmt, args...)
+}
+
+// Start flushes buffered events every interval until the context is done, upon which remaining events are flushed.
+func (b *eventBatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.flush()
+			return nil
+		case <-ticker.C:
+			b.flush()
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, events are flushed regardless of leadership.
+func (b *eventBatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// flush records all buffered events with the underlying recorder, in the order in which they were buffered.
+func (b *eventBatcher) flush() {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	b.seen = map[bufferedEventKey]struct{}{}
+	b.mu.Unlock()
+
+	for _, e := range pending {
+		b.recorder.Event(e.obj, e.key.eventType, e.key.reason, e.key.message)
+	}
+}
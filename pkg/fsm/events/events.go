@@ -1,6 +1,9 @@
 package events
 
 import (
+	"fmt"
+	"time"
+
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +23,9 @@ type EventRecorder struct {
 	metrics  *metrics.Metrics
 
 	controllerName string
+
+	// limiter rate limits events per involved object, nil if events aren't rate limited
+	limiter *objectRateLimiter
 }
 
 // NewEventRecorder creates a new EventRecorder for the given controller and manager.
@@ -28,9 +34,41 @@ func NewEventRecorder(controllerName string, manager ctrl.Manager, metrics *metr
 	return &EventRecorder{recorder: manager.GetEventRecorderFor(controllerName), metrics: metrics, controllerName: controllerName}
 }
 
+// NewRateLimitedEventRecorder creates a new EventRecorder for the given controller and manager that records at most
+// eventsPerMinute events per involved object, allowing bursts of up to eventsPerMinute events. Events exceeding the rate are dropped.
+// This guards against rapidly-flapping objects flooding the kube-apiserver with events. Values <= 0 disable rate limiting.
+// Metrics is optional and can be nil. If provided, it will be used to emit metrics for each recorded event.
+func NewRateLimitedEventRecorder(controllerName string, manager ctrl.Manager, metrics *metrics.Metrics, eventsPerMinute int) *EventRecorder {
+	e := NewEventRecorder(controllerName, manager, metrics)
+	if eventsPerMinute > 0 {
+		e.limiter = newObjectRateLimiter(eventsPerMinute)
+	}
+	return e
+}
+
+// NewBatchedEventRecorder creates a new EventRecorder for the given controller and manager that buffers events and
+// records them every flushInterval, reducing load on the kube-apiserver for controllers recording many events.
+// Identical events (same involved object, type, reason, and message) within the same interval are recorded once.
+// Buffered events are flushed when the manager stops.
+// Metrics is optional and can be nil. If provided, it will be used to emit metrics for each event.
+func NewBatchedEventRecorder(controllerName string, manager ctrl.Manager, metrics *metrics.Metrics, flushInterval time.Duration) (*EventRecorder, error) {
+	if flushInterval <= 0 {
+		return nil, fmt.Errorf("flush interval must be positive, got %s", flushInterval)
+	}
+
+	batcher := newEventBatcher(manager.GetEventRecorderFor(controllerName), flushInterval)
+	if err := manager.Add(batcher); err != nil {
+		return nil, fmt.Errorf("adding event batcher to manager: %w", err)
+	}
+	return &EventRecorder{recorder: batcher, metrics: metrics, controllerName: controllerName}, nil
+}
+
 // RecordReady records a ready event for the given object.
 // message is optional and defaults to "Object is ready".
 func (e *EventRecorder) RecordReady(obj client.Object, message string) {
+	if !e.allow(obj) {
+		return
+	}
 	if message == "" {
 		message = "Object is ready"
 	}
@@ -43,6 +81,9 @@ func (e *EventRecorder) RecordReady(obj client.Object, message string) {
 
 // RecordWarning records a warning event for the given object.
 func (e *EventRecorder) RecordWarning(obj client.Object, reason string, message string) {
+	if !e.allow(obj) {
+		return
+	}
 	e.recorder.Event(obj, eventTypeWarning, reason, message)
 
 	if e.metrics != nil {
@@ -52,9 +93,17 @@ func (e *EventRecorder) RecordWarning(obj client.Object, reason string, message
 
 // RecordEvent records a normal event for the given object.
 func (e *EventRecorder) RecordEvent(obj client.Object, reason string, message string) {
+	if !e.allow(obj) {
+		return
+	}
 	e.recorder.Event(obj, eventTypeNormal, reason, message)
 
 	if e.metrics != nil {
 		e.metrics.RecordEvent(obj.GetObjectKind().GroupVersionKind(), obj.GetName(), obj.GetNamespace(), eventTypeNormal, reason, e.controllerName)
 	}
 }
+
+// allow returns true if the event for the given object isn't rate limited.
+func (e *EventRecorder) allow(obj client.Object) bool {
+	return e.limiter == nil || e.limiter.allow(obj)
+}
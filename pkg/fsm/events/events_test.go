@@ -0,0 +1,200 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEventRecorder_RateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newObjectRateLimiter(2)
+	limiter.now = func() time.Time { return now }
+
+	fakeRecorder := record.NewFakeRecorder(100)
+	e := &EventRecorder{recorder: fakeRecorder, controllerName: "test", limiter: limiter}
+
+	flapping := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "flapping", Namespace: "default", UID: types.UID("flapping")}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: types.UID("other")}}
+
+	recorded := func() int {
+		n := len(fakeRecorder.Events)
+		for range n {
+			<-fakeRecorder.Events
+		}
+		return n
+	}
+
+	// events beyond the burst are dropped
+	for range 5 {
+		e.RecordWarning(flapping, "Flapping", "object is flapping")
+	}
+	if n := recorded(); n != 2 {
+		t.Errorf("expected 2 events to be recorded, got %d", n)
+	}
+
+	// buckets are per object
+	e.RecordEvent(other, "Other", "other object")
+	if n := recorded(); n != 1 {
+		t.Errorf("expected 1 event to be recorded for other object, got %d", n)
+	}
+
+	// the bucket refills at the configured rate
+	now = now.Add(30 * time.Second)
+	for range 5 {
+		e.RecordReady(flapping, "")
+	}
+	if n := recorded(); n != 1 {
+		t.Errorf("expected 1 event to be recorded after partial refill, got %d", n)
+	}
+
+	// stale buckets are evicted once fully refilled
+	now = now.Add(time.Minute)
+	e.RecordEvent(other, "Other", "other object")
+	if _, ok := limiter.buckets["flapping"]; ok {
+		t.Error("expected stale bucket to be evicted")
+	}
+	if n := recorded(); n != 1 {
+		t.Errorf("expected 1 event to be recorded for other object, got %d", n)
+	}
+	for range 5 {
+		e.RecordWarning(flapping, "Flapping", "object is flapping")
+	}
+	if n := recorded(); n != 2 {
+		t.Errorf("expected 2 events to be recorded after full refill, got %d", n)
+	}
+}
+
+func TestEventRecorder_NoRateLimit(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(100)
+	e := &EventRecorder{recorder: fakeRecorder, controllerName: "test"}
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", UID: types.UID("cm")}}
+	for range 10 {
+		e.RecordEvent(obj, "Reason", "message")
+	}
+	if n := len(fakeRecorder.Events); n != 10 {
+		t.Errorf("expected 10 events to be recorded, got %d", n)
+	}
+}
+
+func TestEventRecorder_Batched(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(100)
+	batcher := newEventBatcher(fakeRecorder, time.Hour)
+	e := &EventRecorder{recorder: batcher, controllerName: "test"}
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", UID: types.UID("cm")}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: types.UID("other")}}
+
+	recorded := func() []string {
+		var events []string
+		for len(fakeRecorder.Events) > 0 {
+			events = append(events, <-fakeRecorder.Events)
+		}
+		return events
+	}
+
+	// events are buffered until flushed, identical events within a batch are deduplicated
+	for range 3 {
+		e.RecordWarning(obj, "Failed", "failed")
+	}
+	e.RecordWarning(obj, "Failed", "failed differently")
+	e.RecordWarning(other, "Failed", "failed")
+	e.RecordReady(obj, "")
+	if events := recorded(); len(events) != 0 {
+		t.Fatalf("expected events to be buffered, got %v", events)
+	}
+
+	batcher.flush()
+	expected := []string{
+		"Warning Failed failed",
+		"Warning Failed failed differently",
+		"Warning Failed failed",
+		"Normal Ready Object is ready",
+	}
+	if diff := cmp.Diff(expected, recorded()); diff != "" {
+		t.Errorf("unexpected flushed events (-want +got):\n%s", diff)
+	}
+
+	// deduplication is scoped to a batch
+	e.RecordWarning(obj, "Failed", "failed")
+
+	// buffered events are flushed on shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- batcher.Start(ctx) }()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("running batcher: %s", err)
+	}
+	if diff := cmp.Diff([]string{"Warning Failed failed"}, recorded()); diff != "" {
+		t.Errorf("unexpected events flushed on shutdown (-want +got):\n%s", diff)
+	}
+}
+
+func TestEventRecorder_BatchedPeriodicFlush(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(100)
+	batcher := newEventBatcher(fakeRecorder, 10*time.Millisecond)
+	e := &EventRecorder{recorder: batcher, controllerName: "test"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = batcher.Start(ctx) }()
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", UID: types.UID("cm")}}
+	e.RecordEvent(obj, "Reason", "message")
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if event != "Normal Reason message" {
+			t.Errorf("unexpected event %q", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected buffered event to be flushed periodically")
+	}
+}
+
+// resourceVersionRecorder is a record.EventRecorder that records the resource version of each event's involved object.
+type resourceVersionRecorder struct {
+	record.EventRecorder
+	resourceVersions chan string
+}
+
+func (r *resourceVersionRecorder) Event(obj runtime.Object, _, _, _ string) {
+	r.resourceVersions <- obj.(*corev1.ConfigMap).ResourceVersion
+}
+
+func TestEventRecorder_BatchedCopiesObject(t *testing.T) {
+	recorder := &resourceVersionRecorder{resourceVersions: make(chan string, 1)}
+	batcher := newEventBatcher(recorder, time.Millisecond)
+	e := &EventRecorder{recorder: batcher, controllerName: "test"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = batcher.Start(ctx) }()
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", UID: types.UID("cm"), ResourceVersion: "1"}}
+	e.RecordEvent(obj, "Reason", "message")
+
+	// the caller continues mutating the object while the event is flushed (run with -race)
+	for i := range 100 {
+		obj.SetResourceVersion(fmt.Sprint(i + 2))
+	}
+
+	select {
+	case resourceVersion := <-recorder.resourceVersions:
+		if resourceVersion != "1" {
+			t.Errorf("expected event to be recorded for the object as of buffering, got resource version %q", resourceVersion)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected buffered event to be flushed periodically")
+	}
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// objectRateLimiter is a token bucket rate limiter keyed by involved object.
+type objectRateLimiter struct {
+	limit rate.Limit
+	burst int
+	// ttl is the duration after which an unused bucket has fully refilled, at which point it's indistinguishable
+	// from a new bucket and can be evicted
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	// lastEvicted is the last time stale buckets were evicted
+	lastEvicted time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// newObjectRateLimiter returns a rate limiter allowing eventsPerMinute events per object, with bursts of up to eventsPerMinute events.
+func newObjectRateLimiter(eventsPerMinute int) *objectRateLimiter {
+	return &objectRateLimiter{
+		limit:   rate.Limit(float64(eventsPerMinute) / time.Minute.Seconds()),
+		burst:   eventsPerMinute,
+		ttl:     time.Minute,
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// allow returns true if an event for the object is allowed, consuming a token from the object's bucket.
+func (l *objectRateLimiter) allow(obj client.Object) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// evict stale buckets, e.g. for deleted objects, to bound memory usage
+	if now.Sub(l.lastEvicted) >= l.ttl {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastUsed) >= l.ttl {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastEvicted = now
+	}
+
+	key := objectKey(obj)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastUsed = now
+
+	return b.limiter.AllowN(now, 1)
+}
+
+// objectKey returns the object's UID, or its namespaced name if the UID isn't populated.
+func objectKey(obj client.Object) string {
+	if uid := obj.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return client.ObjectKeyFromObject(obj).String()
+}
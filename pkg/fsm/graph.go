@@ -0,0 +1,84 @@
+package fsm
+
+import (
+	"fmt"
+	"slices"
+
+	fsmtypes "github.com/reddit/achilles-sdk/pkg/fsm/types"
+)
+
+// GraphIssueType is the type of an issue found in an FSM graph.
+type GraphIssueType string
+
+const (
+	// GraphIssueUnreachable indicates a state that isn't reachable from the initial or finalizer state.
+	GraphIssueUnreachable GraphIssueType = "Unreachable"
+	// GraphIssueSelfLoop indicates a state that declares itself as its next state. Re-entering a state within a reconcile
+	// fails the reconcile, so such a transition always fails.
+	GraphIssueSelfLoop GraphIssueType = "SelfLoop"
+)
+
+// GraphIssue is an issue found in an FSM graph by Builder.ValidateGraph.
+type GraphIssue struct {
+	// State is the name of the state.
+	State string
+	// Type is the type of issue.
+	Type GraphIssueType
+}
+
+func (i GraphIssue) String() string {
+	return fmt.Sprintf("state %q: %s", i.State, i.Type)
+}
+
+// ValidateGraph walks the FSM graph from the initial and finalizer states, following the next states declared by each
+// state's Next field, and reports states that are unreachable or loop to themselves. states are any additional states of
+// the FSM, e.g. all states of a programmatically built FSM, which are reported if unreachable.
+//
+// Validation is best-effort, since transitions determine their next state at runtime. Only next states declared through
+// State.Next are followed, so states returned by transitions without being declared are reported as unreachable.
+func (b *Builder[T, Obj]) ValidateGraph(states ...*fsmtypes.State[Obj]) []GraphIssue {
+	var issues []GraphIssue
+
+	// walk the graph in depth-first order
+	reachable := map[*fsmtypes.State[Obj]]struct{}{}
+	var walk func(state *fsmtypes.State[Obj])
+	walk = func(state *fsmtypes.State[Obj]) {
+		if state == nil {
+			return
+		}
+		if _, ok := reachable[state]; ok {
+			return
+		}
+		reachable[state] = struct{}{}
+
+		for _, next := range state.Next {
+			if next == state {
+				issues = append(issues, GraphIssue{State: state.Name, Type: GraphIssueSelfLoop})
+				continue
+			}
+			walk(next)
+		}
+	}
+	walk(b.initialState)
+	walk(b.finalizerState)
+
+	reported := map[*fsmtypes.State[Obj]]struct{}{}
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		if _, ok := reachable[state]; ok {
+			continue
+		}
+		if _, ok := reported[state]; ok {
+			continue
+		}
+		reported[state] = struct{}{}
+		issues = append(issues, GraphIssue{State: state.Name, Type: GraphIssueUnreachable})
+		if slices.Contains(state.Next, state) {
+			issues = append(issues, GraphIssue{State: state.Name, Type: GraphIssueSelfLoop})
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,36 @@
+package fsm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+func TestBuilder_ValidateGraph(t *testing.T) {
+	final := &testState{Name: "final"}
+	provision := &testState{Name: "provision", Next: []*testState{final}}
+	initial := &testState{Name: "initial", Next: []*testState{provision}}
+	cleanup := &testState{Name: "cleanup"}
+
+	// a well-formed graph has no issues
+	b := NewBuilder(&v1alpha1.TestClaim{}, initial, scheme).WithFinalizerState(cleanup)
+	if issues := b.ValidateGraph(initial, provision, final, cleanup); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+
+	// a state that's never declared as a next state is unreachable
+	orphan := &testState{Name: "orphan", Next: []*testState{final}}
+	retry := &testState{Name: "retry"}
+	retry.Next = []*testState{retry}
+	provision.Next = append(provision.Next, retry)
+
+	expected := []GraphIssue{
+		{State: "retry", Type: GraphIssueSelfLoop},
+		{State: "orphan", Type: GraphIssueUnreachable},
+	}
+	if diff := cmp.Diff(expected, b.ValidateGraph(initial, provision, retry, final, orphan, cleanup)); diff != "" {
+		t.Errorf("unexpected issues (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// triggerLogKey identifies triggers that are logged as one when coalesced.
+type triggerLogKey struct {
+	req        reconcile.Request
+	eventType  string
+	triggerGVK schema.GroupVersionKind
+}
+
+// triggerLogCoalescer counts identical triggers received within a window, logging them once the window elapses.
+type triggerLogCoalescer struct {
+	window time.Duration
+	log    func(key triggerLogKey, count int)
+
+	mu sync.Mutex
+	// pending is the number of triggers per key received within the current window
+	pending map[triggerLogKey]int
+}
+
+func newTriggerLogCoalescer(window time.Duration, log func(key triggerLogKey, count int)) *triggerLogCoalescer {
+	return &triggerLogCoalescer{
+		window:  window,
+		log:     log,
+		pending: map[triggerLogKey]int{},
+	}
+}
+
+// observe counts the trigger, starting a new window if none is in progress for the key.
+func (c *triggerLogCoalescer) observe(key triggerLogKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if count, ok := c.pending[key]; ok {
+		c.pending[key] = count + 1
+		return
+	}
+	c.pending[key] = 1
+	time.AfterFunc(c.window, func() { c.flush(key) })
+}
+
+// flush logs the triggers counted for the key within the elapsed window.
+func (c *triggerLogCoalescer) flush(key triggerLogKey) {
+	c.mu.Lock()
+	count := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+
+	c.log(key, count)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// DataChangedPredicate returns a predicate that filters out update events on ConfigMaps and Secrets that don't change
+// their data, e.g. updates of labels or annotations only. For ConfigMaps, both data and binaryData are compared.
+// Create, delete, and generic events, as well as update events on objects of other types, always pass.
+func DataChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldHash, ok := dataHash(e.ObjectOld)
+			if !ok {
+				return true
+			}
+			newHash, ok := dataHash(e.ObjectNew)
+			if !ok {
+				return true
+			}
+			return oldHash != newHash
+		},
+	}
+}
+
+// dataHash returns a hash of the object's data. Returns false if the object isn't a ConfigMap or Secret.
+func dataHash(obj client.Object) ([sha256.Size]byte, bool) {
+	var data any
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		data = []any{o.Data, o.BinaryData}
+	case *corev1.Secret:
+		data = o.Data
+	default:
+		return [sha256.Size]byte{}, false
+	}
+
+	// maps are marshalled with sorted keys, so the hash is deterministic
+	b, err := json.Marshal(data)
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(b), true
+}
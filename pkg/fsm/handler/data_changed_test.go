@@ -0,0 +1,99 @@
+package handler_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	fsmhandler "github.com/reddit/achilles-sdk/pkg/fsm/handler"
+)
+
+func TestDataChangedPredicate(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+
+	withLabel := func(obj client.Object) client.Object {
+		obj = obj.DeepCopyObject().(client.Object)
+		obj.SetLabels(map[string]string{"label": "value"})
+		return obj
+	}
+
+	changedConfigMap := configMap.DeepCopy()
+	changedConfigMap.Data["key"] = "changed"
+	binaryConfigMap := configMap.DeepCopy()
+	binaryConfigMap.BinaryData = map[string][]byte{"binary": []byte("value")}
+	changedSecret := secret.DeepCopy()
+	changedSecret.Data["key"] = []byte("changed")
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "default"}}
+
+	cases := []struct {
+		name     string
+		old      client.Object
+		new      client.Object
+		expected bool
+	}{
+		{
+			name:     "config map label change",
+			old:      configMap,
+			new:      withLabel(configMap),
+			expected: false,
+		},
+		{
+			name:     "config map data change",
+			old:      configMap,
+			new:      changedConfigMap,
+			expected: true,
+		},
+		{
+			name:     "config map binary data change",
+			old:      configMap,
+			new:      binaryConfigMap,
+			expected: true,
+		},
+		{
+			name:     "secret label change",
+			old:      secret,
+			new:      withLabel(secret),
+			expected: false,
+		},
+		{
+			name:     "secret data change",
+			old:      secret,
+			new:      changedSecret,
+			expected: true,
+		},
+		{
+			name:     "other type label change",
+			old:      deployment,
+			new:      withLabel(deployment),
+			expected: true,
+		},
+	}
+
+	p := fsmhandler.DataChangedPredicate()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := p.Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new}); actual != tc.expected {
+				t.Errorf("expected update to pass %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+
+	// creations and deletions always pass
+	if !p.Create(event.CreateEvent{Object: configMap}) {
+		t.Error("expected create to pass")
+	}
+	if !p.Delete(event.DeleteEvent{Object: configMap}) {
+		t.Error("expected delete to pass")
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -19,6 +20,10 @@ import (
 
 var _ handler.EventHandler = &ObservedEventHandler{}
 
+// IgnoreChildTriggersAnnotationKey is the annotation on a reconciled object that, when set to "true", skips reconciles
+// triggered by events on its children (see WithIgnoreChildTriggers). Events on the object itself still trigger reconciles.
+const IgnoreChildTriggersAnnotationKey = "infrared.reddit.com/ignore-child-triggers"
+
 // ObservedEventHandler wraps the underlying controller-runtime implementation with logging and metrics.
 type ObservedEventHandler struct {
 	scheme         *runtime.Scheme
@@ -29,10 +34,57 @@ type ObservedEventHandler struct {
 	// underlying handler that requests get forwarded to
 	handler     handler.EventHandler
 	triggerType TriggerType
+
+	// coalescer coalesces logs of identical triggers, nil if every trigger is logged
+	coalescer *triggerLogCoalescer
+
+	// ignoreChildTriggers, if not nil, skips child triggers of reconciled objects annotated with IgnoreChildTriggersAnnotationKey
+	ignoreChildTriggers *ignoreChildTriggers
+}
+
+type ignoreChildTriggers struct {
+	reader client.Reader
+	newObj func() client.Object
+	// enqueueOnDelete, if true, enqueues annotated objects on deletions of their children regardless
+	enqueueOnDelete bool
+}
+
+// ObservedEventHandlerOption configures an ObservedEventHandler.
+type ObservedEventHandlerOption func(*ObservedEventHandler)
+
+// WithTriggerLogWindow coalesces logs of identical triggers (i.e. of the same event on objects of the same type,
+// enqueueing the same request) received within the given window into a single log with a "count" field, logged once the window elapses.
+// This reduces log volume when bursts of events on child objects enqueue the same parent.
+// Metrics are recorded for every trigger regardless. Values <= 0 disable coalescing.
+func WithTriggerLogWindow(window time.Duration) ObservedEventHandlerOption {
+	return func(h *ObservedEventHandler) {
+		if window <= 0 {
+			h.coalescer = nil
+			return
+		}
+		h.coalescer = newTriggerLogCoalescer(window, h.logTrigger)
+	}
+}
+
+// WithIgnoreChildTriggers skips enqueueing reconciled objects annotated with IgnoreChildTriggersAnnotationKey: "true" for
+// child triggers, e.g. for objects that should reconcile on changes to their spec but not on noisy events on their children.
+// Reconciled objects are read from reader (typically the manager's cached client) into objects returned by newObj,
+// and are enqueued if they can't be read. If enqueueOnDelete is true, deletions of children still enqueue annotated
+// objects, e.g. so that deleted children are recreated.
+// Has no effect on handlers of other trigger types.
+func WithIgnoreChildTriggers(reader client.Reader, newObj func() client.Object, enqueueOnDelete bool) ObservedEventHandlerOption {
+	return func(h *ObservedEventHandler) {
+		h.ignoreChildTriggers = &ignoreChildTriggers{
+			reader:          reader,
+			newObj:          newObj,
+			enqueueOnDelete: enqueueOnDelete,
+		}
+	}
 }
 
 type observedQueue struct {
 	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	ctx        context.Context
 	handler    *ObservedEventHandler
 	eventType  string
 	triggerRef types.NamespacedName
@@ -51,8 +103,9 @@ func NewObservedEventHandler(
 	metrics *metrics.Metrics,
 	origHandler handler.EventHandler,
 	triggerType TriggerType,
+	opts ...ObservedEventHandlerOption,
 ) *ObservedEventHandler {
-	return &ObservedEventHandler{
+	h := &ObservedEventHandler{
 		log:            log,
 		scheme:         scheme,
 		controllerName: controllerName,
@@ -60,25 +113,30 @@ func NewObservedEventHandler(
 		handler:        origHandler,
 		triggerType:    triggerType,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *ObservedEventHandler) Create(ctx context.Context, evt event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
-	h.handler.Create(ctx, evt, h.observedQueue("create", evt.Object, q))
+	h.handler.Create(ctx, evt, h.observedQueue(ctx, "create", evt.Object, q))
 }
 
 func (h *ObservedEventHandler) Update(ctx context.Context, evt event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
-	h.handler.Update(ctx, evt, h.observedQueue("update", evt.ObjectNew, q))
+	h.handler.Update(ctx, evt, h.observedQueue(ctx, "update", evt.ObjectNew, q))
 }
 
 func (h *ObservedEventHandler) Delete(ctx context.Context, evt event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
-	h.handler.Delete(ctx, evt, h.observedQueue("delete", evt.Object, q))
+	h.handler.Delete(ctx, evt, h.observedQueue(ctx, "delete", evt.Object, q))
 }
 
 func (h *ObservedEventHandler) Generic(ctx context.Context, evt event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
-	h.handler.Generic(ctx, evt, h.observedQueue("generic", evt.Object, q))
+	h.handler.Generic(ctx, evt, h.observedQueue(ctx, "generic", evt.Object, q))
 }
 
 func (h *ObservedEventHandler) observedQueue(
+	ctx context.Context,
 	eventType string,
 	trigger client.Object,
 	q workqueue.TypedRateLimitingInterface[reconcile.Request],
@@ -86,6 +144,7 @@ func (h *ObservedEventHandler) observedQueue(
 	// trigger client.Object
 	return &observedQueue{
 		TypedRateLimitingInterface: q,
+		ctx:                        ctx,
 		handler:                    h,
 		eventType:                  eventType,
 		// ref to the object being reconciled (which may differ from the triggering object for owner ref based triggers)
@@ -95,11 +154,36 @@ func (h *ObservedEventHandler) observedQueue(
 }
 
 func (q *observedQueue) Add(item reconcile.Request) {
+	if q.ignored(item) {
+		q.handler.log.
+			With(fieldNameRequestObjKey, item.String()).
+			With(fieldNameEvent, q.eventType).
+			Debugf("ignoring child trigger, the annotation %s is set", IgnoreChildTriggersAnnotationKey)
+		return
+	}
 	q.observeEvent(item)
 	q.TypedRateLimitingInterface.Add(item)
 }
 
-// logs an event trigger
+// ignored returns true if the request is for a reconciled object that ignores child triggers (see WithIgnoreChildTriggers).
+func (q *observedQueue) ignored(req reconcile.Request) bool {
+	ignore := q.handler.ignoreChildTriggers
+	if ignore == nil || q.handler.triggerType != TriggerTypeChild {
+		return false
+	}
+	if q.eventType == "delete" && ignore.enqueueOnDelete {
+		return false
+	}
+
+	obj := ignore.newObj()
+	if err := ignore.reader.Get(q.ctx, req.NamespacedName, obj); err != nil {
+		// enqueue objects that can't be read, the reconciler handles missing objects
+		return false
+	}
+	return obj.GetAnnotations()[IgnoreChildTriggersAnnotationKey] == "true"
+}
+
+// records a metric for and logs an event trigger
 func (q *observedQueue) observeEvent(req reconcile.Request) {
 	triggerGVK := q.triggerGVK
 	triggerType := q.handler.triggerType.String()
@@ -113,15 +197,31 @@ func (q *observedQueue) observeEvent(req reconcile.Request) {
 		q.handler.controllerName,
 	)
 
-	// log trigger metric
-	q.handler.log.
-		With(fieldNameRequestObjKey, req.String()).
-		With(fieldNameEvent, q.eventType).
-		With(fieldNameTriggerType, triggerType).
-		With(fieldNameTriggerGroup, triggerGVK.Group).
-		With(fieldNameTriggerVersion, triggerGVK.Version).
-		With(fieldNameTriggerKind, triggerGVK.Kind).
-		With(fieldNameRequestName, req.Name).
-		With(fieldNameRequestNamespace, req.Namespace).
-		Debug(triggerMessage)
+	key := triggerLogKey{
+		req:        req,
+		eventType:  q.eventType,
+		triggerGVK: triggerGVK,
+	}
+	if q.handler.coalescer != nil {
+		q.handler.coalescer.observe(key)
+		return
+	}
+	q.handler.logTrigger(key, 0)
+}
+
+// logs an event trigger, including the number of coalesced triggers if count is positive
+func (h *ObservedEventHandler) logTrigger(key triggerLogKey, count int) {
+	log := h.log.
+		With(fieldNameRequestObjKey, key.req.String()).
+		With(fieldNameEvent, key.eventType).
+		With(fieldNameTriggerType, h.triggerType.String()).
+		With(fieldNameTriggerGroup, key.triggerGVK.Group).
+		With(fieldNameTriggerVersion, key.triggerGVK.Version).
+		With(fieldNameTriggerKind, key.triggerGVK.Kind).
+		With(fieldNameRequestName, key.req.Name).
+		With(fieldNameRequestNamespace, key.req.Namespace)
+	if count > 0 {
+		log = log.With(fieldNameCount, count)
+	}
+	log.Debug(triggerMessage)
 }
@@ -2,9 +2,11 @@ package handler_test
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -22,6 +24,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -560,3 +563,196 @@ func sortLogs(logs []observer.LoggedEntry) {
 		return aKey < bKey
 	})
 }
+
+func TestObserveEnqueue_TriggerLogWindow(t *testing.T) {
+	scheme, err := internalscheme.NewScheme()
+	if err != nil {
+		t.Fatalf("constructing scheme: %s", err)
+	}
+
+	observedZapCore, observedLogs := observer.New(zap.DebugLevel)
+	log := zap.New(observedZapCore).Sugar()
+	reg := prometheus.NewRegistry()
+	m := metrics.MustMakeMetrics(scheme, reg)
+
+	h := fsmhandler.NewObservedEventHandler(
+		log,
+		scheme,
+		controllerName,
+		m,
+		handler.EnqueueRequestForOwner(scheme, testrestmapper.TestOnlyStaticRESTMapper(scheme), &corev1.ConfigMap{}),
+		fsmhandler.TriggerTypeChild,
+		fsmhandler.WithTriggerLogWindow(500*time.Millisecond),
+	)
+
+	newChild := func(name, owner string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "v1", Kind: "ConfigMap", Name: owner},
+				},
+			},
+		}
+	}
+
+	queue := workqueue.NewTypedRateLimitingQueue(ratelimiter.NewZeroDelayManagedRateLimiter(ratelimiter.NewGlobal(1)))
+
+	// a burst of triggers for the same parent
+	const n = 5
+	for i := range n {
+		h.Update(context.TODO(), event.UpdateEvent{ObjectOld: newChild(fmt.Sprintf("child-%d", i), "parent"), ObjectNew: newChild(fmt.Sprintf("child-%d", i), "parent")}, queue)
+	}
+	// distinct parent
+	h.Update(context.TODO(), event.UpdateEvent{ObjectOld: newChild("other-child", "other-parent"), ObjectNew: newChild("other-child", "other-parent")}, queue)
+
+	// logs are deferred until the window elapses
+	assert.Equal(t, 0, observedLogs.FilterMessage("received trigger").Len())
+
+	assert.Eventually(t, func() bool {
+		return observedLogs.FilterMessage("received trigger").Len() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	counts := map[string]int64{}
+	for _, entry := range observedLogs.FilterMessage("received trigger").All() {
+		counts[entry.ContextMap()["request"].(string)] = entry.ContextMap()["count"].(int64)
+	}
+	assert.Equal(t, map[string]int64{"/parent": n, "/other-parent": 1}, counts)
+
+	// metrics are recorded for every trigger
+	assertExpectedCounterMetrics(t, reg,
+		[][]*ioprometheusclient.LabelPair{
+			{
+				newLabelPair("group", ""),
+				newLabelPair("version", "v1"),
+				newLabelPair("kind", "Namespace"),
+				newLabelPair("event", "update"),
+				newLabelPair("type", "child"),
+				newLabelPair("reqName", "other-parent"),
+				newLabelPair("reqNamespace", ""),
+				newLabelPair("controller", controllerName),
+			},
+			{
+				newLabelPair("group", ""),
+				newLabelPair("version", "v1"),
+				newLabelPair("kind", "Namespace"),
+				newLabelPair("event", "update"),
+				newLabelPair("type", "child"),
+				newLabelPair("reqName", "parent"),
+				newLabelPair("reqNamespace", ""),
+				newLabelPair("controller", controllerName),
+			},
+		},
+		[]*float64{ptr.To[float64](1), ptr.To[float64](n)},
+		"achilles_trigger",
+	)
+}
+
+func TestObserveEnqueue_IgnoreChildTriggers(t *testing.T) {
+	scheme, err := internalscheme.NewScheme()
+	if err != nil {
+		t.Fatalf("constructing scheme: %s", err)
+	}
+
+	annotated := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "annotated",
+			Annotations: map[string]string{fsmhandler.IgnoreChildTriggersAnnotationKey: "true"},
+		},
+	}
+	plain := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "plain"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(annotated, plain).Build()
+
+	newChild := func(owner string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "child-of-" + owner,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "v1", Kind: "ConfigMap", Name: owner},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name            string
+		owner           string
+		enqueueOnDelete bool
+		delete          bool
+		expected        bool
+	}{
+		{
+			name:     "annotated parent",
+			owner:    annotated.Name,
+			expected: false,
+		},
+		{
+			name:     "parent without annotation",
+			owner:    plain.Name,
+			expected: true,
+		},
+		{
+			name:     "missing parent",
+			owner:    "missing",
+			expected: true,
+		},
+		{
+			name:     "annotated parent on child deletion",
+			owner:    annotated.Name,
+			delete:   true,
+			expected: false,
+		},
+		{
+			name:            "annotated parent on child deletion with enqueue on delete",
+			owner:           annotated.Name,
+			enqueueOnDelete: true,
+			delete:          true,
+			expected:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := fsmhandler.NewObservedEventHandler(
+				zap.NewNop().Sugar(),
+				scheme,
+				controllerName,
+				metrics.MustMakeMetrics(scheme, prometheus.NewRegistry()),
+				handler.EnqueueRequestForOwner(scheme, testrestmapper.TestOnlyStaticRESTMapper(scheme), &corev1.ConfigMap{}),
+				fsmhandler.TriggerTypeChild,
+				fsmhandler.WithIgnoreChildTriggers(c, func() client.Object { return &corev1.ConfigMap{} }, tc.enqueueOnDelete),
+			)
+
+			queue := workqueue.NewTypedRateLimitingQueue(ratelimiter.NewZeroDelayManagedRateLimiter(ratelimiter.NewGlobal(1)))
+			defer queue.ShutDown()
+
+			child := newChild(tc.owner)
+			if tc.delete {
+				h.Delete(context.TODO(), event.DeleteEvent{Object: child}, queue)
+			} else {
+				h.Update(context.TODO(), event.UpdateEvent{ObjectOld: child, ObjectNew: child}, queue)
+			}
+
+			if enqueued := queue.Len() == 1; enqueued != tc.expected {
+				t.Errorf("expected enqueued %t, got %t", tc.expected, enqueued)
+			}
+		})
+	}
+
+	// triggers on the annotated object itself are unaffected
+	h := fsmhandler.NewObservedEventHandler(
+		zap.NewNop().Sugar(),
+		scheme,
+		controllerName,
+		metrics.MustMakeMetrics(scheme, prometheus.NewRegistry()),
+		&handler.EnqueueRequestForObject{},
+		fsmhandler.TriggerTypeSelf,
+		fsmhandler.WithIgnoreChildTriggers(c, func() client.Object { return &corev1.ConfigMap{} }, false),
+	)
+	queue := workqueue.NewTypedRateLimitingQueue(ratelimiter.NewZeroDelayManagedRateLimiter(ratelimiter.NewGlobal(1)))
+	defer queue.ShutDown()
+	h.Update(context.TODO(), event.UpdateEvent{ObjectOld: annotated, ObjectNew: annotated}, queue)
+	if queue.Len() != 1 {
+		t.Errorf("expected self trigger to be enqueued")
+	}
+}
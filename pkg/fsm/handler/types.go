@@ -33,5 +33,8 @@ const (
 	fieldNameRequestName      = "reqName"
 	fieldNameRequestNamespace = "reqNamespace"
 
+	// fieldNameCount is the number of identical triggers coalesced into a single log
+	fieldNameCount = "count"
+
 	triggerMessage = "received trigger"
 )
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/fsm/types"
+	"github.com/reddit/achilles-sdk/pkg/io"
+)
+
+// EvaluatedState is the outcome of a state executed during a dry evaluation of the FSM.
+type EvaluatedState struct {
+	// State is the name of the state.
+	State string
+	// Result is the result returned by the state's transition.
+	Result types.Result
+	// Outputs is the output set populated by the state's transition.
+	Outputs *types.OutputSet
+	// Condition is the state's status condition as it would be set by a reconcile, empty if the state has no condition
+	// or the reconcile was skipped.
+	Condition api.Condition
+}
+
+// Evaluate performs a dry evaluation of the FSM against obj, returning the outcome of each executed state.
+// States are executed as they would be in a reconcile, except that outputs are neither applied nor requeued, the object's
+// status isn't updated, and MaxStatesPerReconcile is ignored. Transitions run in a read-only context (see io.NewReadOnlyContext),
+// so writes through an io.ClientApplicator fail rather than reaching the kube-apiserver. obj may be mutated by transitions.
+func (r *fsmReconciler[T, Obj]) Evaluate(ctx context.Context, obj Obj) ([]EvaluatedState, error) {
+	var evaluated []EvaluatedState
+	observe := func(s EvaluatedState) {
+		evaluated = append(evaluated, s)
+	}
+
+	ctx = io.NewReadOnlyContext(r.reconcileContext(ctx, r.log))
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	if _, result, _ := r.runStates(ctx, r.log, req, obj, resumeState[Obj]{state: r.startState(obj)}, true, observe); errors.Is(result.Err, errStateLoop) {
+		return evaluated, result.Err
+	}
+	return evaluated, nil
+}
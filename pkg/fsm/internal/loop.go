@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultReconcileLoopThreshold is the default number of consecutive no-op reconciles after which a reconcile loop is suspected
+	defaultReconcileLoopThreshold = 10
+	// defaultReconcileLoopWindow is the default maximum interval between consecutive no-op reconciles of a suspected reconcile loop
+	defaultReconcileLoopWindow = time.Second
+)
+
+// reconcileLoopDetector detects suspected reconcile loops, i.e. objects that are repeatedly enqueued and reconciled
+// in quick succession even though their status doesn't change and the FSM doesn't request a requeue.
+// Such loops are typically caused by a controller perpetually mutating an object it watches.
+type reconcileLoopDetector struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	objects map[client.ObjectKey]reconcileLoopState
+}
+
+type reconcileLoopState struct {
+	// statusHash is the hash of the object's status as of the last reconcile
+	statusHash uint64
+	// reconciledAt is the time of the last reconcile
+	reconciledAt time.Time
+	// noops is the number of consecutive reconciles that left the object's status unchanged
+	noops int
+}
+
+// newReconcileLoopDetector returns a reconcileLoopDetector, or nil if threshold is negative.
+// Zero values for threshold and window are replaced by their defaults.
+func newReconcileLoopDetector(threshold int, window time.Duration) *reconcileLoopDetector {
+	if threshold < 0 {
+		return nil
+	}
+	if threshold == 0 {
+		threshold = defaultReconcileLoopThreshold
+	}
+	if window == 0 {
+		window = defaultReconcileLoopWindow
+	}
+	return &reconcileLoopDetector{
+		threshold: threshold,
+		window:    window,
+		now:       time.Now,
+		objects:   map[client.ObjectKey]reconcileLoopState{},
+	}
+}
+
+// observe records a reconcile of the object with the given status hash, and returns the number of consecutive
+// reconciles that left the object's status unchanged. Reconciles that requeue reset the count, as do reconciles
+// occurring more than the window apart.
+func (d *reconcileLoopDetector) observe(key client.ObjectKey, statusHash uint64, requeue bool) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if requeue {
+		// requeues requested by the FSM are expected to reconcile in quick succession
+		delete(d.objects, key)
+		return 0
+	}
+
+	now := d.now()
+	s, ok := d.objects[key]
+	if ok && s.statusHash == statusHash && now.Sub(s.reconciledAt) <= d.window {
+		s.noops++
+	} else {
+		s = reconcileLoopState{statusHash: statusHash}
+	}
+	s.reconciledAt = now
+	d.objects[key] = s
+
+	return s.noops
+}
+
+// suspected returns true if the number of consecutive no-op reconciles indicates a reconcile loop.
+func (d *reconcileLoopDetector) suspected(noops int) bool {
+	return noops >= d.threshold
+}
+
+// forget forgets the reconcile history of the object.
+func (d *reconcileLoopDetector) forget(key client.ObjectKey) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.objects, key)
+}
+
+// statusHash returns a hash of the object's status.
+func statusHash(obj client.Object) (uint64, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return 0, fmt.Errorf("converting object to unstructured: %w", err)
+	}
+	status, _, err := unstructured.NestedFieldNoCopy(u, "status")
+	if err != nil {
+		return 0, fmt.Errorf("getting status: %w", err)
+	}
+	// maps are marshalled with sorted keys, so the encoding is deterministic
+	b, err := json.Marshal(status)
+	if err != nil {
+		return 0, fmt.Errorf("marshalling status: %w", err)
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	return h.Sum64(), nil
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// priorityQueue is a workqueue that dequeues requests in order of descending priority, as returned by priorityFunc.
+// Requests of equal priority are dequeued in the order in which they were added.
+type priorityQueue struct {
+	priorityqueue.PriorityQueue[reconcile.Request]
+
+	priorityFunc func(reconcile.Request) int
+}
+
+// NewPriorityQueue returns a priority queue for the named controller. The priority of each added request is
+// determined by priorityFunc, overriding any priority supplied by the caller (e.g. by controller-runtime event handlers).
+func NewPriorityQueue(
+	controllerName string,
+	rateLimiter workqueue.TypedRateLimiter[reconcile.Request],
+	priorityFunc func(reconcile.Request) int,
+) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return &priorityQueue{
+		PriorityQueue: priorityqueue.New(controllerName, func(o *priorityqueue.Opts[reconcile.Request]) {
+			o.RateLimiter = rateLimiter
+		}),
+		priorityFunc: priorityFunc,
+	}
+}
+
+func (q *priorityQueue) Add(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{}, item)
+}
+
+func (q *priorityQueue) AddAfter(item reconcile.Request, after time.Duration) {
+	q.AddWithOpts(priorityqueue.AddOpts{After: after}, item)
+}
+
+func (q *priorityQueue) AddRateLimited(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{RateLimited: true}, item)
+}
+
+func (q *priorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...reconcile.Request) {
+	for _, item := range items {
+		o.Priority = q.priorityFunc(item)
+		q.PriorityQueue.AddWithOpts(o, item)
+	}
+}
+
+// NewCoalescedQueue wraps the workqueue, invoking onCoalesced whenever a request is added while already waiting in the
+// queue, i.e. when the workqueue coalesces the request with the waiting one into a single reconcile.
+// Only requests added without delay (e.g. by event handlers) are tracked, delayed and rate limited requeues are not.
+func NewCoalescedQueue(
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+	onCoalesced func(),
+) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	tracker := &waitingTracker{
+		onCoalesced: onCoalesced,
+		waiting:     map[reconcile.Request]struct{}{},
+	}
+	// preserve the priority queue interface, through which controller-runtime adds and gets requests if implemented
+	if pq, ok := queue.(priorityqueue.PriorityQueue[reconcile.Request]); ok {
+		return &coalescedPriorityQueue{PriorityQueue: pq, tracker: tracker}
+	}
+	return &coalescedQueue{TypedRateLimitingInterface: queue, tracker: tracker}
+}
+
+// waitingTracker tracks the requests waiting in a workqueue.
+type waitingTracker struct {
+	onCoalesced func()
+
+	mu      sync.Mutex
+	waiting map[reconcile.Request]struct{}
+}
+
+func (t *waitingTracker) add(item reconcile.Request) {
+	t.mu.Lock()
+	_, coalesced := t.waiting[item]
+	t.waiting[item] = struct{}{}
+	t.mu.Unlock()
+
+	if coalesced {
+		t.onCoalesced()
+	}
+}
+
+func (t *waitingTracker) get(item reconcile.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.waiting, item)
+}
+
+type coalescedQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	tracker *waitingTracker
+}
+
+func (q *coalescedQueue) Add(item reconcile.Request) {
+	q.tracker.add(item)
+	q.TypedRateLimitingInterface.Add(item)
+}
+
+func (q *coalescedQueue) Get() (reconcile.Request, bool) {
+	item, shutdown := q.TypedRateLimitingInterface.Get()
+	if !shutdown {
+		q.tracker.get(item)
+	}
+	return item, shutdown
+}
+
+type coalescedPriorityQueue struct {
+	priorityqueue.PriorityQueue[reconcile.Request]
+
+	tracker *waitingTracker
+}
+
+func (q *coalescedPriorityQueue) Add(item reconcile.Request) {
+	q.tracker.add(item)
+	q.PriorityQueue.Add(item)
+}
+
+func (q *coalescedPriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...reconcile.Request) {
+	if o.After == 0 && !o.RateLimited {
+		for _, item := range items {
+			q.tracker.add(item)
+		}
+	}
+	q.PriorityQueue.AddWithOpts(o, items...)
+}
+
+func (q *coalescedPriorityQueue) Get() (reconcile.Request, bool) {
+	item, shutdown := q.PriorityQueue.Get()
+	if !shutdown {
+		q.tracker.get(item)
+	}
+	return item, shutdown
+}
+
+func (q *coalescedPriorityQueue) GetWithPriority() (reconcile.Request, int, bool) {
+	item, priority, shutdown := q.PriorityQueue.GetWithPriority()
+	if !shutdown {
+		q.tracker.get(item)
+	}
+	return item, priority, shutdown
+}
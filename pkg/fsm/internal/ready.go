@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readyTracker tracks the last observed readiness of each object, so that ready events are recorded exactly upon
+// transitions to ready, even if the object is read from a cache that hasn't yet observed its latest status.
+//
+// NOTE: tracking is in-memory, so objects whose status reads unready when the controller restarts record a ready event
+// upon their next transition to ready.
+type readyTracker struct {
+	mu sync.Mutex
+	// a map of object key to whether the object was ready as of its last reconcile
+	ready map[client.ObjectKey]bool
+}
+
+// newReadyTracker returns a readyTracker, or nil if ready events aren't recorded.
+func newReadyTracker(enabled bool) *readyTracker {
+	if !enabled {
+		return nil
+	}
+	return &readyTracker{
+		ready: map[client.ObjectKey]bool{},
+	}
+}
+
+// becameReady records the object's readiness and returns true if the object transitioned to ready.
+// wasReady is the readiness read from the object's status, used if the object's readiness wasn't previously observed.
+func (t *readyTracker) becameReady(key client.ObjectKey, wasReady, ready bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.ready[key]; ok {
+		wasReady = last
+	}
+	t.ready[key] = ready
+
+	return ready && !wasReady
+}
+
+// forget stops tracking the object's readiness
+func (t *readyTracker) forget(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.ready, key)
+}
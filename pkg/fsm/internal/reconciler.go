@@ -16,14 +16,17 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/reddit/achilles-sdk-api/api"
 	apitypes "github.com/reddit/achilles-sdk-api/pkg/types"
+	liberrors "github.com/reddit/achilles-sdk/pkg/errors"
 	fsmio "github.com/reddit/achilles-sdk/pkg/fsm/io"
 	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
 	"github.com/reddit/achilles-sdk/pkg/fsm/types"
 	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/logging"
 	"github.com/reddit/achilles-sdk/pkg/meta"
 	"github.com/reddit/achilles-sdk/pkg/status"
 )
@@ -31,6 +34,12 @@ import (
 const (
 	deletedStateName = "deleted"
 	finalizerKey     = "infrared.reddit.com/fsm"
+
+	// pausedReason is the result reason for a reconcile paused because MaxStatesPerReconcile was reached
+	pausedReason = "MaxStatesPerReconcile"
+	// resumeRequeueAfter is the requeue duration after MaxStatesPerReconcile is reached,
+	// a fixed (rather than zero) duration ensures the requeue isn't subject to rate limiting
+	resumeRequeueAfter = time.Millisecond
 )
 
 var errStateLoop = errors.New("re-entered state")
@@ -47,7 +56,62 @@ type fsmReconciler[T any, Obj apitypes.FSMResource[T]] struct {
 
 	metrics *metrics.Metrics
 
+	// customMetrics records user-defined metrics from transitions, nil if no custom metrics registerer is configured
+	customMetrics *metrics.CustomMetricsRecorder
+
 	reconcilerOptions types.ReconcilerOptions[T, Obj]
+
+	requeueSource *requeueSource
+
+	// missingRefTracker delays pruning of refs for missing managed resources, nil if no grace period is configured
+	missingRefTracker *fsmio.MissingRefTracker
+
+	// resumeStates tracks the state to resume from for objects whose reconcile was paused due to MaxStatesPerReconcile
+	resumeStates *resumeStates[Obj]
+
+	// loopDetector detects suspected reconcile loops, nil if detection is disabled
+	loopDetector *reconcileLoopDetector
+
+	// transientErrors delays surfacing transient errors on status conditions, nil if no grace period is configured
+	transientErrors *transientErrorTracker
+
+	// readyTracker tracks readiness transitions for recording ready events, nil if ready events aren't recorded
+	readyTracker *readyTracker
+
+	// requeueEvents deduplicates requeue events, nil if requeue events aren't recorded
+	requeueEvents *requeueEventTracker
+
+	// statusSize detects status fields exceeding their size threshold, nil if the check is disabled
+	statusSize *statusSizeGuard
+
+	// stateObserver, if set, is invoked with the name of each state as it's entered
+	stateObserver func(state string)
+}
+
+// newCustomMetricsRecorder returns a recorder for user-defined metrics labeled with the reconciled object's GVK,
+// or nil if no custom metrics registerer is configured.
+func newCustomMetricsRecorder[T any, Obj apitypes.FSMResource[T]](
+	scheme *runtime.Scheme,
+	metricsOptions types.MetricsOptions,
+) *metrics.CustomMetricsRecorder {
+	if metricsOptions.CustomMetricsRegisterer == nil {
+		return nil
+	}
+	gvk := meta.MustGVKForObject(Obj(new(T)), scheme)
+	return metrics.NewCustomMetrics(metricsOptions.CustomMetricsRegisterer).ForGVK(gvk)
+}
+
+// Reconciler is an FSM reconciler.
+type Reconciler interface {
+	reconcile.TypedReconciler[ctrl.Request]
+
+	// RequeueSource returns a source that must be watched by the reconciler's controller
+	// in order for objects requeued through OutputSet.RequeueRef to be enqueued.
+	RequeueSource() source.Source
+
+	// SetStateObserver sets a function invoked with the name of each state as it's entered, used for tracing FSM execution in tests.
+	// NOTE: not thread-safe, must be called before the reconciler is started.
+	SetStateObserver(observer func(state string))
 }
 
 func NewFSMReconciler[T any, Obj apitypes.FSMResource[T]](
@@ -70,6 +134,11 @@ func NewFSMReconciler[T any, Obj apitypes.FSMResource[T]](
 		reconcilerOptions.CreateFunc = types.DefaultCreateFunc[T, Obj]
 	}
 
+	var missingRefTracker *fsmio.MissingRefTracker
+	if reconcilerOptions.ManagedResourceRefGracePeriod > 0 {
+		missingRefTracker = fsmio.NewMissingRefTracker(reconcilerOptions.ManagedResourceRefGracePeriod)
+	}
+
 	return &fsmReconciler[T, Obj]{
 		log:               log,
 		client:            client,
@@ -79,17 +148,45 @@ func NewFSMReconciler[T any, Obj apitypes.FSMResource[T]](
 		finalizerState:    finalizerState,
 		managedTypes:      managedTypesMap,
 		metrics:           metrics,
+		customMetrics:     newCustomMetricsRecorder[T, Obj](scheme, reconcilerOptions.MetricsOptions),
 		reconcilerOptions: reconcilerOptions,
+		requeueSource:     &requeueSource{},
+		missingRefTracker: missingRefTracker,
+		resumeStates:      newResumeStates[Obj](),
+		loopDetector:      newReconcileLoopDetector(reconcilerOptions.ReconcileLoopThreshold, reconcilerOptions.ReconcileLoopWindow),
+		transientErrors:   newTransientErrorTracker(reconcilerOptions.TransientErrorGracePeriod),
+		readyTracker:      newReadyTracker(reconcilerOptions.ReadyEventRecorder != nil),
+		requeueEvents:     newRequeueEventTracker(reconcilerOptions.RequeueEventRecorder != nil),
+		statusSize:        newStatusSizeGuard(reconcilerOptions.StatusConditionsThreshold, reconcilerOptions.StatusManagedResourcesThreshold),
 	}
 }
 
+func (r *fsmReconciler[T, Obj]) RequeueSource() source.Source {
+	return r.requeueSource
+}
+
+func (r *fsmReconciler[T, Obj]) SetStateObserver(observer func(state string)) {
+	r.stateObserver = observer
+}
+
 func (r *fsmReconciler[T, Obj]) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
-	requestId := ctrlcontroller.ReconcileIDFromContext(ctx)
-	log := r.log.With("request", req, "requestId", requestId)
+	log := logging.ReconcileLogger(ctx, r.log.With("request", req), r.reconcilerOptions.TraceIDsFunc)
 	log.Debug("entering reconcile")
 	startedAt := time.Now()
 	defer func() { log.Debugf("finished reconcile in %s", time.Since(startedAt)) }()
 
+	if pauseSwitch := r.reconcilerOptions.PauseSwitch; pauseSwitch != nil {
+		paused := pauseSwitch.Load()
+		r.metrics.RecordControllerPaused(r.name, paused)
+		if paused {
+			log.Debug("skipping reconcile, controller is paused")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// record reconcile result
+	defer func() { r.metrics.RecordReconcileResult(r.name, res, err) }()
+
 	// record metrics
 	defer func() {
 		// fetch the object's latest state
@@ -100,6 +197,10 @@ func (r *fsmReconciler[T, Obj]) Reconcile(ctx context.Context, req ctrl.Request)
 			}
 			return
 		}
+		if !r.matchesFilter(obj) {
+			// metrics of filtered objects are deleted
+			return
+		}
 
 		// record status condition metric for custom condition types
 		for _, conditionType := range r.reconcilerOptions.MetricsOptions.ConditionTypes {
@@ -116,53 +217,250 @@ func (r *fsmReconciler[T, Obj]) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 
 		// record object readiness
-		r.metrics.RecordReadiness(obj)
+		r.metrics.RecordReadinessForType(obj, r.readyConditionType())
+		r.metrics.RecordConditionAge(obj, r.readyConditionType())
+
+		// record number of managed resources
+		r.metrics.RecordManagedResources(obj, len(obj.GetManagedResources()))
 	}()
 
-	obj, conditions, result := r.reconcile(ctx, req, log)
+	ctx = r.reconcileContext(ctx, log)
+
+	reconcileStartedAt := time.Now()
+	obj, conditions, result, paused := r.reconcile(ctx, req, log)
+	r.metrics.RecordActiveReconcileDuration(r.name, time.Since(reconcileStartedAt))
 	if obj == nil {
 		return result.Get(log)
 	}
 
-	// merge computed conditions
-	if conditions != nil {
-		// set top level ready status condition
-		if !r.reconcilerOptions.DisableReadyCondition {
-			readyCondition := status.NewReadyCondition(obj.GetGeneration(), conditions.GetConditions()...)
-			conditions.SetConditions(readyCondition)
-		}
+	if r.toleratesTransientError(req, result) {
+		// leave the object's status conditions unchanged so that blips in connectivity to the kube-apiserver don't surface as failed conditions,
+		// the rest of the status (e.g. managed resource refs of outputs already applied) is still updated
+		log.Infof("leaving status conditions unchanged for transient error: %s", result.Err)
+		conditions = nil
+	}
+
+	// skipped reconciles leave the object's status unchanged
+	if !result.Skipped {
+		wasReady := obj.GetCondition(r.readyConditionType()).Status == corev1.ConditionTrue
+
+		// merge computed conditions, a nil condition set leaves the object's existing conditions unchanged
+		if conditions != nil {
+			// set top level ready status condition
+			if !r.reconcilerOptions.DisableReadyCondition {
+				readyCondition := status.NewReadyCondition(obj.GetGeneration(), conditions.GetConditions()...)
+				readyCondition.Type = r.readyConditionType()
+				if r.reconcilerOptions.PropagateChildReadiness && readyCondition.Status != corev1.ConditionTrue {
+					children, err := r.childReadyConditions(ctx, obj)
+					if err != nil {
+						log.Errorf("reading ready conditions of managed resources: %s", err)
+					}
+					readyCondition = status.WithChildCause(readyCondition, children...)
+				}
+				conditions.SetConditions(readyCondition)
+			}
 
-		obj.SetConditions(conditions.GetConditions()...)
+			obj.SetConditions(r.truncateConditionMessages(conditions.GetConditions())...)
+		}
 
 		// NOTE: status must be updated upon termination of FSM, otherwise steady state won't be reached because
 		// later states that overwrite status conditions of earlier states will trigger reconcile events
 		if err := r.client.ApplyStatus(ctx, obj); err != nil {
 			return ctrl.Result{}, fmt.Errorf("updating status: %w", err)
 		}
+
+		r.detectReconcileLoop(log, req, obj, result)
+		r.checkStatusSize(log, req, obj)
+
+		if r.readyTracker != nil && !r.reconcilerOptions.DisableReadyCondition {
+			ready := obj.GetCondition(r.readyConditionType()).Status == corev1.ConditionTrue
+			if r.readyTracker.becameReady(req.NamespacedName, wasReady, ready) {
+				r.reconcilerOptions.ReadyEventRecorder.RecordReady(obj, "")
+			}
+		}
 	}
 
+	r.recordRequeueEvent(req, obj, result)
+
 	// For FSMs with finalizer states, remove finalizer when finalizer states have been completed.
 	// NB: If the object has a non-zero deletion timestamp, its finalizer states are guaranteed to be processed
 	// But this invariant relies on the object never being fetched from the server mid-reconcile,
 	// which can lead to `meta.WasDeleted(obj) == true` and `result.IsDone()` without the finalizer states
 	// having been processed (if, for instance, an external actor deletes the object after `r.reconcile(ctx, req)`
 	// and before this condition.
-	if meta.WasDeleted(obj) && r.finalizerState != nil && result.IsDone() {
+	// Skipped reconciles haven't completed the finalizer states, so the finalizer is retained.
+	if meta.WasDeleted(obj) && r.finalizerState != nil && result.IsDone() && !result.Skipped {
 		if err := meta.RemoveFinalizer(ctx, r.client, obj, finalizerKey); err != nil {
 			return ctrl.Result{}, fmt.Errorf("removing FSM finalizer: %w", err)
 		}
 	}
 
+	// refs of a deleted object's managed resources are no longer pruned, so its missing refs are no longer tracked
+	if meta.WasDeleted(obj) && result.IsDone() && !result.Skipped {
+		r.forgetMissingRefs(obj)
+	}
+
+	// the reconciler's own requeue for resuming a paused reconcile isn't subject to the minimum requeue interval
+	if !paused {
+		result = result.WithMinRequeueInterval(r.reconcilerOptions.MinRequeueInterval)
+	}
+
 	return result.Get(log)
 }
 
+// reconcileContext returns the context in which the FSM's states are executed.
+func (r *fsmReconciler[T, Obj]) reconcileContext(ctx context.Context, log *zap.SugaredLogger) context.Context {
+	// transitions may retrieve the reconcile-scoped logger through logging.FromContext
+	ctx = logging.NewContext(ctx, log)
+	if r.customMetrics != nil {
+		ctx = metrics.NewCustomMetricsContext(ctx, r.customMetrics)
+	}
+	if len(r.reconcilerOptions.DefaultReadyFuncs) > 0 {
+		ctx = types.NewDefaultReadyFuncsContext(ctx, r.reconcilerOptions.DefaultReadyFuncs...)
+	}
+	if r.reconcilerOptions.ReconcileReadCache {
+		ctx = io.NewReadCacheContext(ctx)
+	}
+	return ctx
+}
+
+// truncateConditionMessages truncates the messages of the conditions to MaxConditionMessageLength, if configured.
+func (r *fsmReconciler[T, Obj]) truncateConditionMessages(conditions []api.Condition) []api.Condition {
+	maxLength := r.reconcilerOptions.MaxConditionMessageLength
+	if maxLength <= 0 {
+		return conditions
+	}
+	for i := range conditions {
+		conditions[i].Message = status.TruncateMessage(conditions[i].Message, maxLength)
+	}
+	return conditions
+}
+
+// recordRequeueEvent records the Warning event signaled by the result, unless the object's previous reconcile recorded
+// an event of the same reason.
+func (r *fsmReconciler[T, Obj]) recordRequeueEvent(req ctrl.Request, obj Obj, result types.Result) {
+	if r.requeueEvents == nil {
+		return
+	}
+	if !result.RecordEvent {
+		r.requeueEvents.forget(req.NamespacedName)
+		return
+	}
+
+	_, reason := result.GetMessageAndReason()
+	if r.requeueEvents.shouldRecord(req.NamespacedName, string(reason)) {
+		r.reconcilerOptions.RequeueEventRecorder.RecordWarning(obj, string(reason), result.RequeueMsg)
+	}
+}
+
+// readyConditionType returns the type of the top-level status condition rolling up all other status conditions.
+func (r *fsmReconciler[T, Obj]) readyConditionType() api.ConditionType {
+	if r.reconcilerOptions.ReadyConditionType != "" {
+		return r.reconcilerOptions.ReadyConditionType
+	}
+	return api.TypeReady
+}
+
+// childReadyConditions returns the "Ready" status conditions of the object's managed resources.
+// Managed resources that are not found, or don't have a "Ready" status condition, are skipped.
+func (r *fsmReconciler[T, Obj]) childReadyConditions(ctx context.Context, obj Obj) ([]status.ChildCondition, error) {
+	var children []status.ChildCondition
+	for _, ref := range obj.GetManagedResources() {
+		child, err := meta.NewObjectForGVK(r.scheme, ref.GroupVersionKind())
+		if err != nil {
+			return children, err
+		}
+		conditioned, ok := child.(api.Conditioned)
+		if !ok {
+			continue
+		}
+
+		if err := r.client.Get(ctx, ref.ObjectKey(), child); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return children, fmt.Errorf("getting managed resource %s %s: %w", ref.Kind, ref.ObjectKey(), err)
+		}
+
+		ready := conditioned.GetCondition(api.TypeReady)
+		if ready.Type != api.TypeReady {
+			continue
+		}
+		children = append(children, status.ChildCondition{
+			Child:     fmt.Sprintf("%s %s", ref.Kind, ref.ObjectKey()),
+			Condition: ready,
+		})
+	}
+	return children, nil
+}
+
+// toleratesTransientError returns true if the result's error is transient and the object's reconciles have been failing
+// with transient errors for less than the configured grace period.
+func (r *fsmReconciler[T, Obj]) toleratesTransientError(req ctrl.Request, result types.Result) bool {
+	if r.transientErrors == nil {
+		return false
+	}
+	if !liberrors.IsTransient(result.Err) {
+		r.transientErrors.forget(req.NamespacedName)
+		return false
+	}
+	return r.transientErrors.tolerate(req.NamespacedName)
+}
+
+// matchesFilter returns true if the object matches the reconcile filter, if any.
+func (r *fsmReconciler[T, Obj]) matchesFilter(obj Obj) bool {
+	return r.reconcilerOptions.ReconcileFilter == nil || r.reconcilerOptions.ReconcileFilter.Matches(obj)
+}
+
+// forget discards all state tracked for the object, and deregisters its metrics
+// (to keep metrics cardinality count from monotonically increasing over an application's lifetime).
+func (r *fsmReconciler[T, Obj]) forget(req ctrl.Request, obj Obj) {
+	r.resumeStates.forget(req.NamespacedName)
+	r.forgetMissingRefs(obj)
+	if r.loopDetector != nil {
+		r.loopDetector.forget(req.NamespacedName)
+	}
+	if r.transientErrors != nil {
+		r.transientErrors.forget(req.NamespacedName)
+	}
+	if r.readyTracker != nil {
+		r.readyTracker.forget(req.NamespacedName)
+	}
+	if r.requeueEvents != nil {
+		r.requeueEvents.forget(req.NamespacedName)
+	}
+	if r.statusSize != nil {
+		r.statusSize.forget(req.NamespacedName)
+	}
+
+	r.metrics.DeleteTrigger(req.NamespacedName, r.name)
+	r.metrics.DeleteReadinessForType(obj, r.readyConditionType())
+	r.metrics.DeleteEvent(obj)
+	r.metrics.DeleteSuspectedReconcileLoop(obj)
+	r.metrics.DeleteStatusSizeExceeded(obj)
+	r.metrics.DeleteManagedResources(obj)
+	r.metrics.DeleteConditionAge(obj, r.readyConditionType())
+
+	for _, conditionType := range r.reconcilerOptions.MetricsOptions.ConditionTypes {
+		r.metrics.DeleteCondition(obj, conditionType)
+	}
+}
+
+// forgetMissingRefs stops tracking the missing managed resource refs of the object.
+func (r *fsmReconciler[T, Obj]) forgetMissingRefs(obj Obj) {
+	if r.missingRefTracker != nil {
+		r.missingRefTracker.ForgetParent(*meta.MustTypedObjectRefFromObject(obj, r.scheme))
+	}
+}
+
 // reconcile the object through a sequence of FSM states
-// return the mutated object, status conditions (one per FSM state), and result
+// return the mutated object, status conditions (one per FSM state), result, and whether the reconcile was paused
+// because MaxStatesPerReconcile was reached
 func (r *fsmReconciler[T, Obj]) reconcile(
 	ctx context.Context,
 	req ctrl.Request,
 	log *zap.SugaredLogger,
-) (Obj, api.Conditioned, types.Result) {
+) (Obj, api.Conditioned, types.Result, bool) {
 	obj := Obj(new(T))
 	if err := r.client.Get(ctx, req.NamespacedName, obj); k8serrors.IsNotFound(err) {
 		// object not found, meaning that it has been deleted (not merely in terminating state)
@@ -173,71 +471,109 @@ func (r *fsmReconciler[T, Obj]) reconcile(
 			if obj != nil {
 				// already exists error can occur if the CreateFunc sets the object name to something other than req.Name
 				if err := r.client.Create(ctx, obj); client.IgnoreAlreadyExists(err) != nil {
-					return nil, nil, types.ErrorResult(fmt.Errorf("creating object %s: %w", req.NamespacedName, err))
+					return nil, nil, types.ErrorResult(fmt.Errorf("creating object %s: %w", req.NamespacedName, err)), false
 				}
 				// NOTE: wait for next reconcile before updating status to reduce "object does not exist, cannot update its status" errors
-				return nil, nil, types.DoneResult()
+				return nil, nil, types.DoneResult(), false
 			}
 
 			// If obj is nil, the caller signals that the object should not be created. This is primarily used by callers to prevent
 			// stale requests in the event queue from triggering unneeded object creations.
 		}
 
-		// deregister metrics for deleted objects (to keep metrics cardinality count from monotonically increasing over an application's lifetime)
-		r.metrics.DeleteTrigger(req.NamespacedName, r.name)
-
 		obj.SetName(req.Name)
 		obj.SetNamespace(req.Namespace)
-		r.metrics.DeleteReadiness(obj)
-		r.metrics.DeleteEvent(obj)
-
-		for _, conditionType := range r.reconcilerOptions.MetricsOptions.ConditionTypes {
-			r.metrics.DeleteCondition(obj, conditionType)
-		}
+		r.forget(req, obj)
 
-		return nil, nil, types.DoneResult()
+		return nil, nil, types.DoneResult(), false
 	} else if err != nil {
-		return nil, nil, types.ErrorResult(fmt.Errorf("getting %T: %w", obj, err))
+		return nil, nil, types.ErrorResult(fmt.Errorf("getting %T: %w", obj, err)), false
+	}
+
+	if !r.matchesFilter(obj) {
+		log.Debug("skipping reconciliation, object doesn't match the reconcile filter")
+		r.forget(req, obj)
+		return nil, nil, types.DoneResult(), false
 	}
 
 	isSuspended := meta.HasSuspendLabel(obj)
 	r.metrics.RecordSuspend(obj, isSuspended)
 	if isSuspended {
 		log.Infof("Skipping reconciliation, the label %s is set", meta.SuspendKey)
-		return nil, nil, types.DoneResult()
+		return nil, nil, types.DoneResult(), false
 	}
 
 	// ensure finalizer if finalizer states exist, do not add if the resource has already been deleted
 	// as no new finalizers can be added to the resource
 	if r.finalizerState != nil && !slices.Contains(obj.GetFinalizers(), finalizerKey) && !meta.WasDeleted(obj) {
 		if err := meta.AddFinalizer(ctx, r.client, obj, finalizerKey); err != nil {
-			return nil, nil, types.ErrorResult(fmt.Errorf("adding FSM finalizer: %w", err))
+			return nil, nil, types.ErrorResult(fmt.Errorf("adding FSM finalizer: %w", err)), false
 		}
 	}
 
-	// transition through states
-	currentState := r.initialState
-	// transition through finalizer states
+	// transition through states, or finalizer states if the object was deleted
+	start := resumeState[Obj]{state: r.startState(obj)}
+
+	// resume from the state following the last executed state if the previous reconcile was paused,
+	// carrying over the conditions and requeue after completion of the states executed before pausing
+	// NOTE: the resume state is consumed so that the following reconcile starts from the initial state
+	if resumed, ok := r.resumeStates.pop(req.NamespacedName, obj); ok && !meta.WasDeleted(obj) {
+		log.Debugw("resuming paused reconcile", "state", resumed.state.Name)
+		start = resumed
+	}
+
+	conditions, result, paused := r.runStates(ctx, log, req, obj, start, false, nil)
+	return obj, conditions, result, paused
+}
+
+// startState returns the state from which the FSM is evaluated for the object, i.e. the initial state,
+// or the finalizer state if the object was deleted.
+func (r *fsmReconciler[T, Obj]) startState(obj Obj) *types.State[Obj] {
 	if meta.WasDeleted(obj) {
-		currentState = DeletedStateFor(r) // default deleted state when finalizer states aren't provided
 		if r.finalizerState != nil {
-			currentState = r.finalizerState
+			return r.finalizerState
 		}
+		return DeletedStateFor(r) // default deleted state when finalizer states aren't provided
 	}
+	return r.initialState
+}
 
+// runStates transitions the object through a sequence of FSM states beginning at start's state,
+// returning the status conditions (one per FSM state), result, and whether the reconcile was paused because
+// MaxStatesPerReconcile was reached. The conditions and requeue after completion of start, if any, are those of
+// states executed by a previous paused reconcile.
+// If dryRun is true, outputs are neither applied nor requeued, and MaxStatesPerReconcile is ignored.
+// observe, if not nil, is invoked with the outcome of each executed state.
+func (r *fsmReconciler[T, Obj]) runStates(
+	ctx context.Context,
+	log *zap.SugaredLogger,
+	req ctrl.Request,
+	obj Obj,
+	start resumeState[Obj],
+	dryRun bool,
+	observe func(EvaluatedState),
+) (api.Conditioned, types.Result, bool) {
 	// empty object for accumulating conditions
 	conditions := Obj(new(T))
+	conditions.SetConditions(start.conditions...)
 
 	// transition state
+	currentState := start.state
 	seenStates := sets.NewString()
 
-	var requeueAfterCompletion types.Result
+	requeueAfterCompletion := start.requeueAfterCompletion
+	var statesExecuted int
+	// retainedRefsRequeue is the smallest remaining grace period of managed resource refs retained for missing objects
+	var retainedRefsRequeue time.Duration
 
 	for currentState != nil {
 		log.Debugw("entering state", "state", currentState.Name)
+		if r.stateObserver != nil {
+			r.stateObserver(currentState.Name)
+		}
 		// record seen states to prevent loops
 		if seenStates.Has(currentState.Name) {
-			return obj, conditions, types.ErrorResult(fmt.Errorf("%w %q", errStateLoop, currentState.Name))
+			return conditions, types.ErrorResult(fmt.Errorf("%w %q", errStateLoop, currentState.Name)), false
 		}
 		seenStates.Insert(currentState.Name)
 
@@ -249,15 +585,31 @@ func (r *fsmReconciler[T, Obj]) reconcile(
 		// transition if a transition func is defined, else it's a terminal state
 		var next *types.State[Obj]
 
-		var result types.Result
+		result := types.DoneResult()
+		observeState := func(condition api.Condition) {
+			if observe != nil {
+				observe(EvaluatedState{State: currentState.Name, Result: result, Outputs: out, Condition: condition})
+			}
+		}
+
 		if currentState.Transition != nil {
 			// obj, managedResources, and out can be mutated
 
 			start := time.Now()
 			next, result = currentState.Transition(ctx, obj, out)
 
+			duration := time.Since(start)
 			typedObjectRef := meta.MustTypedObjectRefFromObject(obj, r.scheme)
-			r.metrics.RecordStateDuration(typedObjectRef.GroupVersionKind(), currentState.Name, time.Since(start))
+			r.metrics.RecordStateDuration(typedObjectRef.GroupVersionKind(), currentState.Name, duration)
+			logStateResult(log, currentState.Name, duration, result)
+
+			if result.IsDone() && result.Skipped {
+				log.Debugw("skipping reconciliation", "state", currentState.Name, "reason", result.Reason)
+				r.metrics.RecordReconcileSkipped(r.name, string(result.Reason))
+				observeState(api.Condition{})
+				// leave the object's status unchanged
+				return nil, result, false
+			}
 
 			condition.LastTransitionTime = metav1.Now() // set status condition last transition time
 			condition.Status = corev1.ConditionTrue     // default status condition to true if state is done
@@ -278,33 +630,56 @@ func (r *fsmReconciler[T, Obj]) reconcile(
 					condition.Message, condition.Reason = result.GetMessageAndReason()
 					conditions.SetConditions(condition)
 				}
-				return obj, conditions, result.WrapError(fmt.Sprintf("transitioning state %q", currentState.Name))
+				observeState(condition)
+				return conditions, result.WrapError(fmt.Sprintf("transitioning state %q", currentState.Name)), false
 			} else if result.CustomStatusCondition != nil {
 				condition.Status = result.CustomStatusCondition.Status
 				condition.Reason = result.CustomStatusCondition.Reason
 				condition.Message = result.CustomStatusCondition.Message
+				condition.ObservedGeneration = result.CustomStatusCondition.ObservedGeneration
+				condition = status.WithObservedGeneration(condition, obj.GetGeneration())
 			}
 		}
 
-		if err := r.applyOutputs(ctx, log, obj, out); err != nil {
-			// Mark the state's condition as failed since outputs couldn't be applied
-			if !condition.IsEmpty() {
-				condition.Status = corev1.ConditionFalse
-				condition.Reason = "ApplyOutputsFailed"
-				condition.Message = fmt.Sprintf("Failed to apply outputs: %v", err)
-				conditions.SetConditions(condition)
+		if !dryRun {
+			remaining, err := r.applyOutputs(ctx, log, obj, out)
+			if remaining > 0 && (retainedRefsRequeue == 0 || remaining < retainedRefsRequeue) {
+				retainedRefsRequeue = remaining
 			}
-			return obj, conditions, types.ErrorResult(fmt.Errorf("applying outputs: %w", err))
+			if err != nil {
+				// Mark the state's condition as failed since outputs couldn't be applied
+				if !condition.IsEmpty() {
+					condition.Status = corev1.ConditionFalse
+					condition.Reason = "ApplyOutputsFailed"
+					message, _ := status.SanitizeErrorMessage(err)
+					condition.Message = fmt.Sprintf("Failed to apply outputs: %s", message)
+					conditions.SetConditions(condition)
+				}
+				return conditions, types.ErrorResult(fmt.Errorf("applying outputs: %w", err)), false
+			}
+
+			r.requeueRefs(log, req, out.ListRequeueRefs())
 		}
 
 		// accumulate status conditions, overwrites duplicate conditions with those of later states
 		if !condition.IsEmpty() {
 			conditions.SetConditions(condition)
 		}
+		observeState(condition)
 
 		// for requeue results (excluding requeues after completion), requeue instead of proceeding to the following state
 		if result.HasRequeue() && !result.RequeueAfterCompletion {
-			return obj, conditions, result
+			return conditions, requeueForRetainedRefs(result, retainedRefsRequeue), false
+		}
+
+		// pause and requeue if the maximum number of states per reconcile is reached
+		statesExecuted++
+		if maxStates := r.reconcilerOptions.MaxStatesPerReconcile; !dryRun && maxStates > 0 && statesExecuted >= maxStates && next != nil {
+			r.resumeStates.push(req.NamespacedName, obj, next, conditions.GetConditions(), requeueAfterCompletion)
+
+			msg := fmt.Sprintf("executed maximum of %d states per reconcile, resuming at state %q", maxStates, next.Name)
+			// leave the object's conditions unchanged until the FSM completes so that readiness doesn't flap between paused reconciles
+			return nil, types.RequeueResultWithReason(msg, pausedReason, resumeRequeueAfter), true
 		}
 
 		// update state
@@ -316,24 +691,188 @@ func (r *fsmReconciler[T, Obj]) reconcile(
 		result = requeueAfterCompletion
 	}
 
-	return obj, conditions, result
+	return conditions, requeueForRetainedRefs(result, retainedRefsRequeue), false
 }
 
+// requeueForRetainedRefs returns the result requeued no later than retainedRefsRequeue, the smallest remaining grace
+// period of managed resource refs retained for missing objects, so that the refs are pruned once their grace period elapses.
+// Error results and requeues with exponential backoff are returned unmodified.
+func requeueForRetainedRefs(result types.Result, retainedRefsRequeue time.Duration) types.Result {
+	if retainedRefsRequeue <= 0 || result.Err != nil {
+		return result
+	}
+	if result.RequeueMsg == "" {
+		result.RequeueMsg = "retaining managed resource refs of missing objects until their grace period elapses"
+		result.RequeueAfter = retainedRefsRequeue
+	} else if result.RequeueAfter > retainedRefsRequeue {
+		result.RequeueAfter = retainedRefsRequeue
+	}
+	return result
+}
+
+// logStateResult debug-logs the outcome and duration of a state's transition.
+func logStateResult(log *zap.SugaredLogger, state string, duration time.Duration, result types.Result) {
+	outcome := "done"
+	if result.Err != nil {
+		outcome = "error"
+	} else if !result.IsDone() || result.HasRequeue() {
+		outcome = "requeue"
+	}
+
+	keysAndValues := []interface{}{
+		"state", state,
+		"duration_ms", duration.Milliseconds(),
+		"result", outcome,
+		"reason", result.Reason,
+	}
+	if result.Err != nil {
+		keysAndValues = append(keysAndValues, "error", result.Err)
+	}
+	log.Debugw("exiting state", keysAndValues...)
+}
+
+// detectReconcileLoop records a suspected reconcile loop if the object is repeatedly reconciled without changes to its status.
+// Reconciles that requeue, for instance while waiting on a managed resource, aren't counted towards a loop.
+func (r *fsmReconciler[T, Obj]) detectReconcileLoop(
+	log *zap.SugaredLogger,
+	req ctrl.Request,
+	obj Obj,
+	result types.Result,
+) {
+	if r.loopDetector == nil {
+		return
+	}
+
+	hash, err := statusHash(obj)
+	if err != nil {
+		log.Errorf("hashing status for reconcile loop detection: %s", err)
+		return
+	}
+
+	noops := r.loopDetector.observe(req.NamespacedName, hash, !result.IsDone() || result.HasRequeue())
+	suspected := r.loopDetector.suspected(noops)
+	// warn once per loop rather than on every reconcile
+	if noops == r.loopDetector.threshold {
+		log.Warnf("suspected reconcile loop, object reconciled %d consecutive times without status changes, "+
+			"check for controllers (including this one) repeatedly updating the object or its watched resources", noops+1)
+	}
+	r.metrics.RecordSuspectedReconcileLoop(obj, suspected)
+}
+
+// checkStatusSize records whether the object's status conditions and managed resource refs exceed their size threshold,
+// warning when they first do, as unbounded growth typically indicates a controller bug.
+func (r *fsmReconciler[T, Obj]) checkStatusSize(
+	log *zap.SugaredLogger,
+	req ctrl.Request,
+	obj Obj,
+) {
+	if r.statusSize == nil {
+		return
+	}
+
+	for field, size := range map[string]int{
+		statusFieldConditions:   len(obj.GetConditions()),
+		statusFieldResourceRefs: len(obj.GetManagedResources()),
+	} {
+		checked, exceeded, newlyExceeded := r.statusSize.observe(req.NamespacedName, field, size)
+		if !checked {
+			continue
+		}
+		if newlyExceeded {
+			log.Warnf("status field %q has %d entries, exceeding its threshold of %d, check for entries that are never removed "+
+				"or raise the threshold if expected", field, size, r.statusSize.thresholds[field])
+		}
+		r.metrics.RecordStatusSizeExceeded(obj, field, exceeded)
+	}
+}
+
+// applyOutputs applies the state's outputs, returning the smallest remaining grace period of managed resource refs
+// retained for missing objects, or zero if none are retained.
 func (r *fsmReconciler[T, Obj]) applyOutputs(
 	ctx context.Context,
 	log *zap.SugaredLogger,
 	obj Obj,
 	outputSet *types.OutputSet,
-) error {
+) (time.Duration, error) {
 	for _, res := range outputSet.ListApplied() {
 		// guard against undeclared output types
 		gvk := meta.MustGVKForObject(res, r.scheme)
 		if _, ok := r.managedTypes[gvk]; !ok {
 			log.DPanicf("unrecognized output resource type %s, must be added to managed types", gvk)
 		}
-		meta.SetRedditLabels(res, r.name)
+		if keys, ok := r.reconcilerOptions.RedditLabelKeys[gvk]; ok {
+			meta.SetRedditLabelKeys(res, r.name, keys...)
+		} else {
+			meta.SetRedditLabels(res, r.name)
+		}
+	}
+	for _, prune := range outputSet.ListPrunes() {
+		// guard against undeclared pruned types, whose objects can't be owned by the reconciled object
+		if _, ok := r.managedTypes[prune.GVK]; !ok {
+			log.DPanicf("unrecognized pruned resource type %s, must be added to managed types", prune.GVK)
+		}
+	}
+	var opts []fsmio.ApplyOutputSetOption
+	if version := r.reconcilerOptions.VersionAnnotation; version != "" {
+		opts = append(opts, fsmio.WithVersionAnnotation(version))
+		if obj.GetAnnotations()[io.ReconciledByVersionAnnotationKey] != version {
+			outputSet.PatchSelf(func(o client.Object) {
+				annotations := o.GetAnnotations()
+				if annotations == nil {
+					annotations = map[string]string{}
+				}
+				annotations[io.ReconciledByVersionAnnotationKey] = version
+				o.SetAnnotations(annotations)
+			})
+		}
+	}
+	var retainedRefsRequeue time.Duration
+	if r.missingRefTracker != nil {
+		opts = append(opts,
+			fsmio.WithMissingRefTracker(r.missingRefTracker),
+			fsmio.WithRetainedRefsCallback(func(remaining time.Duration) { retainedRefsRequeue = remaining }),
+		)
+	}
+	if r.reconcilerOptions.WithoutDefaultOwnerRefs {
+		opts = append(opts, fsmio.WithoutDefaultControllerRef())
+	}
+	if r.reconcilerOptions.ShadowApplicator != nil {
+		opts = append(opts, fsmio.WithShadowApplicator(r.reconcilerOptions.ShadowApplicator))
+	}
+	if err := fsmio.ApplyOutputSet(ctx, log, r.client, r.scheme, obj, outputSet, opts...); err != nil {
+		return 0, err
+	}
+	return retainedRefsRequeue, nil
+}
+
+// requeueRefs enqueues requests for the referenced objects, excluding the object being reconciled.
+func (r *fsmReconciler[T, Obj]) requeueRefs(
+	log *zap.SugaredLogger,
+	req ctrl.Request,
+	refs []api.TypedObjectRef,
+) {
+	if len(refs) == 0 {
+		return
+	}
+
+	gvk := meta.MustGVKForObject(Obj(new(T)), r.scheme)
+
+	var reqs []reconcile.Request
+	for _, ref := range refs {
+		if ref.GroupVersionKind() != gvk {
+			log.Warnf("cannot requeue %s, only objects of type %s can be requeued", ref, gvk)
+			continue
+		}
+		// requeueing the object being reconciled would loop
+		if ref.ObjectKey() == req.NamespacedName {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: ref.ObjectKey()})
+	}
+
+	if len(reqs) > 0 && !r.requeueSource.enqueue(reqs...) {
+		log.Warnf("cannot requeue %v, requeue source not watched by controller", reqs)
 	}
-	return fsmio.ApplyOutputSet(ctx, r.log, r.client, r.scheme, obj, outputSet)
 }
 
 func DeletedStateFor[T any, Obj apitypes.FSMResource[T]](_ *fsmReconciler[T, Obj]) *types.State[Obj] {
@@ -13,11 +13,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 
 	"github.com/reddit/achilles-sdk-api/api"
 	apitypes "github.com/reddit/achilles-sdk-api/pkg/types"
 	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/logging"
 	"github.com/reddit/achilles-sdk/pkg/meta"
 	"github.com/reddit/achilles-sdk/pkg/status"
 )
@@ -45,8 +45,7 @@ type BeforeDelete[
 ] func(Claim, Claimed) error
 
 func (r *ClaimReconciler[T, Claimed, U, Claim]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	requestId := ctrlcontroller.ReconcileIDFromContext(ctx)
-	log := r.Log.With("request", req, "requestId", requestId)
+	log := logging.ReconcileLogger(ctx, r.Log.With("request", req), nil)
 	log.Debug("entering reconcile")
 	startedAt := time.Now()
 	defer func() { log.Debugf("finished reconcile in %s", time.Since(startedAt)) }()
@@ -0,0 +1,1935 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
+	fsmtypes "github.com/reddit/achilles-sdk/pkg/fsm/types"
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/logging"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+	"github.com/reddit/achilles-sdk/pkg/status"
+)
+
+const testNamespace = "default"
+
+var (
+	configMapGVK = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	secretGVK    = corev1.SchemeGroupVersion.WithKind("Secret")
+)
+
+type testFSMState = fsmtypes.State[*v1alpha1.TestClaim]
+
+func TestReconciler_RedditLabelKeys(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: testNamespace}}
+
+	initialState := &testFSMState{
+		Name: "apply-outputs",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			out.Apply(cm.DeepCopy())
+			out.Apply(secret.DeepCopy())
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	cases := []struct {
+		name                 string
+		redditLabelKeys      map[schema.GroupVersionKind][]string
+		expectedCMLabels     map[string]string
+		expectedSecretLabels map[string]string
+	}{
+		{
+			name:                 "default labels",
+			expectedCMLabels:     meta.RedditLabels(testControllerName),
+			expectedSecretLabels: meta.RedditLabels(testControllerName),
+		},
+		{
+			name: "excluded type",
+			redditLabelKeys: map[schema.GroupVersionKind][]string{
+				configMapGVK: nil,
+			},
+			expectedCMLabels:     nil,
+			expectedSecretLabels: meta.RedditLabels(testControllerName),
+		},
+		{
+			name: "subset of keys",
+			redditLabelKeys: map[schema.GroupVersionKind][]string{
+				configMapGVK: {meta.ManagedByKey},
+			},
+			expectedCMLabels:     map[string]string{meta.ManagedByKey: testControllerName},
+			expectedSecretLabels: meta.RedditLabels(testControllerName),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := newTestFSMClaim()
+			r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+				RedditLabelKeys: tc.redditLabelKeys,
+			}, claim)
+
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+				t.Fatalf("running reconciler: %s", err)
+			}
+
+			actualCM := &corev1.ConfigMap{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actualCM); err != nil {
+				t.Fatalf("getting configmap: %s", err)
+			}
+			actualSecret := &corev1.Secret{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(secret), actualSecret); err != nil {
+				t.Fatalf("getting secret: %s", err)
+			}
+
+			assertLabels(t, "configmap", actualCM.GetLabels(), tc.expectedCMLabels)
+			assertLabels(t, "secret", actualSecret.GetLabels(), tc.expectedSecretLabels)
+
+			// owner refs are applied regardless of labels
+			if len(actualCM.GetOwnerReferences()) != 1 {
+				t.Errorf("expected configmap to have a controller reference, got %v", actualCM.GetOwnerReferences())
+			}
+			if len(actualSecret.GetOwnerReferences()) != 1 {
+				t.Errorf("expected secret to have a controller reference, got %v", actualSecret.GetOwnerReferences())
+			}
+		})
+	}
+}
+
+func TestReconciler_RequeueRefs(t *testing.T) {
+	claim := newTestFSMClaim()
+	sibling := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "sibling", Namespace: testNamespace}}
+
+	initialState := &testFSMState{
+		Name: "requeue-refs",
+		Transition: func(_ context.Context, obj *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			out.RequeueRef(*meta.MustTypedObjectRefFromObject(sibling, scheme))
+			// the object being reconciled is not requeued
+			out.RequeueRef(*meta.MustTypedObjectRefFromObject(obj, scheme))
+			// objects not of the reconciled type are not requeued
+			out.RequeueRef(*meta.MustTypedObjectRefFromObject(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}}, scheme))
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim, sibling)
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	ctx := context.Background()
+	if err := r.RequeueSource().Start(ctx, q); err != nil {
+		t.Fatalf("starting requeue source: %s", err)
+	}
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	if q.Len() != 1 {
+		t.Fatalf("expected 1 enqueued request, got %d", q.Len())
+	}
+	actual, _ := q.Get()
+	expected := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(sibling)}
+	if actual != expected {
+		t.Errorf("unexpected request %s, want %s", actual, expected)
+	}
+	q.Done(actual)
+}
+
+func TestReconciler_ReconcileResultMetric(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   fsmtypes.Result
+		notFound bool
+		expected string
+	}{
+		{
+			name:     "done",
+			result:   fsmtypes.DoneResult(),
+			expected: metrics.ReconcileResultDone,
+		},
+		{
+			name:     "requeue",
+			result:   fsmtypes.RequeueResult("requeue", time.Second),
+			expected: metrics.ReconcileResultRequeue,
+		},
+		{
+			name:     "requeue with backoff",
+			result:   fsmtypes.RequeueResultWithBackoff("requeue"),
+			expected: metrics.ReconcileResultRequeue,
+		},
+		{
+			name:     "error",
+			result:   fsmtypes.ErrorResult(errors.New("error")),
+			expected: metrics.ReconcileResultError,
+		},
+		{
+			name:     "terminal error",
+			result:   fsmtypes.ErrorResult(reconcile.TerminalError(errors.New("error"))),
+			expected: metrics.ReconcileResultTerminal,
+		},
+		{
+			name:     "not found",
+			notFound: true,
+			expected: metrics.ReconcileResultDone,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialState := &testFSMState{
+				Name: "result",
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					return nil, tc.result
+				},
+			}
+
+			var objs []client.Object
+			if !tc.notFound {
+				objs = append(objs, newTestFSMClaim())
+			}
+			r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, objs...)
+
+			reg := prometheus.NewRegistry()
+			r.metrics = metrics.MustMakeMetrics(scheme, reg)
+			r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+			_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(newTestFSMClaim())})
+
+			expected := fmt.Sprintf(`# HELP achilles_reconcile_result_total Total number of reconciles per controller and result type (done, requeue, error, or terminal).
+# TYPE achilles_reconcile_result_total counter
+achilles_reconcile_result_total{controller=%q,result=%q} 1
+`, testControllerName, tc.expected)
+			if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_reconcile_result_total"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReconciler_SuspectedReconcileLoop(t *testing.T) {
+	const threshold = 3
+
+	cases := []struct {
+		name     string
+		result   func(i int) fsmtypes.Result
+		expected float64
+	}{
+		{
+			name: "no-op status loop",
+			result: func(int) fsmtypes.Result {
+				return fsmtypes.DoneResult()
+			},
+			expected: 1,
+		},
+		{
+			name: "status changes",
+			result: func(i int) fsmtypes.Result {
+				return fsmtypes.DoneResultWithStatusCondition(fsmtypes.ResultStatusCondition{
+					Status:  corev1.ConditionTrue,
+					Reason:  "Changed",
+					Message: fmt.Sprintf("reconcile %d", i),
+				})
+			},
+			expected: 0,
+		},
+		{
+			name: "fast requeue",
+			result: func(int) fsmtypes.Result {
+				return fsmtypes.RequeueResult("waiting", time.Millisecond)
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var i int
+			initialState := &testFSMState{
+				Name:      "state",
+				Condition: api.Condition{Type: "State"},
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					i++
+					return nil, tc.result(i)
+				},
+			}
+
+			r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+				ReconcileLoopThreshold: threshold,
+				ReconcileLoopWindow:    time.Minute,
+			}, newTestFSMClaim())
+
+			reg := prometheus.NewRegistry()
+			r.metrics = metrics.MustMakeMetrics(scheme, reg)
+			r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+			// simulate the object being repeatedly enqueued
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(newTestFSMClaim())}
+			for range threshold + 1 {
+				_, _ = r.Reconcile(context.Background(), req)
+			}
+
+			expected := fmt.Sprintf(`# HELP achilles_suspected_reconcile_loop Gauge reporting whether the object is suspected to be in a reconcile loop, i.e. repeatedly reconciled without status changes
+# TYPE achilles_suspected_reconcile_loop gauge
+achilles_suspected_reconcile_loop{group="test.infrared.reddit.com",kind="TestClaim",name=%q,namespace=%q,version="v1alpha1"} %v
+`, testClaimName, testNamespace, tc.expected)
+			if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_suspected_reconcile_loop"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReconciler_StatusSizeThreshold(t *testing.T) {
+	cases := []struct {
+		name             string
+		threshold        int
+		expectedWarnings int
+		expected         float64
+	}{
+		{
+			name:             "exceeded",
+			threshold:        2,
+			expectedWarnings: 1,
+			expected:         1,
+		},
+		{
+			name:      "higher threshold",
+			threshold: 10,
+			expected:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// three state conditions and the ready condition
+			stateC := &testFSMState{Name: "c", Condition: api.Condition{Type: "C"}}
+			stateB := &testFSMState{
+				Name:      "b",
+				Condition: api.Condition{Type: "B"},
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					return stateC, fsmtypes.DoneResult()
+				},
+			}
+			stateA := &testFSMState{
+				Name:      "a",
+				Condition: api.Condition{Type: "A"},
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					return stateB, fsmtypes.DoneResult()
+				},
+			}
+
+			claim := newTestFSMClaim()
+			r, _ := newTestFSMReconciler(t, stateA, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+				StatusConditionsThreshold: tc.threshold,
+			}, claim)
+
+			core, logs := observer.New(zapcore.WarnLevel)
+			r.log = zap.New(core).Sugar()
+			reg := prometheus.NewRegistry()
+			r.metrics = metrics.MustMakeMetrics(scheme, reg)
+			r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+			for range 2 {
+				if _, err := r.Reconcile(context.Background(), req); err != nil {
+					t.Fatalf("running reconciler: %s", err)
+				}
+			}
+
+			// warns once rather than on every reconcile
+			if warnings := logs.FilterMessageSnippet(`status field "conditions" has 4 entries`).Len(); warnings != tc.expectedWarnings {
+				t.Errorf("expected %d warnings, got %d", tc.expectedWarnings, warnings)
+			}
+
+			expected := fmt.Sprintf(`# HELP achilles_status_size_exceeded Gauge reporting whether a status field (conditions or resourceRefs) of an Achilles resource exceeds its size threshold, suggesting unbounded growth.
+# TYPE achilles_status_size_exceeded gauge
+achilles_status_size_exceeded{field="conditions",group="test.infrared.reddit.com",kind="TestClaim",name=%[1]q,namespace=%[2]q,version="v1alpha1"} %[3]v
+achilles_status_size_exceeded{field="resourceRefs",group="test.infrared.reddit.com",kind="TestClaim",name=%[1]q,namespace=%[2]q,version="v1alpha1"} 0
+`, testClaimName, testNamespace, tc.expected)
+			if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_status_size_exceeded"); err != nil {
+				t.Error(err)
+			}
+
+			// metrics are deleted once the object is forgotten
+			r.forget(req, claim)
+			if count, err := testutil.GatherAndCount(reg, "achilles_status_size_exceeded"); err != nil || count != 0 {
+				t.Errorf("expected status size metrics to be deleted, got %d (err: %v)", count, err)
+			}
+		})
+	}
+}
+
+func TestReconciler_CustomMetrics(t *testing.T) {
+	var recordErr error
+	initialState := &testFSMState{
+		Name: "record",
+		Transition: func(ctx context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			recordErr = metrics.CustomMetricsFromContext(ctx).SetGauge("capacity", "Provisioned capacity.", 42, prometheus.Labels{"tier": "gold"})
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	t.Run("no registerer", func(t *testing.T) {
+		recordErr = nil
+		r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, newTestFSMClaim())
+
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(newTestFSMClaim())})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if recordErr != nil {
+			t.Errorf("unexpected error recording custom metric: %v", recordErr)
+		}
+	})
+
+	t.Run("registerer", func(t *testing.T) {
+		recordErr = nil
+		reg := prometheus.NewRegistry()
+		r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+			MetricsOptions: fsmtypes.MetricsOptions{CustomMetricsRegisterer: reg},
+		}, newTestFSMClaim())
+
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(newTestFSMClaim())})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if recordErr != nil {
+			t.Errorf("unexpected error recording custom metric: %v", recordErr)
+		}
+
+		expected := fmt.Sprintf(`# HELP capacity Provisioned capacity.
+# TYPE capacity gauge
+capacity{group=%q,kind=%q,tier="gold",version=%q} 42
+`, v1alpha1.TestClaimGroupVersionKind.Group, v1alpha1.TestClaimGroupVersionKind.Kind, v1alpha1.TestClaimGroupVersionKind.Version)
+		if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "capacity"); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestReconciler_MaxStatesPerReconcile(t *testing.T) {
+	var executed []string
+	var requeueA bool
+
+	var stateA, stateB, stateC *testFSMState
+	newState := func(name string, next func() *testFSMState) *testFSMState {
+		return &testFSMState{
+			Name:      name,
+			Condition: api.Condition{Type: api.ConditionType(name)},
+			Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+				executed = append(executed, name)
+				if name == "a" && requeueA {
+					return next(), fsmtypes.DoneAndRequeueAfterCompletion("requeueing a", time.Minute)
+				}
+				return next(), fsmtypes.DoneResult()
+			},
+		}
+	}
+	stateC = newState("c", func() *testFSMState { return nil })
+	stateB = newState("b", func() *testFSMState { return stateC })
+	stateA = newState("a", func() *testFSMState { return stateB })
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, stateA, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		MaxStatesPerReconcile: 2,
+	}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	reconcileAndAssert := func(expectedExecuted []string, expectRequeue bool) {
+		t.Helper()
+		executed = nil
+		res, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+		if diff := cmp.Diff(executed, expectedExecuted); diff != "" {
+			t.Errorf("unexpected executed states: (-got +want)\n%s", diff)
+		}
+		if requeued := res.RequeueAfter > 0; requeued != expectRequeue {
+			t.Errorf("unexpected requeue %t, want %t", requeued, expectRequeue)
+		}
+	}
+
+	getClaim := func() *v1alpha1.TestClaim {
+		t.Helper()
+		actual := &v1alpha1.TestClaim{}
+		if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+			t.Fatalf("getting claim: %s", err)
+		}
+		return actual
+	}
+	getReadyCondition := func() api.Condition {
+		t.Helper()
+		return getClaim().GetCondition(api.TypeReady)
+	}
+
+	// pauses after 2 states
+	reconcileAndAssert([]string{"a", "b"}, true)
+
+	// resumes from the following state
+	reconcileAndAssert([]string{"c"}, false)
+
+	// conditions of states executed before pausing are retained once completed
+	actual := getClaim()
+	for _, conditionType := range []api.ConditionType{"a", "b", "c", api.TypeReady} {
+		if condition := actual.GetCondition(conditionType); condition.Status != corev1.ConditionTrue {
+			t.Errorf("expected condition %q to be true once completed, got %v", conditionType, condition)
+		}
+	}
+
+	ready := getReadyCondition()
+
+	// starts from the initial state once completed, leaving the ready condition unchanged while paused
+	reconcileAndAssert([]string{"a", "b"}, true)
+	if diff := cmp.Diff(getReadyCondition(), ready); diff != "" {
+		t.Errorf("unexpected change to ready condition while paused: (-got +want)\n%s", diff)
+	}
+
+	reconcileAndAssert([]string{"c"}, false)
+	if diff := cmp.Diff(getReadyCondition(), ready); diff != "" {
+		t.Errorf("unexpected change to ready condition once completed: (-got +want)\n%s", diff)
+	}
+
+	// starts from the initial state if the object's generation changed since pausing
+	reconcileAndAssert([]string{"a", "b"}, true)
+	actual = getClaim()
+	actual.SetGeneration(actual.GetGeneration() + 1)
+	if err := c.Update(ctx, actual); err != nil {
+		t.Fatalf("updating claim: %s", err)
+	}
+	reconcileAndAssert([]string{"a", "b"}, true)
+	reconcileAndAssert([]string{"c"}, false)
+
+	// requeues after completion signaled before pausing are retained once completed
+	requeueA = true
+	reconcileAndAssert([]string{"a", "b"}, true)
+	reconcileAndAssert([]string{"c"}, true)
+	if condition := getClaim().GetCondition("a"); condition.Status != corev1.ConditionFalse {
+		t.Errorf("expected condition %q to be false for requeue after completion, got %v", "a", condition)
+	}
+	if ready := getReadyCondition(); ready.Status != corev1.ConditionFalse {
+		t.Errorf("expected ready condition to be false for requeue after completion, got %v", ready)
+	}
+}
+
+func TestReconciler_RequeueBeforeSoonest(t *testing.T) {
+	var executed []string
+
+	renewedState := &testFSMState{
+		Name:      "renewed",
+		Condition: api.Condition{Type: "Renewed"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			executed = append(executed, "renewed")
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+	initialState := &testFSMState{
+		Name:      "renew",
+		Condition: api.Condition{Type: "Renew"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			executed = append(executed, "renew")
+			return renewedState, fsmtypes.RequeueBeforeSoonest([]time.Time{time.Now().Add(24 * time.Hour)}, time.Hour)
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	// states following the renewal are executed, and the FSM requeues before the expiry once completed
+	if diff := cmp.Diff(executed, []string{"renew", "renewed"}); diff != "" {
+		t.Errorf("unexpected executed states: (-got +want)\n%s", diff)
+	}
+	if res.RequeueAfter <= 22*time.Hour || res.RequeueAfter > 23*time.Hour {
+		t.Errorf("expected requeue an hour before expiry, got %s", res.RequeueAfter)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	for _, conditionType := range []api.ConditionType{"Renew", "Renewed", api.TypeReady} {
+		if condition := actual.GetCondition(conditionType); condition.Status != corev1.ConditionTrue {
+			t.Errorf("expected condition %q to be true, got %v", conditionType, condition)
+		}
+	}
+	if reason := actual.GetCondition("Renew").Reason; reason != "RenewingBeforeExpiry" {
+		t.Errorf("expected reason RenewingBeforeExpiry, got %q", reason)
+	}
+}
+
+func TestReconciler_BranchResult(t *testing.T) {
+	var executed []string
+
+	newState := func(name string) *testFSMState {
+		return &testFSMState{
+			Name:      name,
+			Condition: api.Condition{Type: api.ConditionType(name)},
+			Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+				executed = append(executed, name)
+				return nil, fsmtypes.DoneResult()
+			},
+		}
+	}
+	createState := newState("create")
+	updateState := newState("update")
+	initialState := &testFSMState{
+		Name:      "branch",
+		Condition: api.Condition{Type: "branch"},
+		Transition: func(_ context.Context, claim *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			executed = append(executed, "branch")
+			branch := "create"
+			if claim.Spec.TestField == "existing" {
+				branch = "update"
+			}
+			return fsmtypes.BranchResult(map[string]*testFSMState{
+				"create": createState,
+				"update": updateState,
+			}, branch)
+		},
+	}
+
+	claim := newTestFSMClaim()
+	claim.Spec.TestField = "existing"
+	r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if diff := cmp.Diff(executed, []string{"branch", "update"}); diff != "" {
+		t.Errorf("unexpected executed states: (-got +want)\n%s", diff)
+	}
+}
+
+func TestReconciler_TransientErrorGracePeriod(t *testing.T) {
+	transientErr := fmt.Errorf("getting config map: %w", &net.DNSError{Err: "no such host", Name: "kube-apiserver"})
+
+	cases := []struct {
+		name           string
+		err            error
+		elapsed        time.Duration
+		expectedStatus corev1.ConditionStatus
+	}{
+		{
+			name:           "transient error within grace period",
+			err:            transientErr,
+			elapsed:        time.Second,
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:           "transient error beyond grace period",
+			err:            transientErr,
+			elapsed:        2 * time.Minute,
+			expectedStatus: corev1.ConditionFalse,
+		},
+		{
+			name:           "non-transient error",
+			err:            errors.New("invalid spec"),
+			expectedStatus: corev1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stateErr error
+			initialState := &testFSMState{
+				Name:      "state",
+				Condition: api.Condition{Type: "State"},
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					if stateErr != nil {
+						return nil, fsmtypes.ErrorResult(stateErr)
+					}
+					return nil, fsmtypes.DoneResult()
+				},
+			}
+
+			claim := newTestFSMClaim()
+			r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+				TransientErrorGracePeriod: time.Minute,
+			}, claim)
+
+			now := time.Now()
+			r.transientErrors.now = func() time.Time { return now }
+
+			ctx := context.Background()
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+			// reconcile successfully
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("running reconciler: %s", err)
+			}
+
+			// fail repeatedly over the elapsed duration
+			stateErr = tc.err
+			for _, elapsed := range []time.Duration{0, tc.elapsed} {
+				now = now.Add(elapsed)
+				if _, err := r.Reconcile(ctx, req); !errors.Is(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+			}
+
+			actual := &v1alpha1.TestClaim{}
+			if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+				t.Fatalf("getting claim: %s", err)
+			}
+			for _, conditionType := range []api.ConditionType{"State", api.TypeReady} {
+				if cond := actual.GetCondition(conditionType); cond.Status != tc.expectedStatus {
+					t.Errorf("expected condition %s to have status %s, got %v", conditionType, tc.expectedStatus, cond)
+				}
+			}
+		})
+	}
+}
+
+func TestReconciler_TransientErrorGracePeriodManagedResources(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}}
+
+	var stateErr error
+	failingState := &testFSMState{
+		Name:      "failing",
+		Condition: api.Condition{Type: "Failing"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			if stateErr != nil {
+				return nil, fsmtypes.ErrorResult(stateErr)
+			}
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+	initialState := &testFSMState{
+		Name:      "apply-outputs",
+		Condition: api.Condition{Type: "ApplyOutputs"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			if stateErr != nil {
+				out.Apply(cm.DeepCopy())
+			}
+			return failingState, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		TransientErrorGracePeriod: time.Minute,
+	}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	// managed resources applied before a tolerated transient error are recorded on the object's status
+	stateErr = fmt.Errorf("getting config map: %w", &net.DNSError{Err: "no such host", Name: "kube-apiserver"})
+	if _, err := r.Reconcile(ctx, req); !errors.Is(err, stateErr) {
+		t.Fatalf("expected error %q, got %v", stateErr, err)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	expectedRefs := []api.TypedObjectRef{*meta.MustTypedObjectRefFromObject(cm, scheme)}
+	if diff := cmp.Diff(actual.GetManagedResources(), expectedRefs); diff != "" {
+		t.Errorf("unexpected managed resources: (-got +want)\n%s", diff)
+	}
+	for _, conditionType := range []api.ConditionType{"Failing", api.TypeReady} {
+		if cond := actual.GetCondition(conditionType); cond.Status != corev1.ConditionTrue {
+			t.Errorf("expected condition %s to be unchanged, got %v", conditionType, cond)
+		}
+	}
+}
+
+func TestReconciler_ManagedResourceRefGracePeriod(t *testing.T) {
+	missing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: testNamespace}}
+	missingRef := *meta.MustTypedObjectRefFromObject(missing, scheme)
+
+	initialState := &testFSMState{
+		Name:      "state",
+		Condition: api.Condition{Type: "State"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	claim.Status.ResourceRefs = []api.TypedObjectRef{missingRef}
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		ManagedResourceRefGracePeriod: time.Minute,
+	}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	// requeues once the grace period of the retained ref elapses, so that it's pruned without waiting for another event
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if res.RequeueAfter <= 0 || res.RequeueAfter > time.Minute {
+		t.Errorf("expected requeue within grace period, got %s", res.RequeueAfter)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if diff := cmp.Diff(actual.GetManagedResources(), []api.TypedObjectRef{missingRef}); diff != "" {
+		t.Errorf("unexpected managed resources: (-got +want)\n%s", diff)
+	}
+	if ready := actual.GetCondition(api.TypeReady); ready.Status != corev1.ConditionTrue {
+		t.Errorf("expected ready condition to be true while retaining refs, got %v", ready)
+	}
+}
+
+func TestReconciler_ReconcileFilter(t *testing.T) {
+	var executed int
+	initialState := &testFSMState{
+		Name:      "state",
+		Condition: api.Condition{Type: "State"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			executed++
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	claim.Labels = map[string]string{"tenant": "a"}
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		ReconcileFilter: &fsmtypes.ReconcileFilter{
+			Namespaces: []string{testNamespace},
+			Selector:   labels.SelectorFromSet(labels.Set{"tenant": "a"}),
+		},
+	}, claim)
+
+	reg := prometheus.NewRegistry()
+	r.metrics = metrics.MustMakeMetrics(scheme, reg)
+	r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	reconcileAndAssert := func(expectedExecuted int, expectReadiness bool) {
+		t.Helper()
+		executed = 0
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+		if executed != expectedExecuted {
+			t.Errorf("expected %d state executions, got %d", expectedExecuted, executed)
+		}
+		count, err := testutil.GatherAndCount(reg, "achilles_resource_readiness")
+		if err != nil {
+			t.Fatalf("gathering metrics: %s", err)
+		}
+		if reported := count > 0; reported != expectReadiness {
+			t.Errorf("expected readiness reported %t, got %d series", expectReadiness, count)
+		}
+	}
+
+	setLabels := func(l map[string]string) {
+		t.Helper()
+		actual := &v1alpha1.TestClaim{}
+		if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+			t.Fatalf("getting claim: %s", err)
+		}
+		actual.Labels = l
+		if err := c.Update(ctx, actual); err != nil {
+			t.Fatalf("updating claim: %s", err)
+		}
+	}
+
+	// matching object is reconciled
+	reconcileAndAssert(1, true)
+
+	// object that stops matching is skipped, and its metrics deleted
+	setLabels(map[string]string{"tenant": "b"})
+	reconcileAndAssert(0, false)
+
+	// object that starts matching is reconciled
+	setLabels(map[string]string{"tenant": "a"})
+	reconcileAndAssert(1, true)
+}
+
+func TestReconciler_LogCorrelationIDs(t *testing.T) {
+	initialState := &testFSMState{
+		Name: "state",
+		Transition: func(ctx context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			log, err := logging.FromContext(ctx)
+			if err != nil {
+				return nil, fsmtypes.ErrorResult(err)
+			}
+			log.Info("logged from transition")
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		TraceIDsFunc: func(context.Context) (string, string) {
+			return "trace-id", "span-id"
+		},
+	}, claim)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	r.log = zap.New(core).Sugar()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	for range 2 {
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+	}
+
+	if logs.FilterMessage("logged from transition").Len() != 2 {
+		t.Fatalf("expected transition logs, got %v", logs.All())
+	}
+
+	requestIDs := map[string]struct{}{}
+	for _, entry := range logs.All() {
+		fields := entry.ContextMap()
+		requestID, ok := fields["requestId"]
+		if !ok {
+			t.Errorf("expected requestId field on log %q", entry.Message)
+			continue
+		}
+		requestIDs[fmt.Sprint(requestID)] = struct{}{}
+		if fields["traceId"] != "trace-id" || fields["spanId"] != "span-id" {
+			t.Errorf("expected trace and span IDs on log %q, got %v", entry.Message, fields)
+		}
+	}
+	if len(requestIDs) != 2 {
+		t.Errorf("expected a distinct requestId per reconcile, got %v", requestIDs)
+	}
+}
+
+func TestReconciler_StateResultLogs(t *testing.T) {
+	var fail bool
+	waitState := &testFSMState{
+		Name: "wait",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			if fail {
+				return nil, fsmtypes.ErrorResultWithReason(errors.New("boom"), "Broken")
+			}
+			return nil, fsmtypes.RequeueResultWithReason("waiting", "Waiting", time.Second)
+		},
+	}
+	initialState := &testFSMState{
+		Name: "ready",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return waitState, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	r.log = zap.New(core).Sugar()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	fail = true
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Fatalf("expected reconcile error")
+	}
+
+	entries := logs.FilterMessage("exiting state").All()
+	expected := []struct {
+		state  string
+		result string
+		reason string
+	}{
+		{state: "ready", result: "done"},
+		{state: "wait", result: "requeue", reason: "Waiting"},
+		{state: "ready", result: "done"},
+		{state: "wait", result: "error", reason: "Broken"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d state logs, got %v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		fields := entry.ContextMap()
+		if entry.Level != zapcore.DebugLevel {
+			t.Errorf("expected debug level, got %s", entry.Level)
+		}
+		if fields["state"] != expected[i].state || fields["result"] != expected[i].result || fmt.Sprint(fields["reason"]) != expected[i].reason {
+			t.Errorf("unexpected fields for state %q: %v", expected[i].state, fields)
+		}
+		if _, ok := fields["duration_ms"].(int64); !ok {
+			t.Errorf("expected duration_ms field for state %q, got %v", expected[i].state, fields)
+		}
+		if _, ok := fields["error"]; ok != (expected[i].result == "error") {
+			t.Errorf("unexpected error field for state %q: %v", expected[i].state, fields)
+		}
+	}
+	if fields := entries[3].ContextMap(); fields["error"] != "boom" {
+		t.Errorf("expected logged error %q, got %v", "boom", fields["error"])
+	}
+}
+
+// helpers
+
+const testControllerName = "test-claim"
+
+func newTestFSMClaim() *v1alpha1.TestClaim {
+	return &v1alpha1.TestClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testClaimName,
+			Namespace:  testNamespace,
+			Generation: 1,
+		},
+	}
+}
+
+func newTestFSMReconciler(
+	t *testing.T,
+	initialState *testFSMState,
+	opts fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim],
+	objs ...client.Object,
+) (*fsmReconciler[v1alpha1.TestClaim, *v1alpha1.TestClaim], *io.ClientApplicator) {
+	t.Helper()
+
+	log := zaptest.NewLogger(t).Sugar()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		Build()
+	c := testApplicator(fakeClient)
+
+	m := metrics.MustMakeMetrics(scheme, prometheus.NewRegistry())
+	m.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	r := NewFSMReconciler(
+		testControllerName,
+		log,
+		c,
+		scheme,
+		initialState,
+		nil,
+		[]schema.GroupVersionKind{configMapGVK, secretGVK},
+		m,
+		opts,
+	)
+
+	return r, c
+}
+
+func assertLabels(t *testing.T, name string, actual, expected map[string]string) {
+	t.Helper()
+
+	if diff := cmp.Diff(actual, expected, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("unexpected %s labels: (-got +want)\n%s", name, diff)
+	}
+}
+
+func TestReconciler_PropagateChildReadiness(t *testing.T) {
+	child := &v1alpha1.TestClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: testNamespace},
+		Status: v1alpha1.TestClaimStatus{
+			ConditionedStatus: api.ConditionedStatus{
+				Conditions: []api.Condition{
+					{
+						Type:    api.TypeReady,
+						Status:  corev1.ConditionFalse,
+						Reason:  "QuotaExceeded",
+						Message: "quota exceeded",
+					},
+				},
+			},
+		},
+	}
+	claim := newTestFSMClaim()
+	claim.SetManagedResources([]api.TypedObjectRef{*meta.MustTypedObjectRefFromObject(child, scheme)})
+
+	stateErr := errors.New("child not ready")
+	initialState := &testFSMState{
+		Name:      "state",
+		Condition: api.Condition{Type: "State"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.ErrorResult(stateErr)
+		},
+	}
+
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		PropagateChildReadiness: true,
+	}, claim, child)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	if _, err := r.Reconcile(ctx, req); !errors.Is(err, stateErr) {
+		t.Fatalf("expected error %q, got %v", stateErr, err)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	expected := "Non-successful conditions: State. Caused by child TestClaim default/child (QuotaExceeded): quota exceeded"
+	if msg := actual.GetCondition(api.TypeReady).Message; msg != expected {
+		t.Errorf("unexpected Ready condition message %q, want %q", msg, expected)
+	}
+}
+
+func TestReconciler_CustomStatusConditionObservedGeneration(t *testing.T) {
+	explicitState := &testFSMState{
+		Name:      "explicit",
+		Condition: api.Condition{Type: "Explicit"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.DoneResultWithStatusCondition(fsmtypes.ResultStatusCondition{
+				Status:             corev1.ConditionFalse,
+				Reason:             "Stale",
+				ObservedGeneration: 1,
+			})
+		},
+	}
+	initialState := &testFSMState{
+		Name:      "custom",
+		Condition: api.Condition{Type: "Custom"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return explicitState, fsmtypes.DoneResultWithStatusCondition(fsmtypes.ResultStatusCondition{
+				Status: corev1.ConditionFalse,
+				Reason: "Degraded",
+			})
+		},
+	}
+
+	claim := newTestFSMClaim()
+	claim.Generation = 3
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if cond := actual.GetCondition("Custom"); cond.Reason != "Degraded" || cond.ObservedGeneration != 3 {
+		t.Errorf("expected custom condition to carry the claim's generation, got %v", cond)
+	}
+	if cond := actual.GetCondition("Explicit"); cond.Reason != "Stale" || cond.ObservedGeneration != 1 {
+		t.Errorf("expected explicitly set observed generation to be respected, got %v", cond)
+	}
+}
+
+func TestReconciler_SkipResult(t *testing.T) {
+	var nextEntered bool
+	skippedState := &testFSMState{
+		Name:      "skipped",
+		Condition: api.Condition{Type: "Skipped"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			nextEntered = true
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+	initialState := &testFSMState{
+		Name:      "owner",
+		Condition: api.Condition{Type: "Owner"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return skippedState, fsmtypes.SkipResult("HandledElsewhere")
+		},
+	}
+
+	claim := newTestFSMClaim()
+	existing := api.Condition{
+		Type:    "Owner",
+		Status:  corev1.ConditionFalse,
+		Reason:  "Previous",
+		Message: "set by a previous reconcile",
+	}
+	claim.SetConditions(existing)
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	reg := prometheus.NewRegistry()
+	r.metrics = metrics.MustMakeMetrics(scheme, reg)
+	r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	before := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, before); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if !res.IsZero() {
+		t.Errorf("expected no requeue, got %v", res)
+	}
+	if nextEntered {
+		t.Error("expected FSM to terminate at the skipping state")
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if actual.ResourceVersion != before.ResourceVersion {
+		t.Errorf("expected claim to be untouched, resourceVersion changed from %s to %s", before.ResourceVersion, actual.ResourceVersion)
+	}
+	if len(actual.Status.Conditions) != 1 || actual.GetCondition("Owner") != existing {
+		t.Errorf("expected existing conditions to be retained, got %v", actual.Status.Conditions)
+	}
+
+	expected := fmt.Sprintf(`# HELP achilles_reconcile_skipped_total Total number of reconciles per controller skipped by a state transition, e.g. for objects handled by another controller.
+# TYPE achilles_reconcile_skipped_total counter
+achilles_reconcile_skipped_total{controller=%q,reason="HandledElsewhere"} 1
+`, testControllerName)
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_reconcile_skipped_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReconciler_ReadyConditionType(t *testing.T) {
+	const availableType = api.ConditionType("Available")
+
+	initialState := &testFSMState{
+		Name:      "state",
+		Condition: api.Condition{Type: "State"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		ReadyConditionType: availableType,
+	}, claim)
+
+	reg := prometheus.NewRegistry()
+	r.metrics = metrics.MustMakeMetrics(scheme, reg)
+	r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if cond := actual.GetCondition(availableType); cond.Status != corev1.ConditionTrue || cond.Reason != status.ReasonSuccess {
+		t.Errorf("expected successful %s condition, got %v", availableType, cond)
+	}
+	for _, cond := range actual.GetConditions() {
+		if cond.Type == api.TypeReady {
+			t.Errorf("unexpected %s condition %v", api.TypeReady, cond)
+		}
+	}
+	if !status.ResourceReadyForType(actual, availableType) {
+		t.Errorf("expected claim to be ready for condition type %s", availableType)
+	}
+
+	expected := `
+# HELP achilles_resource_readiness The status condition of type "Ready" for an Achilles resource.
+# TYPE achilles_resource_readiness gauge
+achilles_resource_readiness{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",status="Deleted",type="Available",version="v1alpha1"} 0
+achilles_resource_readiness{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",status="False",type="Available",version="v1alpha1"} 0
+achilles_resource_readiness{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",status="True",type="Available",version="v1alpha1"} 1
+achilles_resource_readiness{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",status="Unknown",type="Available",version="v1alpha1"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_resource_readiness"); err != nil {
+		t.Error(err)
+	}
+
+	// readiness metric of the custom condition type is deleted once the object is forgotten
+	r.forget(req, actual)
+	if count, err := testutil.GatherAndCount(reg, "achilles_resource_readiness"); err != nil || count != 0 {
+		t.Errorf("expected readiness metrics to be deleted, got %d (err: %v)", count, err)
+	}
+}
+
+func TestReconciler_PatchSelf(t *testing.T) {
+	secondState := &testFSMState{
+		Name: "second",
+		Transition: func(_ context.Context, obj *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			// self-patches of earlier states are visible to later states
+			if obj.GetLabels()["phase"] != "first" {
+				return nil, fsmtypes.ErrorResult(fmt.Errorf("unexpected labels %v", obj.GetLabels()))
+			}
+			// conflicting self-patches resolve to the last state's
+			out.PatchSelf(func(o client.Object) {
+				o.SetLabels(map[string]string{"phase": "second"})
+			})
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+	initialState := &testFSMState{
+		Name: "first",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			out.PatchSelf(func(o client.Object) {
+				o.SetLabels(map[string]string{"phase": "first"})
+				o.SetAnnotations(map[string]string{"annotated": "true"})
+			})
+			return secondState, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	claim.Spec.TestField = "test"
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if diff := cmp.Diff(map[string]string{"phase": "second"}, actual.GetLabels()); diff != "" {
+		t.Errorf("unexpected labels (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"annotated": "true"}, actual.GetAnnotations()); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+	if actual.Spec.TestField != "test" {
+		t.Errorf("unexpected spec %v", actual.Spec)
+	}
+}
+
+func TestReconciler_ManagedResourcesMetric(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: testNamespace}}
+
+	var deleteSecret bool
+	initialState := &testFSMState{
+		Name: "apply-outputs",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			out.Apply(cm.DeepCopy())
+			if deleteSecret {
+				out.Delete(secret.DeepCopy())
+			} else {
+				out.Apply(secret.DeepCopy())
+			}
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	reg := prometheus.NewRegistry()
+	r.metrics = metrics.MustMakeMetrics(scheme, reg)
+	r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	expectManagedResources := func(expected int) {
+		t.Helper()
+		expectedMetric := fmt.Sprintf(`
+# HELP achilles_managed_resources The number of resources managed by an Achilles resource, i.e. its managed resource refs.
+# TYPE achilles_managed_resources gauge
+achilles_managed_resources{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",version="v1alpha1"} %d
+`, expected)
+		if err := testutil.GatherAndCompare(reg, strings.NewReader(expectedMetric), "achilles_managed_resources"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// gauge tracks the number of managed resource refs
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	expectManagedResources(2)
+
+	deleteSecret = true
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	expectManagedResources(1)
+
+	// gauge is deleted along with the object
+	if err := c.Delete(ctx, claim); err != nil {
+		t.Fatalf("deleting claim: %s", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if count, err := testutil.GatherAndCount(reg, "achilles_managed_resources"); err != nil || count != 0 {
+		t.Errorf("expected managed resources metric to be deleted, got %d (err: %v)", count, err)
+	}
+}
+
+func TestReconciler_ActiveReconcileDurationMetric(t *testing.T) {
+	initialState := &testFSMState{
+		Name: "requeue",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.RequeueResult("waiting", time.Minute)
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{}, claim)
+
+	reg := prometheus.NewRegistry()
+	r.metrics = metrics.MustMakeMetrics(scheme, reg)
+	r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	expectObservations := func(expected uint64) {
+		t.Helper()
+		families, err := reg.Gather()
+		if err != nil {
+			t.Fatalf("gathering metrics: %s", err)
+		}
+		var actual uint64
+		for _, family := range families {
+			if family.GetName() != "achilles_active_reconcile_seconds" {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				actual += m.GetHistogram().GetSampleCount()
+			}
+		}
+		if actual != expected {
+			t.Errorf("expected %d observations, got %d", expected, actual)
+		}
+	}
+
+	// one observation per reconcile, regardless of the requeue
+	for i := range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+		expectObservations(uint64(i + 1))
+	}
+
+	// reconciles of deleted objects are observed
+	if err := c.Delete(ctx, claim); err != nil {
+		t.Fatalf("deleting claim: %s", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	expectObservations(3)
+}
+
+func TestReconciler_PauseSwitch(t *testing.T) {
+	var transitions int
+	initialState := &testFSMState{
+		Name: "state",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			transitions++
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	paused := new(atomic.Bool)
+	paused.Store(true)
+
+	claim := newTestFSMClaim()
+	r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{PauseSwitch: paused}, claim)
+
+	reg := prometheus.NewRegistry()
+	r.metrics = metrics.MustMakeMetrics(scheme, reg)
+	r.metrics.InitializeForGVK(v1alpha1.TestClaimGroupVersionKind)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	expectPaused := func(expected float64) {
+		t.Helper()
+		expectedMetric := fmt.Sprintf(`
+# HELP achilles_controller_paused Gauge reporting whether the controller's reconciliation is paused or not
+# TYPE achilles_controller_paused gauge
+achilles_controller_paused{controller="test-claim"} %v
+`, expected)
+		if err := testutil.GatherAndCompare(reg, strings.NewReader(expectedMetric), "achilles_controller_paused"); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// reconciles no-op while paused
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if !res.IsZero() {
+		t.Errorf("expected done result while paused, got %+v", res)
+	}
+	if transitions != 0 {
+		t.Errorf("expected no transitions while paused, got %d", transitions)
+	}
+	actual := &v1alpha1.TestClaim{}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if actual.ResourceVersion != claim.ResourceVersion || len(actual.Status.Conditions) != 0 {
+		t.Errorf("expected claim to be unchanged while paused, got %+v", actual)
+	}
+	expectPaused(1)
+
+	// reconciles resume once unpaused
+	paused.Store(false)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if transitions != 1 {
+		t.Errorf("expected 1 transition once unpaused, got %d", transitions)
+	}
+	if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+		t.Fatalf("getting claim: %s", err)
+	}
+	if len(actual.Status.Conditions) == 0 {
+		t.Errorf("expected status conditions once unpaused, got none")
+	}
+	expectPaused(0)
+}
+
+// readyEventCounter counts recorded ready events
+type readyEventCounter struct {
+	count int
+}
+
+func (c *readyEventCounter) RecordReady(client.Object, string) {
+	c.count++
+}
+
+func TestReconciler_ReadyEvents(t *testing.T) {
+	var fail bool
+	initialState := &testFSMState{
+		Name:      "state",
+		Condition: api.Condition{Type: "Provisioned"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			if fail {
+				return nil, fsmtypes.ErrorResult(errors.New("flapping"))
+			}
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	recorder := &readyEventCounter{}
+	claim := newTestFSMClaim()
+	r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{ReadyEventRecorder: recorder}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	for i, tc := range []struct {
+		fail          bool
+		expectedCount int
+	}{
+		{expectedCount: 1}, // becomes ready
+		{expectedCount: 1}, // remains ready
+		{fail: true, expectedCount: 1},
+		{fail: true, expectedCount: 1},
+		{expectedCount: 2}, // becomes ready again
+		{expectedCount: 2},
+	} {
+		fail = tc.fail
+		_, _ = r.Reconcile(ctx, req)
+		if recorder.count != tc.expectedCount {
+			t.Errorf("reconcile %d: expected %d ready events, got %d", i, tc.expectedCount, recorder.count)
+		}
+	}
+}
+
+type warningEventRecorder struct {
+	reasons []string
+}
+
+func (r *warningEventRecorder) RecordWarning(_ client.Object, reason string, _ string) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func TestReconciler_RequeueEvents(t *testing.T) {
+	// reason of the requeue event, or done if empty
+	var reason string
+	initialState := &testFSMState{
+		Name:      "state",
+		Condition: api.Condition{Type: "Provisioned"},
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			if reason != "" {
+				return nil, fsmtypes.RequeueResultWithEvent("waiting for quota", reason, time.Second)
+			}
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	recorder := &warningEventRecorder{}
+	claim := newTestFSMClaim()
+	r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{RequeueEventRecorder: recorder}, claim)
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	for i, tc := range []struct {
+		reason          string
+		expectedReasons []string
+	}{
+		{reason: "QuotaExceeded", expectedReasons: []string{"QuotaExceeded"}},
+		{reason: "QuotaExceeded", expectedReasons: []string{"QuotaExceeded"}}, // repeated requeue
+		{reason: "Throttled", expectedReasons: []string{"QuotaExceeded", "Throttled"}},
+		{reason: "Throttled", expectedReasons: []string{"QuotaExceeded", "Throttled"}},
+		{expectedReasons: []string{"QuotaExceeded", "Throttled"}}, // recovers
+		{reason: "Throttled", expectedReasons: []string{"QuotaExceeded", "Throttled", "Throttled"}},
+	} {
+		reason = tc.reason
+		_, _ = r.Reconcile(ctx, req)
+		if diff := cmp.Diff(tc.expectedReasons, recorder.reasons); diff != "" {
+			t.Errorf("reconcile %d: unexpected event reasons (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestReconciler_ShadowApplicator(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}, Data: map[string]string{"foo": "bar"}}
+
+	var deleteConfigMap bool
+	initialState := &testFSMState{
+		Name: "apply-outputs",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			if deleteConfigMap {
+				out.Delete(cm.DeepCopy())
+			} else {
+				out.Apply(cm.DeepCopy())
+			}
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	ctx := context.Background()
+
+	t.Run("applies and deletes outputs in shadow", func(t *testing.T) {
+		deleteConfigMap = false
+		shadow := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		claim := newTestFSMClaim()
+		r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+			ShadowApplicator: &io.ClientApplicator{Client: shadow, Applicator: io.NewAPIPatchingApplicator(shadow)},
+		}, claim)
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+
+		for name, cl := range map[string]client.Client{"primary": c, "shadow": shadow} {
+			actual := &corev1.ConfigMap{}
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+				t.Fatalf("getting %s config map: %s", name, err)
+			}
+			if diff := cmp.Diff(cm.Data, actual.Data); diff != "" {
+				t.Errorf("unexpected %s config map data (-want +got):\n%s", name, diff)
+			}
+		}
+
+		// shadow objects don't reference owners in the primary cluster
+		actual := &corev1.ConfigMap{}
+		if err := shadow.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+			t.Fatalf("getting shadow config map: %s", err)
+		}
+		if len(actual.OwnerReferences) != 0 {
+			t.Errorf("expected no owner references on shadow config map, got %v", actual.OwnerReferences)
+		}
+
+		deleteConfigMap = true
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+		for name, cl := range map[string]client.Client{"primary": c, "shadow": shadow} {
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); !k8serrors.IsNotFound(err) {
+				t.Errorf("expected %s config map to be deleted, got error %v", name, err)
+			}
+		}
+	})
+
+	t.Run("shadow failure doesn't fail reconcile", func(t *testing.T) {
+		deleteConfigMap = false
+		shadow := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(context.Context, client.WithWatch, client.Object, ...client.CreateOption) error {
+				return errors.New("shadow unavailable")
+			},
+		}).Build()
+
+		claim := newTestFSMClaim()
+		r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+			ShadowApplicator: &io.ClientApplicator{Client: shadow, Applicator: io.NewAPIPatchingApplicator(shadow)},
+		}, claim)
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+		res, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+		if !res.IsZero() {
+			t.Errorf("expected zero result, got %v", res)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+			t.Errorf("getting primary config map: %s", err)
+		}
+	})
+}
+
+func TestReconciler_VersionAnnotation(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}}
+
+	initialState := &testFSMState{
+		Name: "apply-outputs",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			out.Apply(cm.DeepCopy())
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	opts := fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{VersionAnnotation: "v1"}
+	r, c := newTestFSMReconciler(t, initialState, opts, claim)
+
+	// count patches of the reconciled object's metadata
+	var selfPatches int
+	r.client = testApplicator(interceptor.NewClient(c.Client.(client.WithWatch), interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if _, ok := obj.(*metav1.PartialObjectMetadata); ok {
+				selfPatches++
+			}
+			return cl.Patch(ctx, obj, patch, opts...)
+		},
+	}))
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+
+	expectVersions := func(expectedClaim, expectedConfigMap string) {
+		t.Helper()
+		actualClaim := &v1alpha1.TestClaim{}
+		if err := c.Get(ctx, req.NamespacedName, actualClaim); err != nil {
+			t.Fatalf("getting claim: %s", err)
+		}
+		if actual := actualClaim.GetAnnotations()[io.ReconciledByVersionAnnotationKey]; actual != expectedClaim {
+			t.Errorf("expected claim version annotation %q, got %q", expectedClaim, actual)
+		}
+		actualConfigMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actualConfigMap); err != nil {
+			t.Fatalf("getting config map: %s", err)
+		}
+		if actual := actualConfigMap.GetAnnotations()[io.ReconciledByVersionAnnotationKey]; actual != expectedConfigMap {
+			t.Errorf("expected config map version annotation %q, got %q", expectedConfigMap, actual)
+		}
+	}
+
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+	}
+	expectVersions("v1", "v1")
+	// the reconciled object is only patched once per version
+	if selfPatches != 1 {
+		t.Errorf("expected 1 patch of the reconciled object, got %d", selfPatches)
+	}
+
+	r.reconcilerOptions.VersionAnnotation = "v2"
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("running reconciler: %s", err)
+		}
+	}
+	expectVersions("v2", "v2")
+	if selfPatches != 2 {
+		t.Errorf("expected 2 patches of the reconciled object, got %d", selfPatches)
+	}
+}
+
+func TestReconciler_MaxConditionMessageLength(t *testing.T) {
+	cases := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "shorter message",
+			message:  "waiting",
+			expected: "waiting (requeued)",
+		},
+		{
+			name:     "longer multibyte message",
+			message:  strings.Repeat("ö", 30),
+			expected: strings.Repeat("ö", 20) + "...",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialState := &testFSMState{
+				Name:      "state",
+				Condition: api.Condition{Type: "State"},
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					return nil, fsmtypes.RequeueResult(tc.message, time.Minute)
+				},
+			}
+
+			claim := newTestFSMClaim()
+			r, c := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+				MaxConditionMessageLength: 20,
+			}, claim)
+
+			ctx := context.Background()
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("running reconciler: %s", err)
+			}
+
+			actual := &v1alpha1.TestClaim{}
+			if err := c.Get(ctx, req.NamespacedName, actual); err != nil {
+				t.Fatalf("getting claim: %s", err)
+			}
+			if message := actual.GetCondition("State").Message; message != tc.expected {
+				t.Errorf("expected State condition message %q, got %q", tc.expected, message)
+			}
+			if message := actual.GetCondition(api.TypeReady).Message; len([]rune(message)) > 20+len("...") {
+				t.Errorf("expected Ready condition message to be truncated, got %q", message)
+			}
+		})
+	}
+}
+
+func TestReconciler_MinRequeueInterval(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   fsmtypes.Result
+		expected ctrl.Result
+	}{
+		{
+			name:     "sub-floor requeue",
+			result:   fsmtypes.RequeueResult("requeue", -time.Second),
+			expected: ctrl.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name:     "requeue with backoff",
+			result:   fsmtypes.RequeueResultWithBackoff("requeue"),
+			expected: ctrl.Result{Requeue: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialState := &testFSMState{
+				Name: "state",
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+					return nil, tc.result
+				},
+			}
+
+			claim := newTestFSMClaim()
+			r, _ := newTestFSMReconciler(t, initialState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+				MinRequeueInterval: time.Minute,
+			}, claim)
+
+			actual, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+			if err != nil {
+				t.Fatalf("running reconciler: %s", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("unexpected result %v, want %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestReconciler_MinRequeueIntervalWithMaxStatesPerReconcile(t *testing.T) {
+	stateB := &testFSMState{
+		Name: "b",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.DoneResult()
+		},
+	}
+	stateA := &testFSMState{
+		Name: "a",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return stateB, fsmtypes.DoneResult()
+		},
+	}
+
+	claim := newTestFSMClaim()
+	r, _ := newTestFSMReconciler(t, stateA, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		MaxStatesPerReconcile: 1,
+		MinRequeueInterval:    time.Minute,
+	}, claim)
+
+	// the requeue for resuming a paused reconcile isn't raised to the minimum requeue interval
+	actual, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+	if err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if expected := (ctrl.Result{RequeueAfter: resumeRequeueAfter}); actual != expected {
+		t.Errorf("unexpected result %v, want %v", actual, expected)
+	}
+
+	// requeues of transitions are raised to the minimum requeue interval, even with the reason of a paused reconcile
+	requeueState := &testFSMState{
+		Name: "requeue",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *fsmtypes.OutputSet) (*testFSMState, fsmtypes.Result) {
+			return nil, fsmtypes.RequeueResultWithReason("waiting", pausedReason, time.Millisecond)
+		},
+	}
+	claim = newTestFSMClaim()
+	r, _ = newTestFSMReconciler(t, requeueState, fsmtypes.ReconcilerOptions[v1alpha1.TestClaim, *v1alpha1.TestClaim]{
+		MaxStatesPerReconcile: 1,
+		MinRequeueInterval:    time.Minute,
+	}, claim)
+
+	actual, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+	if err != nil {
+		t.Fatalf("running reconciler: %s", err)
+	}
+	if expected := (ctrl.Result{RequeueAfter: time.Minute}); actual != expected {
+		t.Errorf("unexpected result %v, want %v", actual, expected)
+	}
+}
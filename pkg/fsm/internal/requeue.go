@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var _ source.Source = &requeueSource{}
+
+// requeueSource is a source.Source that captures the controller's work queue when started by the controller,
+// allowing the reconciler to enqueue requests for objects other than the one being reconciled.
+type requeueSource struct {
+	mu    sync.RWMutex
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+}
+
+// Start implements source.Source.
+func (s *requeueSource) Start(_ context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = queue
+	return nil
+}
+
+// enqueue adds the requests to the controller's work queue. Returns false if the source hasn't been started.
+func (s *requeueSource) enqueue(reqs ...reconcile.Request) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.queue == nil {
+		return false
+	}
+	for _, req := range reqs {
+		s.queue.Add(req)
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requeueEventTracker tracks the reason of the last requeue event recorded for each object, so that objects repeatedly
+// requeueing for the same reason record a single event.
+type requeueEventTracker struct {
+	mu sync.Mutex
+	// a map of object key to the reason of the event recorded by its last reconcile
+	reasons map[client.ObjectKey]string
+}
+
+// newRequeueEventTracker returns a requeueEventTracker, or nil if requeue events aren't recorded.
+func newRequeueEventTracker(enabled bool) *requeueEventTracker {
+	if !enabled {
+		return nil
+	}
+	return &requeueEventTracker{
+		reasons: map[client.ObjectKey]string{},
+	}
+}
+
+// shouldRecord records the reason of the object's requeue event and returns true if the object's last reconcile
+// didn't requeue with an event of the same reason.
+func (t *requeueEventTracker) shouldRecord(key client.ObjectKey, reason string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.reasons[key]; ok && last == reason {
+		return false
+	}
+	t.reasons[key] = reason
+	return true
+}
+
+// forget stops tracking the object's requeue events, e.g. once it reconciles without requeueing with an event
+func (t *requeueEventTracker) forget(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.reasons, key)
+}
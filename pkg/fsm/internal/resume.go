@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/fsm/types"
+)
+
+// resumeStates tracks, per object, the state from which to resume a reconcile paused due to
+// ReconcilerOptions.MaxStatesPerReconcile.
+type resumeStates[Obj client.Object] struct {
+	mu     sync.Mutex
+	states map[client.ObjectKey]resumeState[Obj]
+}
+
+type resumeState[Obj client.Object] struct {
+	state *types.State[Obj]
+	// generation is the object's generation when the reconcile was paused
+	generation int64
+	// conditions are the status conditions accumulated by the states executed before the reconcile was paused
+	conditions []api.Condition
+	// requeueAfterCompletion is the requeue after completion signaled by the states executed before the reconcile was paused
+	requeueAfterCompletion types.Result
+}
+
+func newResumeStates[Obj client.Object]() *resumeStates[Obj] {
+	return &resumeStates[Obj]{
+		states: map[client.ObjectKey]resumeState[Obj]{},
+	}
+}
+
+// push records the state from which to resume reconciling the object, along with the conditions and requeue after
+// completion accumulated by the states executed so far.
+func (r *resumeStates[Obj]) push(
+	key client.ObjectKey,
+	obj Obj,
+	state *types.State[Obj],
+	conditions []api.Condition,
+	requeueAfterCompletion types.Result,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states[key] = resumeState[Obj]{
+		state:                  state,
+		generation:             obj.GetGeneration(),
+		conditions:             conditions,
+		requeueAfterCompletion: requeueAfterCompletion,
+	}
+}
+
+// pop returns and forgets the state from which to resume reconciling the object.
+// Returns false if the reconcile wasn't paused or if the object's generation changed since it was paused,
+// in which case the FSM must be evaluated from the initial state.
+func (r *resumeStates[Obj]) pop(key client.ObjectKey, obj Obj) (resumeState[Obj], bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.states[key]
+	if !ok {
+		return resumeState[Obj]{}, false
+	}
+	delete(r.states, key)
+
+	if s.generation != obj.GetGeneration() {
+		return resumeState[Obj]{}, false
+	}
+	return s, true
+}
+
+// forget forgets the state from which to resume reconciling the object.
+func (r *resumeStates[Obj]) forget(key client.ObjectKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.states, key)
+}
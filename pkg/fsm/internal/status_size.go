@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultStatusConditionsThreshold is the default number of status conditions above which conditions are suspected to grow unbounded
+	defaultStatusConditionsThreshold = 50
+	// defaultStatusManagedResourcesThreshold is the default number of managed resource refs above which refs are suspected to grow unbounded
+	defaultStatusManagedResourcesThreshold = 1000
+
+	statusFieldConditions   = "conditions"
+	statusFieldResourceRefs = "resourceRefs"
+)
+
+// statusSizeGuard detects status fields exceeding their size threshold, which suggests that they grow without bound,
+// e.g. due to a controller bug adding status conditions of ever-changing types or leaking managed resource refs.
+type statusSizeGuard struct {
+	// a map of status field to its threshold, fields without threshold aren't checked
+	thresholds map[string]int
+
+	mu sync.Mutex
+	// a map of object key to the status fields exceeding their threshold as of the object's last reconcile
+	exceeded map[client.ObjectKey]map[string]bool
+}
+
+// newStatusSizeGuard returns a statusSizeGuard, or nil if both thresholds are negative.
+// Zero thresholds are replaced by their defaults.
+func newStatusSizeGuard(conditionsThreshold, managedResourcesThreshold int) *statusSizeGuard {
+	if conditionsThreshold < 0 && managedResourcesThreshold < 0 {
+		return nil
+	}
+
+	thresholds := map[string]int{}
+	if threshold := withDefault(conditionsThreshold, defaultStatusConditionsThreshold); threshold > 0 {
+		thresholds[statusFieldConditions] = threshold
+	}
+	if threshold := withDefault(managedResourcesThreshold, defaultStatusManagedResourcesThreshold); threshold > 0 {
+		thresholds[statusFieldResourceRefs] = threshold
+	}
+
+	return &statusSizeGuard{
+		thresholds: thresholds,
+		exceeded:   map[client.ObjectKey]map[string]bool{},
+	}
+}
+
+// observe records the size of the object's status field, returning whether the field is checked, whether it exceeds its
+// threshold, and whether it newly exceeds its threshold since the object's last reconcile.
+func (g *statusSizeGuard) observe(key client.ObjectKey, field string, size int) (checked, exceeded, newlyExceeded bool) {
+	threshold, ok := g.thresholds[field]
+	if !ok {
+		return false, false, false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	exceeded = size > threshold
+	fields := g.exceeded[key]
+	newlyExceeded = exceeded && !fields[field]
+	if exceeded {
+		if fields == nil {
+			fields = map[string]bool{}
+			g.exceeded[key] = fields
+		}
+		fields[field] = true
+	} else if fields != nil {
+		delete(fields, field)
+		if len(fields) == 0 {
+			delete(g.exceeded, key)
+		}
+	}
+	return true, exceeded, newlyExceeded
+}
+
+// forget forgets the status fields of the object exceeding their threshold.
+func (g *statusSizeGuard) forget(key client.ObjectKey) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.exceeded, key)
+}
+
+// withDefault returns defaultThreshold if threshold is zero, otherwise threshold.
+func withDefault(threshold, defaultThreshold int) int {
+	if threshold == 0 {
+		return defaultThreshold
+	}
+	return threshold
+}
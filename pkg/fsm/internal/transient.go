@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// transientErrorTracker tracks when each object's reconciles first started failing with transient errors, so that
+// status conditions are only updated once the errors persist beyond the grace period.
+//
+// NOTE: tracking is in-memory, so the grace period restarts when the controller restarts.
+type transientErrorTracker struct {
+	gracePeriod time.Duration
+
+	mu sync.Mutex
+	// a map of object key to the time its reconciles first failed with a transient error
+	firstFailed map[client.ObjectKey]time.Time
+
+	// now returns the current time, overridden in tests
+	now func() time.Time
+}
+
+// newTransientErrorTracker returns a transientErrorTracker, or nil if gracePeriod is not positive.
+func newTransientErrorTracker(gracePeriod time.Duration) *transientErrorTracker {
+	if gracePeriod <= 0 {
+		return nil
+	}
+	return &transientErrorTracker{
+		gracePeriod: gracePeriod,
+		firstFailed: map[client.ObjectKey]time.Time{},
+		now:         time.Now,
+	}
+}
+
+// tolerate records a transient failure of the object's reconcile and returns true if its reconciles have been failing
+// with transient errors for less than the grace period.
+func (t *transientErrorTracker) tolerate(key client.ObjectKey) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	first, ok := t.firstFailed[key]
+	if !ok {
+		t.firstFailed[key] = now
+		first = now
+	}
+
+	return now.Sub(first) < t.gracePeriod
+}
+
+// forget stops tracking the object, called when its reconcile doesn't fail with a transient error.
+func (t *transientErrorTracker) forget(key client.ObjectKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.firstFailed, key)
+}
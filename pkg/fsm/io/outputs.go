@@ -3,10 +3,16 @@ package io
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/reddit/achilles-sdk-api/api"
@@ -16,6 +22,63 @@ import (
 	"github.com/reddit/achilles-sdk/pkg/meta"
 )
 
+// ApplyOutputSetOption configures ApplyOutputSet.
+type ApplyOutputSetOption func(*applyOutputSetOptions)
+
+type applyOutputSetOptions struct {
+	// missingRefTracker, if set, delays pruning of managed resource refs whose objects are not found.
+	missingRefTracker *MissingRefTracker
+	// onRefsRetained, if set, is invoked with the smallest remaining grace period of refs retained by missingRefTracker.
+	onRefsRetained func(remaining time.Duration)
+	// withoutDefaultControllerRef, if true, prevents the default controller reference from being set on applied outputs.
+	withoutDefaultControllerRef bool
+	// version, if not empty, is recorded on applied outputs with io.WithVersionAnnotation.
+	version string
+	// shadow, if set, is the applicator to which outputs are additionally applied on a best-effort basis.
+	shadow *io.ClientApplicator
+}
+
+// WithMissingRefTracker delays pruning of managed resource refs whose objects are not found using the supplied tracker.
+// If not set, such refs are pruned immediately.
+func WithMissingRefTracker(tracker *MissingRefTracker) ApplyOutputSetOption {
+	return func(o *applyOutputSetOptions) {
+		o.missingRefTracker = tracker
+	}
+}
+
+// WithRetainedRefsCallback invokes fn with the smallest remaining grace period of the managed resource refs retained
+// by the missing ref tracker (see WithMissingRefTracker), if any, so that the caller can requeue to prune them once it
+// elapses. fn isn't invoked if no refs are retained.
+func WithRetainedRefsCallback(fn func(remaining time.Duration)) ApplyOutputSetOption {
+	return func(o *applyOutputSetOptions) {
+		o.onRefsRetained = fn
+	}
+}
+
+// WithoutDefaultControllerRef prevents the controller reference to the reconciled object, otherwise set by default,
+// from being set on applied outputs. Owner references explicitly requested for an output (e.g. through io.WithOwnerRef) are still set.
+func WithoutDefaultControllerRef() ApplyOutputSetOption {
+	return func(o *applyOutputSetOptions) {
+		o.withoutDefaultControllerRef = true
+	}
+}
+
+// WithVersionAnnotation records the controller version on applied outputs, see io.WithVersionAnnotation.
+func WithVersionAnnotation(version string) ApplyOutputSetOption {
+	return func(o *applyOutputSetOptions) {
+		o.version = version
+	}
+}
+
+// WithShadowApplicator additionally applies and deletes outputs with the supplied applicator, typically a client for a
+// secondary cluster mirroring the primary. Failures are logged and don't fail ApplyOutputSet.
+// Outputs are applied to the shadow without owner references, since their owners only exist in the primary cluster.
+func WithShadowApplicator(c *io.ClientApplicator) ApplyOutputSetOption {
+	return func(o *applyOutputSetOptions) {
+		o.shadow = c
+	}
+}
+
 // ApplyOutputSet ensures that all objects declared in the OutputSet are applied,
 // ensuring extant outputs and deleting outputs that are no longer needed.
 // Metadata tracking extant outputs are persisted onto the specified object's status.
@@ -26,7 +89,24 @@ func ApplyOutputSet[T any, Obj apitypes.FSMResource[T]](
 	scheme *runtime.Scheme,
 	obj Obj,
 	out *types.OutputSet,
+	options ...ApplyOutputSetOption,
 ) error {
+	opts := &applyOutputSetOptions{}
+	for _, o := range options {
+		o(opts)
+	}
+
+	// stage deletion of pruned objects
+	if err := stagePrunes(ctx, c, scheme, obj, out); err != nil {
+		return fmt.Errorf("pruning objects: %w", err)
+	}
+
+	// copy outputs before they're applied to the primary, which populates server-side fields (e.g. uid and resourceVersion)
+	var shadowOutputs []types.OutputObject
+	if opts.shadow != nil {
+		shadowOutputs = shadowCopies(out.ListAppliedOutputs())
+	}
+
 	// delete resources
 	for _, o := range out.ListDeleted() {
 		if err := c.Delete(ctx, o); err != nil && !k8serrors.IsNotFound(err) {
@@ -35,20 +115,169 @@ func ApplyOutputSet[T any, Obj apitypes.FSMResource[T]](
 	}
 
 	// ensure output resources
-	if err := ensureOutputs(ctx, c, scheme, obj, out.ListAppliedOutputs()); err != nil {
+	if err := ensureOutputs(ctx, c, scheme, obj, out.ListAppliedOutputs(), opts); err != nil {
 		return fmt.Errorf("ensuring outputs: %w", err)
 	}
 
 	// apply managed resource refs to status
 	// NOTE: do this after ensuring the manage resource objects to prevent adding a managed resource ref for an
 	// object that wasn't created successfully
-	if err := applyManagedResourceRefs(ctx, log, c, scheme, obj, out); err != nil {
+	remaining, err := applyManagedResourceRefs(ctx, log, c, scheme, obj, out, opts.missingRefTracker)
+	if err != nil {
 		return fmt.Errorf("applying managed resource refs: %w", err)
 	}
+	if remaining > 0 && opts.onRefsRetained != nil {
+		opts.onRefsRetained(remaining)
+	}
+
+	if err := applySelfPatches(ctx, c, scheme, obj, out.ListSelfPatches()); err != nil {
+		return fmt.Errorf("patching metadata: %w", err)
+	}
+
+	if opts.shadow != nil {
+		applyShadow(ctx, log, opts.shadow, obj, out.ListDeleted(), shadowOutputs)
+	}
+
+	return nil
+}
+
+// stagePrunes stages deletion of the listed objects controlled by obj that aren't kept by their prune request.
+func stagePrunes[T any, Obj apitypes.FSMResource[T]](
+	ctx context.Context,
+	c *io.ClientApplicator,
+	scheme *runtime.Scheme,
+	obj Obj,
+	out *types.OutputSet,
+) error {
+	applied := out.GetApplied()
+	for _, prune := range out.ListPrunes() {
+		o, err := scheme.New(prune.GVK.GroupVersion().WithKind(prune.GVK.Kind + "List"))
+		if err != nil {
+			return fmt.Errorf("constructing list for %s: %w", prune.GVK, err)
+		}
+		list, ok := o.(client.ObjectList)
+		if !ok {
+			return fmt.Errorf("list type %T for %s is not a client.ObjectList", o, prune.GVK)
+		}
 
+		if err := c.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+			return fmt.Errorf("listing %s: %w", prune.GVK, err)
+		}
+
+		if err := apimeta.EachListItem(list, func(item runtime.Object) error {
+			o := item.(client.Object)
+			if !metav1.IsControlledBy(o, obj) || meta.WasDeleted(o) {
+				return nil
+			}
+			if ref := meta.MustTypedObjectRefFromObject(o, scheme); applied.GetByRef(*ref) != nil || prune.Keep(o) {
+				return nil
+			}
+			out.Delete(o)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// shadowCopies returns copies of the outputs stripped of server-side fields and owner references.
+func shadowCopies(outputs []types.OutputObject) []types.OutputObject {
+	copies := make([]types.OutputObject, 0, len(outputs))
+	for _, output := range outputs {
+		res := output.Object.DeepCopyObject().(client.Object)
+		res.SetUID("")
+		res.SetResourceVersion("")
+		res.SetOwnerReferences(nil)
+		copies = append(copies, types.OutputObject{
+			Object:    res,
+			ApplyOpts: append(slices.Clone(output.ApplyOpts), io.WithoutOwnerRefs()),
+		})
+	}
+	return copies
+}
+
+// applyShadow deletes and applies the outputs with the shadow applicator, logging failures.
+// Like for the primary, no objects are created while the reconciled object is being deleted.
+func applyShadow(
+	ctx context.Context,
+	log *zap.SugaredLogger,
+	c *io.ClientApplicator,
+	obj client.Object,
+	deleted []client.Object,
+	outputs []types.OutputObject,
+) {
+	for _, o := range deleted {
+		if err := c.Delete(ctx, o.DeepCopyObject().(client.Object)); err != nil && !k8serrors.IsNotFound(err) {
+			log.Errorf("deleting shadow object %T %s: %s", o, client.ObjectKeyFromObject(o), err)
+		}
+	}
+
+	if meta.WasDeleted(obj) {
+		return
+	}
+
+	for _, output := range outputs {
+		if err := c.Apply(ctx, output.Object, output.ApplyOpts...); err != nil {
+			log.Errorf("applying shadow object %T %s: %s", output.Object, client.ObjectKeyFromObject(output.Object), err)
+		}
+	}
+}
+
+// applySelfPatches applies the mutations to the object's labels and annotations as a merge patch of its metadata,
+// and updates the object in place.
+func applySelfPatches[T any, Obj apitypes.FSMResource[T]](
+	ctx context.Context,
+	c *io.ClientApplicator,
+	scheme *runtime.Scheme,
+	obj Obj,
+	patches []func(client.Object),
+) error {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	mutated := obj.DeepCopyObject().(client.Object)
+	for _, mutate := range patches {
+		mutate(mutated)
+	}
+
+	gvk := meta.MustGVKForObject(obj, scheme)
+	current := metadataOf(gvk, obj)
+	desired := metadataOf(gvk, mutated)
+	if equality.Semantic.DeepEqual(current, desired) {
+		return nil
+	}
+
+	if err := c.Patch(ctx, desired, client.MergeFrom(current)); err != nil {
+		return err
+	}
+
+	obj.SetLabels(desired.GetLabels())
+	obj.SetAnnotations(desired.GetAnnotations())
+	obj.SetResourceVersion(desired.GetResourceVersion())
+	return nil
+}
+
+// metadataOf returns the object's identity, labels, and annotations
+func metadataOf(gvk schema.GroupVersionKind, obj client.Object) *metav1.PartialObjectMetadata {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+			Kind:       kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        obj.GetName(),
+			Namespace:   obj.GetNamespace(),
+			Labels:      obj.GetLabels(),
+			Annotations: obj.GetAnnotations(),
+		},
+	}
+}
+
+// applyManagedResourceRefs persists the refs of the object's managed resources onto its status, returning the smallest
+// remaining grace period of refs retained for missing objects, or zero if none are retained.
 func applyManagedResourceRefs[T any, Obj apitypes.FSMResource[T]](
 	ctx context.Context,
 	log *zap.SugaredLogger,
@@ -56,7 +285,8 @@ func applyManagedResourceRefs[T any, Obj apitypes.FSMResource[T]](
 	scheme *runtime.Scheme,
 	obj Obj,
 	outputSet *types.OutputSet,
-) error {
+	missingRefTracker *MissingRefTracker,
+) (time.Duration, error) {
 	// initialize an empty object so we only update the status' resource refs
 	copy := Obj(new(T))
 	copy.SetName(obj.GetName())
@@ -68,20 +298,39 @@ func applyManagedResourceRefs[T any, Obj apitypes.FSMResource[T]](
 	// accumulate managed resource refs across all states by starting with the status' managed resources,
 	// and deleting explicitly deleted objects and inserting any new objects (while deduplicating)
 	refs := []api.TypedObjectRef{} // explicitly signal deletion if there are no managed resources
+	var minRemaining time.Duration
 	for _, ref := range obj.GetManagedResources() {
 		// verify that managed object exists, emit warning if not
 		managedObj, err := meta.NewObjectForGVK(scheme, ref.GroupVersionKind())
 		if err != nil {
-			return fmt.Errorf("constructing new %T %s: %w", managedObj, client.ObjectKeyFromObject(managedObj), err)
+			return 0, fmt.Errorf("constructing new %T %s: %w", managedObj, client.ObjectKeyFromObject(managedObj), err)
 		}
 		managedObj.SetName(ref.Name)
 		managedObj.SetNamespace(ref.Namespace)
 
 		if err := c.Get(ctx, client.ObjectKeyFromObject(managedObj), managedObj); err != nil {
 			if k8serrors.IsNotFound(err) {
+				explicitlyDeleted := deleted.GetByRef(ref) != nil
+
+				// retain refs for missing objects that weren't explicitly deleted until the grace period elapses
+				if missingRefTracker != nil {
+					parentRef := *meta.MustTypedObjectRefFromObject(obj, scheme)
+					if explicitlyDeleted {
+						missingRefTracker.forget(parentRef, ref)
+					} else if prune, remaining := missingRefTracker.shouldPrune(parentRef, ref); !prune {
+						log.Debugf("managed resource %s of type %T not found, retaining ref until grace period elapses", client.ObjectKeyFromObject(managedObj), managedObj)
+						if minRemaining == 0 || remaining < minRemaining {
+							minRemaining = remaining
+						}
+						newRefs.DeleteByRef(ref)
+						refs = append(refs, ref)
+						continue
+					}
+				}
+
 				// warn for managed resource that wasn't explicitly deleted by the controller, but is deleted on the kube-apiserver
 				// this shouldn't happen unless an external actor tampers with the state by deleting the object
-				if deleted.GetByRef(ref) == nil {
+				if !explicitlyDeleted {
 					log.Warnf(
 						"managed resource %s of type %T not found, an external actor may have deleted it",
 						client.ObjectKeyFromObject(managedObj),
@@ -90,28 +339,30 @@ func applyManagedResourceRefs[T any, Obj apitypes.FSMResource[T]](
 				}
 				continue // remove refs for deleted objects
 			} else {
-				return fmt.Errorf("getting managed resource: %w", err)
+				return 0, fmt.Errorf("getting managed resource: %w", err)
 			}
 		}
 
+		if missingRefTracker != nil {
+			missingRefTracker.forget(*meta.MustTypedObjectRefFromObject(obj, scheme), ref)
+		}
+
 		// remove ref from newly ensured objects (to prevent duplicate refs for objects that are applied in multiple states)
 		newRefs.DeleteByRef(ref)
 
 		refs = append(refs, ref)
 	}
 
-	for _, newRef := range newRefs.List() {
-		refs = append(refs, *meta.MustTypedObjectRefFromObject(newRef, scheme))
-	}
+	refs = append(refs, newRefs.ListRefs()...)
 	copy.SetManagedResources(refs)
 
 	if err := c.ApplyStatus(ctx, copy); err != nil {
-		return fmt.Errorf("applying status resourceRefs: %w", err)
+		return 0, fmt.Errorf("applying status resourceRefs: %w", err)
 	}
 
 	// update in-memory obj
 	obj.SetManagedResources(refs)
-	return nil
+	return minRemaining, nil
 }
 
 func ensureOutputs[T any, Obj apitypes.FSMResource[T]](
@@ -120,6 +371,7 @@ func ensureOutputs[T any, Obj apitypes.FSMResource[T]](
 	scheme *runtime.Scheme,
 	obj Obj,
 	outputs []types.OutputObject,
+	opts *applyOutputSetOptions,
 ) error {
 	for _, output := range outputs {
 		res := output.Object
@@ -146,8 +398,14 @@ func ensureOutputs[T any, Obj apitypes.FSMResource[T]](
 				}
 			}
 		} else {
-			// NOTE: add the default WithControllerRef last so it's not invoked if WithoutOwnerRefs is set
-			applyOpts := append(output.ApplyOpts, io.WithControllerRef(obj, scheme))
+			applyOpts := output.ApplyOpts
+			if opts.version != "" {
+				applyOpts = append(slices.Clone(applyOpts), io.WithVersionAnnotation(opts.version))
+			}
+			if !opts.withoutDefaultControllerRef {
+				// NOTE: add the default WithControllerRef last so it's not invoked if WithoutOwnerRefs is set
+				applyOpts = append(applyOpts, io.WithControllerRef(obj, scheme))
+			}
 
 			if err := c.Apply(ctx, res, applyOpts...); err != nil {
 				return fmt.Errorf("ensuring %s %s: %w", res.GetObjectKind().GroupVersionKind(), res.GetName(), err)
@@ -0,0 +1,276 @@
+package io
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/fsm/types"
+	intscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+func Test_ApplyOutputSet_MissingRefGracePeriod(t *testing.T) {
+	log := zaptest.NewLogger(t).Sugar()
+	scheme := intscheme.MustNewScheme()
+	gracePeriod := time.Minute
+
+	missing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"}}
+	missingRef := *meta.MustTypedObjectRefFromObject(missing, scheme)
+
+	tcs := []struct {
+		name string
+		// tracker, if nil, prunes missing refs immediately
+		tracker *MissingRefTracker
+		// deleted, if true, the missing object is explicitly deleted by the controller
+		deleted bool
+		// elapsed is the time elapsed since the first pass, for each successive pass
+		elapsed      []time.Duration
+		expectedRefs [][]api.TypedObjectRef
+		// expectedRemaining is the remaining grace period reported for retained refs, for each successive pass
+		expectedRemaining []time.Duration
+	}{
+		{
+			name:         "no grace period",
+			elapsed:      []time.Duration{0},
+			expectedRefs: [][]api.TypedObjectRef{{}},
+		},
+		{
+			name:    "transiently missing ref is retained until grace period elapses",
+			tracker: NewMissingRefTracker(gracePeriod),
+			elapsed: []time.Duration{0, 30 * time.Second, gracePeriod},
+			expectedRefs: [][]api.TypedObjectRef{
+				{missingRef},
+				{missingRef},
+				{},
+			},
+			expectedRemaining: []time.Duration{gracePeriod, 30 * time.Second, 0},
+		},
+		{
+			name:         "explicitly deleted ref is pruned immediately",
+			tracker:      NewMissingRefTracker(gracePeriod),
+			deleted:      true,
+			elapsed:      []time.Duration{0},
+			expectedRefs: [][]api.TypedObjectRef{{}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			parent := &v1alpha1.TestClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"},
+				Status: v1alpha1.TestClaimStatus{
+					ResourceRefs: []api.TypedObjectRef{missingRef},
+				},
+			}
+			fakeC := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(parent).
+				WithStatusSubresource(parent).
+				Build()
+			c := &io.ClientApplicator{
+				Client:     fakeC,
+				Applicator: io.NewAPIPatchingApplicator(fakeC),
+			}
+
+			var remaining time.Duration
+			opts := []ApplyOutputSetOption{
+				WithRetainedRefsCallback(func(r time.Duration) { remaining = r }),
+			}
+			start := time.Now()
+			if tc.tracker != nil {
+				opts = append(opts, WithMissingRefTracker(tc.tracker))
+			}
+
+			for i, elapsed := range tc.elapsed {
+				if tc.tracker != nil {
+					tc.tracker.now = func() time.Time { return start.Add(elapsed) }
+				}
+
+				out := types.NewOutputSet(scheme)
+				if tc.deleted {
+					out.Delete(missing.DeepCopy())
+				}
+
+				obj := &v1alpha1.TestClaim{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(parent), obj))
+				remaining = 0
+				assert.NoError(t, ApplyOutputSet(ctx, log, c, scheme, obj, out, opts...))
+
+				actual := &v1alpha1.TestClaim{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(parent), actual))
+				assert.ElementsMatch(t, tc.expectedRefs[i], actual.GetManagedResources(), "pass %d", i)
+
+				var expectedRemaining time.Duration
+				if tc.expectedRemaining != nil {
+					expectedRemaining = tc.expectedRemaining[i]
+				}
+				assert.Equal(t, expectedRemaining, remaining, "pass %d", i)
+			}
+
+			// pruned and explicitly deleted refs are no longer tracked
+			if tc.tracker != nil {
+				assert.Empty(t, tc.tracker.firstMissing)
+			}
+		})
+	}
+}
+
+func Test_MissingRefTracker_ForgetParent(t *testing.T) {
+	tracker := NewMissingRefTracker(time.Minute)
+	parent := api.TypedObjectRef{Group: "test.infrared.reddit.com", Version: "v1alpha1", Kind: "TestClaim", Name: "parent", Namespace: "default"}
+	other := api.TypedObjectRef{Group: "test.infrared.reddit.com", Version: "v1alpha1", Kind: "TestClaim", Name: "other", Namespace: "default"}
+	ref := api.TypedObjectRef{Version: "v1", Kind: "ConfigMap", Name: "missing", Namespace: "default"}
+
+	for _, p := range []api.TypedObjectRef{parent, other} {
+		prune, remaining := tracker.shouldPrune(p, ref)
+		assert.False(t, prune)
+		assert.Equal(t, time.Minute, remaining)
+	}
+
+	// forgetting a deleted parent stops tracking all of its refs
+	tracker.ForgetParent(parent)
+	assert.NotContains(t, tracker.firstMissing, parent.String())
+	assert.Contains(t, tracker.firstMissing, other.String())
+}
+
+func Test_ApplyOutputSet_WithoutDefaultControllerRef(t *testing.T) {
+	log := zaptest.NewLogger(t).Sugar()
+	scheme := intscheme.MustNewScheme()
+
+	tcs := []struct {
+		name string
+		opts []ApplyOutputSetOption
+		// applyOpts are the apply options of the output
+		applyOpts          []io.ApplyOption
+		expectedOwnerRef   bool
+		expectedController bool
+	}{
+		{
+			name:               "default controller ref",
+			expectedOwnerRef:   true,
+			expectedController: true,
+		},
+		{
+			name: "without default controller ref",
+			opts: []ApplyOutputSetOption{WithoutDefaultControllerRef()},
+		},
+		{
+			name:               "without default controller ref with explicit owner ref",
+			opts:               []ApplyOutputSetOption{WithoutDefaultControllerRef()},
+			applyOpts:          []io.ApplyOption{io.WithOwnerRef(&v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "parent-uid"}}, scheme)},
+			expectedOwnerRef:   true,
+			expectedController: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			parent := &v1alpha1.TestClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "parent-uid"},
+			}
+			fakeC := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(parent).
+				WithStatusSubresource(parent).
+				Build()
+			c := &io.ClientApplicator{
+				Client:     fakeC,
+				Applicator: io.NewAPIPatchingApplicator(fakeC),
+			}
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+			out := types.NewOutputSet(scheme)
+			out.Apply(cm.DeepCopy(), tc.applyOpts...)
+
+			obj := &v1alpha1.TestClaim{}
+			assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(parent), obj))
+			assert.NoError(t, ApplyOutputSet(ctx, log, c, scheme, obj, out, tc.opts...))
+
+			actual := &corev1.ConfigMap{}
+			assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cm), actual))
+			if !tc.expectedOwnerRef {
+				assert.Empty(t, actual.OwnerReferences)
+				return
+			}
+			if assert.Len(t, actual.OwnerReferences, 1) {
+				assert.Equal(t, "parent", actual.OwnerReferences[0].Name)
+				assert.Equal(t, tc.expectedController, ptr.Deref(actual.OwnerReferences[0].Controller, false))
+			}
+		})
+	}
+}
+
+func Test_ApplyOutputSet_Prune(t *testing.T) {
+	log := zaptest.NewLogger(t).Sugar()
+	scheme := intscheme.MustNewScheme()
+	ctx := context.Background()
+
+	parent := &v1alpha1.TestClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "parent-uid"},
+	}
+	controllerRef := *metav1.NewControllerRef(parent, v1alpha1.TestClaimGroupVersionKind)
+	configMap := func(name string, ownerRefs ...metav1.OwnerReference) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: ownerRefs}}
+	}
+
+	kept := configMap("kept", controllerRef)
+	stale := configMap("stale", controllerRef)
+	applied := configMap("applied", controllerRef)
+	unowned := configMap("unowned")
+	parent.Status.ResourceRefs = []api.TypedObjectRef{
+		*meta.MustTypedObjectRefFromObject(kept, scheme),
+		*meta.MustTypedObjectRefFromObject(stale, scheme),
+	}
+
+	fakeC := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(parent, kept, stale, applied, unowned).
+		WithStatusSubresource(parent).
+		Build()
+	c := &io.ClientApplicator{
+		Client:     fakeC,
+		Applicator: io.NewAPIPatchingApplicator(fakeC),
+	}
+
+	out := types.NewOutputSet(scheme)
+	out.Apply(configMap("applied"))
+	out.Prune(corev1.SchemeGroupVersion.WithKind("ConfigMap"), func(o client.Object) bool {
+		return o.GetName() == "kept"
+	})
+
+	obj := &v1alpha1.TestClaim{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(parent), obj))
+	assert.NoError(t, ApplyOutputSet(ctx, log, c, scheme, obj, out))
+
+	// owned objects that aren't kept are deleted
+	err := c.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.ConfigMap{})
+	assert.True(t, k8serrors.IsNotFound(err), "expected stale config map to be pruned, got %v", err)
+
+	// kept, applied, and unowned objects survive
+	for _, cm := range []*corev1.ConfigMap{kept, applied, unowned} {
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}), "expected %s to survive", cm.Name)
+	}
+
+	// refs of pruned objects are removed
+	actual := &v1alpha1.TestClaim{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(parent), actual))
+	assert.ElementsMatch(t, []api.TypedObjectRef{
+		*meta.MustTypedObjectRefFromObject(kept, scheme),
+		*meta.MustTypedObjectRefFromObject(applied, scheme),
+	}, actual.GetManagedResources())
+}
@@ -0,0 +1,88 @@
+package io
+
+import (
+	"sync"
+	"time"
+
+	"github.com/reddit/achilles-sdk-api/api"
+)
+
+// MissingRefTracker delays pruning of managed resource refs whose objects are not found, tracking when each ref was first
+// observed missing. This prevents refs from being dropped for objects that are only temporarily absent (e.g. due to
+// eventual consistency of a cache). Refs for objects explicitly deleted by the controller are always pruned immediately.
+//
+// NOTE: tracking is in-memory, so the grace period restarts when the controller restarts.
+type MissingRefTracker struct {
+	gracePeriod time.Duration
+
+	mu sync.Mutex
+	// a map of parent key to managed resource key to the time the managed resource was first observed missing
+	firstMissing map[string]map[string]time.Time
+
+	// now returns the current time, overridden in tests
+	now func() time.Time
+}
+
+// NewMissingRefTracker returns a MissingRefTracker that prunes refs once their objects have been missing for the grace period.
+func NewMissingRefTracker(gracePeriod time.Duration) *MissingRefTracker {
+	return &MissingRefTracker{
+		gracePeriod:  gracePeriod,
+		firstMissing: map[string]map[string]time.Time{},
+		now:          time.Now,
+	}
+}
+
+// shouldPrune records that the ref's object is missing and returns true if it has been missing for longer than the grace period,
+// otherwise returns the remaining grace period.
+func (t *MissingRefTracker) shouldPrune(parent, ref api.TypedObjectRef) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parentKey, refKey := parent.String(), ref.String()
+	now := t.now()
+
+	refs, ok := t.firstMissing[parentKey]
+	if !ok {
+		refs = map[string]time.Time{}
+		t.firstMissing[parentKey] = refs
+	}
+	first, ok := refs[refKey]
+	if !ok {
+		refs[refKey] = now
+		first = now
+	}
+
+	if remaining := t.gracePeriod - now.Sub(first); remaining > 0 {
+		return false, remaining
+	}
+	t.delete(parentKey, refKey)
+	return true, 0
+}
+
+// forget stops tracking the ref, called when its object is found or explicitly deleted.
+func (t *MissingRefTracker) forget(parent, ref api.TypedObjectRef) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.delete(parent.String(), ref.String())
+}
+
+// ForgetParent stops tracking all refs of the parent, called when the parent is deleted.
+func (t *MissingRefTracker) ForgetParent(parent api.TypedObjectRef) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.firstMissing, parent.String())
+}
+
+// delete stops tracking the ref, deleting the parent's entry once it has no tracked refs. Must be called with the lock held.
+func (t *MissingRefTracker) delete(parentKey, refKey string) {
+	refs, ok := t.firstMissing[parentKey]
+	if !ok {
+		return
+	}
+	delete(refs, refKey)
+	if len(refs) == 0 {
+		delete(t.firstMissing, parentKey)
+	}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// conditionAgeCollector reports the time elapsed since the last transition of status conditions.
+// Ages are computed upon collection from the recorded transition times, so that they keep increasing between reconciles.
+type conditionAgeCollector struct {
+	desc *prometheus.Desc
+	now  func() time.Time
+
+	mu sync.Mutex
+	// transitions are keyed by condition labels without the status, since a condition has a single status at a time
+	transitions map[conditionGaugeLabel]conditionTransition
+}
+
+type conditionTransition struct {
+	status string
+	time   time.Time
+}
+
+func newConditionAgeCollector() *conditionAgeCollector {
+	return &conditionAgeCollector{
+		desc: prometheus.NewDesc(
+			"achilles_condition_age_seconds",
+			"Time elapsed since the last transition of the status condition of an Achilles resource.",
+			conditionGaugeLabel{}.names(),
+			nil,
+		),
+		now:         time.Now,
+		transitions: map[conditionGaugeLabel]conditionTransition{},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *conditionAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *conditionAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for label, transition := range c.transitions {
+		label.status = transition.status
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(transition.time).Seconds(), label.values()...)
+	}
+}
+
+// record records the transition time of the condition identified by label, replacing that of any other status.
+func (c *conditionAgeCollector) record(label conditionGaugeLabel, transitioned time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := label.status
+	label.status = ""
+	c.transitions[label] = conditionTransition{status: status, time: transitioned}
+}
+
+// delete deletes the transition time of the condition identified by label, regardless of its status.
+func (c *conditionAgeCollector) delete(label conditionGaugeLabel) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	label.status = ""
+	_, ok := c.transitions[label]
+	delete(c.transitions, label)
+	return ok
+}
+
+func (c *conditionAgeCollector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.transitions = map[conditionGaugeLabel]conditionTransition{}
+}
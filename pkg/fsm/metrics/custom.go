@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomMetrics is a registry of user-defined metrics recorded from within FSM transitions.
+// Metrics are registered lazily upon first use, and labeled with the group, version, and kind of the reconciled object
+// in addition to any labels supplied by the caller.
+type CustomMetrics struct {
+	registerer prometheus.Registerer
+
+	mu         sync.Mutex
+	gauges     map[string]*prometheus.GaugeVec
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// NewCustomMetrics returns a CustomMetrics that registers metrics with the given registerer.
+// The registerer may be shared by multiple controllers, in which case metrics of the same name are shared.
+func NewCustomMetrics(registerer prometheus.Registerer) *CustomMetrics {
+	return &CustomMetrics{
+		registerer: registerer,
+		gauges:     map[string]*prometheus.GaugeVec{},
+		counters:   map[string]*prometheus.CounterVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+	}
+}
+
+// ForGVK returns a CustomMetricsRecorder that labels metrics with the given GVK of the reconciled object.
+func (m *CustomMetrics) ForGVK(gvk schema.GroupVersionKind) *CustomMetricsRecorder {
+	return &CustomMetricsRecorder{metrics: m, gvk: gvk}
+}
+
+func (m *CustomMetrics) gauge(name, help string, labelNames []string) (*prometheus.GaugeVec, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v, ok := m.gauges[name]; ok {
+		return v, nil
+	}
+	v, err := register(m.registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames))
+	if err != nil {
+		return nil, err
+	}
+	m.gauges[name] = v
+	return v, nil
+}
+
+func (m *CustomMetrics) counter(name, help string, labelNames []string) (*prometheus.CounterVec, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v, ok := m.counters[name]; ok {
+		return v, nil
+	}
+	v, err := register(m.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames))
+	if err != nil {
+		return nil, err
+	}
+	m.counters[name] = v
+	return v, nil
+}
+
+func (m *CustomMetrics) histogram(name, help string, labelNames []string) (*prometheus.HistogramVec, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v, ok := m.histograms[name]; ok {
+		return v, nil
+	}
+	v, err := register(m.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help}, labelNames))
+	if err != nil {
+		return nil, err
+	}
+	m.histograms[name] = v
+	return v, nil
+}
+
+// register registers the collector, returning the existing collector if an equivalent one is already registered
+// (e.g. by another controller sharing the registerer).
+func register[C prometheus.Collector](registerer prometheus.Registerer, c C) (C, error) {
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		var zero C
+		return zero, fmt.Errorf("registering custom metric: %w", err)
+	}
+	return c, nil
+}
+
+// CustomMetricsRecorder records user-defined metrics for a reconciled object type.
+// A nil CustomMetricsRecorder is valid, and records nothing.
+type CustomMetricsRecorder struct {
+	metrics *CustomMetrics
+	gvk     schema.GroupVersionKind
+}
+
+// SetGauge sets the value of the gauge with the given name and labels.
+func (r *CustomMetricsRecorder) SetGauge(name, help string, value float64, labels prometheus.Labels) error {
+	if r == nil {
+		return nil
+	}
+	labels, labelNames := r.labels(labels)
+	v, err := r.metrics.gauge(name, help, labelNames)
+	if err != nil {
+		return err
+	}
+	g, err := v.GetMetricWith(labels)
+	if err != nil {
+		return fmt.Errorf("getting gauge %q: %w", name, err)
+	}
+	g.Set(value)
+	return nil
+}
+
+// AddCounter adds the value, which must be non-negative, to the counter with the given name and labels.
+func (r *CustomMetricsRecorder) AddCounter(name, help string, value float64, labels prometheus.Labels) error {
+	if r == nil {
+		return nil
+	}
+	if value < 0 {
+		return fmt.Errorf("adding negative value %v to counter %q", value, name)
+	}
+	labels, labelNames := r.labels(labels)
+	v, err := r.metrics.counter(name, help, labelNames)
+	if err != nil {
+		return err
+	}
+	c, err := v.GetMetricWith(labels)
+	if err != nil {
+		return fmt.Errorf("getting counter %q: %w", name, err)
+	}
+	c.Add(value)
+	return nil
+}
+
+// ObserveHistogram adds an observation to the histogram with the given name and labels, using the default buckets.
+func (r *CustomMetricsRecorder) ObserveHistogram(name, help string, value float64, labels prometheus.Labels) error {
+	if r == nil {
+		return nil
+	}
+	labels, labelNames := r.labels(labels)
+	v, err := r.metrics.histogram(name, help, labelNames)
+	if err != nil {
+		return err
+	}
+	h, err := v.GetMetricWith(labels)
+	if err != nil {
+		return fmt.Errorf("getting histogram %q: %w", name, err)
+	}
+	h.Observe(value)
+	return nil
+}
+
+// labels returns the caller's labels merged with the GVK labels, along with the sorted label names.
+// GVK labels take precedence over caller labels of the same name.
+func (r *CustomMetricsRecorder) labels(labels prometheus.Labels) (prometheus.Labels, []string) {
+	merged := make(prometheus.Labels, len(labels)+3)
+	for k, v := range labels {
+		merged[k] = v
+	}
+	merged["group"] = r.gvk.Group
+	merged["version"] = r.gvk.Version
+	merged["kind"] = r.gvk.Kind
+
+	names := make([]string, 0, len(merged))
+	for k := range merged {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return merged, names
+}
+
+type customMetricsContextKey struct{}
+
+// NewCustomMetricsContext returns a new Context, derived from ctx, which carries the provided CustomMetricsRecorder.
+func NewCustomMetricsContext(ctx context.Context, recorder *CustomMetricsRecorder) context.Context {
+	return context.WithValue(ctx, customMetricsContextKey{}, recorder)
+}
+
+// CustomMetricsFromContext returns the CustomMetricsRecorder carried by ctx. Transitions use this to record
+// user-defined metrics. If the reconciler isn't configured with a custom metrics registerer, the returned recorder
+// is nil, which records nothing.
+func CustomMetricsFromContext(ctx context.Context) *CustomMetricsRecorder {
+	recorder, _ := ctx.Value(customMetricsContextKey{}).(*CustomMetricsRecorder)
+	return recorder
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// delayedDeletions defers deletions of metrics by a grace period, so that metrics of objects that are recreated shortly
+// after deletion are retained rather than dropped and reset, which shows up as gaps in dashboards.
+// A deletion is cancelled if its metric is recorded again within the grace period.
+//
+// NOTE: pending deletions are in-memory, so metrics pending deletion are dropped anyway when the process restarts.
+type delayedDeletions struct {
+	gracePeriod time.Duration
+	clock       clock.WithDelayedExecution
+
+	mu sync.Mutex
+	// a map of deletion key to the timer of the pending deletion
+	pending map[string]clock.Timer
+}
+
+// newDelayedDeletions returns a delayedDeletions, or nil if metrics are deleted immediately.
+func newDelayedDeletions(gracePeriod time.Duration) *delayedDeletions {
+	if gracePeriod <= 0 {
+		return nil
+	}
+	return &delayedDeletions{
+		gracePeriod: gracePeriod,
+		clock:       clock.RealClock{},
+		pending:     map[string]clock.Timer{},
+	}
+}
+
+// schedule runs deleteFunc once the grace period elapses, unless cancelled. Replaces any pending deletion with the same key.
+// If d is nil, deleteFunc is run immediately.
+func (d *delayedDeletions) schedule(key string, deleteFunc func()) {
+	if d == nil {
+		deleteFunc()
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if timer, ok := d.pending[key]; ok {
+		timer.Stop()
+	}
+
+	var timer clock.Timer
+	timer = d.clock.AfterFunc(d.gracePeriod, func() {
+		d.mu.Lock()
+		// the deletion may have been cancelled or replaced after the timer fired
+		current, ok := d.pending[key]
+		if !ok || current != timer {
+			d.mu.Unlock()
+			return
+		}
+		delete(d.pending, key)
+		d.mu.Unlock()
+
+		deleteFunc()
+	})
+	d.pending[key] = timer
+}
+
+// cancel cancels the pending deletion with the given key, if any.
+func (d *delayedDeletions) cancel(key string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if timer, ok := d.pending[key]; ok {
+		timer.Stop()
+		delete(d.pending, key)
+	}
+}
+
+// reset cancels all pending deletions.
+func (d *delayedDeletions) reset() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, timer := range d.pending {
+		timer.Stop()
+		delete(d.pending, key)
+	}
+}
+
+// deletionKey returns the key identifying deletions of the given metric for the given object.
+func deletionKey(metric string, gvk schema.GroupVersionKind, key client.ObjectKey) string {
+	return fmt.Sprintf("%s:%s:%s", metric, gvk, key)
+}
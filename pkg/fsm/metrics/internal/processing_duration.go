@@ -157,3 +157,11 @@ func (p *ProcessingStartTimes) SetRangeFailed(name string, namespace string, obs
 		return true
 	})
 }
+
+// Len returns the number of processing start times tracked.
+func (p *ProcessingStartTimes) Len() int {
+	p.m.RLock()
+	defer p.m.RUnlock()
+
+	return p.startTimes.Len()
+}
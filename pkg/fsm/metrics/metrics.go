@@ -1,7 +1,9 @@
 package metrics
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -31,8 +33,12 @@ type processingStartTimes interface {
 	SetRangeFailed(name string, namespace string, observedGeneration int64)
 	// DeleteRange deletes all processing start times for the given (name, namespace) where generation <= observedGeneration.
 	DeleteRange(name string, namespace string, observedGeneration int64)
+	// Len returns the number of processing start times tracked.
+	Len() int
 }
 
+var _ types.PendingChildDeletionsRecorder = &Metrics{}
+
 type Metrics struct {
 	scheme  *runtime.Scheme
 	sink    *Sink
@@ -40,6 +46,21 @@ type Metrics struct {
 
 	// a map of GVK to processingStartTimes
 	processingStartTimesByGVK map[schema.GroupVersionKind]processingStartTimes
+
+	// pendingChildDeletions tracks the number of child resources pending deletion per parent and child GVK,
+	// so that the gauge reports the total across all parents being deleted concurrently.
+	pendingChildDeletions *pendingChildDeletions
+
+	// deletions defers deletions of per-object metrics by MetricsOptions.DeletionGracePeriod, nil if metrics are deleted immediately
+	deletions *delayedDeletions
+}
+
+type pendingChildDeletions struct {
+	mu sync.Mutex
+	// a map of parent key to child GVK to number of pending child deletions
+	byParent map[string]map[schema.GroupVersionKind]int
+	// a map of child GVK to number of pending child deletions across all parents
+	totals map[schema.GroupVersionKind]int
 }
 
 // MustMakeMetrics creates a new Metrics with a new metrics Sink, and the Metrics.Scheme set to that of the given manager.
@@ -51,6 +72,7 @@ func MustMakeMetrics(scheme *runtime.Scheme, registrar prometheus.Registerer) *M
 		scheme:                    scheme,
 		sink:                      metricsRecorder,
 		processingStartTimesByGVK: make(map[schema.GroupVersionKind]processingStartTimes),
+		pendingChildDeletions:     newPendingChildDeletions(),
 	}
 }
 
@@ -64,6 +86,8 @@ func MustMakeMetricsWithOptions(scheme *runtime.Scheme, registrar prometheus.Reg
 		sink:                      metricsRecorder,
 		options:                   options,
 		processingStartTimesByGVK: make(map[schema.GroupVersionKind]processingStartTimes),
+		pendingChildDeletions:     newPendingChildDeletions(),
+		deletions:                 newDelayedDeletions(options.DeletionGracePeriod),
 	}
 }
 
@@ -78,6 +102,7 @@ func (m *Metrics) InitializeForGVK(gvk schema.GroupVersionKind) {
 
 // Reset resets all metrics.
 func (m *Metrics) Reset() {
+	m.deletions.reset()
 	m.sink.Reset()
 }
 
@@ -93,6 +118,7 @@ func (m *Metrics) RecordTrigger(
 		return
 	}
 
+	m.deletions.cancel(triggerDeletionKey(requestObjKey, controllerName))
 	m.sink.RecordTrigger(triggerGVK, requestObjKey, event, triggerType, controllerName)
 }
 
@@ -105,20 +131,37 @@ func (m *Metrics) DeleteTrigger(
 		return
 	}
 
-	m.sink.DeleteTrigger(requestObjKey, controllerName)
+	m.deletions.schedule(triggerDeletionKey(requestObjKey, controllerName), func() {
+		m.sink.DeleteTrigger(requestObjKey, controllerName)
+	})
+}
+
+// triggerDeletionKey returns the deletion key of the trigger metric of the given triggered object and controller name
+func triggerDeletionKey(requestObjKey client.ObjectKey, controllerName string) string {
+	return deletionKey(string(types.AchillesResourceTrigger)+"/"+controllerName, schema.GroupVersionKind{}, requestObjKey)
 }
 
 // RecordReadiness records the meta.ReadyCondition status for the given obj.
 func (m *Metrics) RecordReadiness(obj conditionedObject) {
+	m.RecordReadinessForType(obj, api.TypeReady)
+}
+
+// RecordReadinessForType records the status of the given top-level readiness conditionType for the given obj.
+func (m *Metrics) RecordReadinessForType(obj conditionedObject, conditionType api.ConditionType) {
 	if m.options.IsMetricDisabled(types.AchillesResourceReadiness) {
 		return
 	}
-	m.RecordCondition(obj, api.TypeReady)
+	m.RecordCondition(obj, conditionType)
 }
 
 // DeleteReadiness deletes the meta.ReadyCondition status metric for the given obj.
 func (m *Metrics) DeleteReadiness(obj conditionedObject) {
-	m.DeleteCondition(obj, api.TypeReady)
+	m.DeleteReadinessForType(obj, api.TypeReady)
+}
+
+// DeleteReadinessForType deletes the status metric of the given top-level readiness conditionType for the given obj.
+func (m *Metrics) DeleteReadinessForType(obj conditionedObject, conditionType api.ConditionType) {
+	m.DeleteCondition(obj, conditionType)
 }
 
 // RecordCondition records the status of the given conditionType for the given obj.
@@ -130,6 +173,7 @@ func (m *Metrics) RecordCondition(obj conditionedObject, conditionType api.Condi
 	condition := obj.GetCondition(conditionType)
 	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
 
+	m.deletions.cancel(deletionKey(string(types.AchillesResourceCondition)+"/"+string(conditionType), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()))
 	m.sink.RecordCondition(
 		typedObjectRef.ObjectKey(),
 		typedObjectRef.GroupVersionKind(),
@@ -138,6 +182,37 @@ func (m *Metrics) RecordCondition(obj conditionedObject, conditionType api.Condi
 	)
 }
 
+// RecordConditionAge records the age, i.e. the time elapsed since the last transition, of the given conditionType for the given obj.
+// The age keeps increasing until the condition transitions again. If the condition is absent, or its last transition time
+// isn't set, the metric is deleted.
+func (m *Metrics) RecordConditionAge(obj conditionedObject, conditionType api.ConditionType) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesConditionAge) {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.cancel(deletionKey(string(types.AchillesConditionAge)+"/"+string(conditionType), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()))
+	for _, condition := range obj.GetConditions() {
+		if condition.Type == conditionType && !condition.LastTransitionTime.IsZero() {
+			m.sink.RecordConditionAge(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), condition)
+			return
+		}
+	}
+	m.sink.DeleteConditionAge(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), conditionType)
+}
+
+// DeleteConditionAge deletes the age of the given conditionType for the given obj.
+func (m *Metrics) DeleteConditionAge(obj client.Object, conditionType api.ConditionType) {
+	if m.sink == nil {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.schedule(deletionKey(string(types.AchillesConditionAge)+"/"+string(conditionType), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()), func() {
+		m.sink.DeleteConditionAge(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), conditionType)
+	})
+}
+
 // DeleteCondition deletes the status of the given conditionType for the given obj.
 func (m *Metrics) DeleteCondition(obj conditionedObject, conditionType api.ConditionType) {
 	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesResourceCondition) {
@@ -147,11 +222,13 @@ func (m *Metrics) DeleteCondition(obj conditionedObject, conditionType api.Condi
 	condition := obj.GetCondition(conditionType)
 	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
 
-	m.sink.DeleteCondition(
-		typedObjectRef.ObjectKey(),
-		typedObjectRef.GroupVersionKind(),
-		condition,
-	)
+	m.deletions.schedule(deletionKey(string(types.AchillesResourceCondition)+"/"+string(conditionType), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()), func() {
+		m.sink.DeleteCondition(
+			typedObjectRef.ObjectKey(),
+			typedObjectRef.GroupVersionKind(),
+			condition,
+		)
+	})
 }
 
 // RecordStateDuration records the duration of the state for the given GVK.
@@ -173,6 +250,76 @@ func (m *Metrics) RecordSuspend(obj client.Object, suspend bool) {
 	m.sink.RecordSuspend(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), suspend)
 }
 
+// RecordSuspectedReconcileLoop records status of the object to be 1 if suspected to be in a reconcile loop and 0 otherwise.
+func (m *Metrics) RecordSuspectedReconcileLoop(obj client.Object, suspected bool) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesSuspectedReconcileLoop) {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.cancel(deletionKey(string(types.AchillesSuspectedReconcileLoop), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()))
+	m.sink.RecordSuspectedReconcileLoop(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), suspected)
+}
+
+// DeleteSuspectedReconcileLoop deletes the suspected reconcile loop metric for the given obj.
+func (m *Metrics) DeleteSuspectedReconcileLoop(obj client.Object) {
+	if m.sink == nil {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.schedule(deletionKey(string(types.AchillesSuspectedReconcileLoop), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()), func() {
+		m.sink.DeleteSuspectedReconcileLoop(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind())
+	})
+}
+
+// RecordManagedResources records the number of resources managed by the given obj.
+func (m *Metrics) RecordManagedResources(obj client.Object, count int) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesManagedResources) {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.cancel(deletionKey(string(types.AchillesManagedResources), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()))
+	m.sink.RecordManagedResources(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), count)
+}
+
+// DeleteManagedResources deletes the managed resources metric for the given obj.
+func (m *Metrics) DeleteManagedResources(obj client.Object) {
+	if m.sink == nil {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.schedule(deletionKey(string(types.AchillesManagedResources), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()), func() {
+		m.sink.DeleteManagedResources(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind())
+	})
+}
+
+// RecordStatusSizeExceeded records status of the object's status field (e.g. "conditions") to be 1 if it exceeds its
+// size threshold and 0 otherwise.
+func (m *Metrics) RecordStatusSizeExceeded(obj client.Object, field string, exceeded bool) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesStatusSizeExceeded) {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.cancel(deletionKey(string(types.AchillesStatusSizeExceeded), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()))
+	m.sink.RecordStatusSizeExceeded(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind(), field, exceeded)
+}
+
+// DeleteStatusSizeExceeded deletes the status size metrics of all status fields of the given obj.
+func (m *Metrics) DeleteStatusSizeExceeded(obj client.Object) {
+	if m.sink == nil {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
+	m.deletions.schedule(deletionKey(string(types.AchillesStatusSizeExceeded), typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey()), func() {
+		m.sink.DeleteStatusSizeExceeded(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind())
+	})
+}
+
 // RecordProcessingStart records the start time of processing for the given GVK and request.
 // This doesn't record a metric, but the start time is used to calculate the processing duration later.
 func (m *Metrics) RecordProcessingStart(
@@ -192,10 +339,21 @@ func (m *Metrics) RecordProcessingStart(
 	}
 
 	processingStartTimes.Set(req.Name, req.Namespace, gen, time.Now())
+	m.recordProcessingStartTimesSize(gvk, processingStartTimes)
 
 	return nil
 }
 
+// recordProcessingStartTimesSize records the number of tracked processing start times, surfacing unbounded growth
+// for objects that never process successfully.
+func (m *Metrics) recordProcessingStartTimesSize(gvk schema.GroupVersionKind, processingStartTimes processingStartTimes) {
+	if m.options.IsMetricDisabled(types.AchillesProcessingStartTimesSize) {
+		return
+	}
+
+	m.sink.RecordProcessingStartTimesSize(gvk, processingStartTimes.Len())
+}
+
 // RecordProcessingDuration records the time taken to process an object of a given metadata.generation.
 func (m *Metrics) RecordProcessingDuration(
 	gvk schema.GroupVersionKind,
@@ -229,6 +387,7 @@ func (m *Metrics) RecordProcessingDuration(
 		// if the processing failed, mark all items of (name, namespace) with generation < observedGeneration as failed to avoid subsequent double counting
 		processingStartTimes.SetRangeFailed(req.Name, req.Namespace, gen)
 	}
+	m.recordProcessingStartTimesSize(gvk, processingStartTimes)
 
 	return nil
 }
@@ -246,6 +405,7 @@ func (m *Metrics) RecordEvent(
 		return
 	}
 
+	m.deletions.cancel(eventDeletionKey(client.ObjectKey{Name: objectName, Namespace: objectNamespace}))
 	m.sink.RecordEvent(triggerGVK, objectName, objectNamespace, eventType, reason, controllerName)
 }
 
@@ -258,5 +418,137 @@ func (m *Metrics) DeleteEvent(
 	}
 
 	typedObjectRef := meta.MustTypedObjectRefFromObject(obj, m.scheme)
-	m.sink.DeleteEvent(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind())
+	m.deletions.schedule(eventDeletionKey(typedObjectRef.ObjectKey()), func() {
+		m.sink.DeleteEvent(typedObjectRef.ObjectKey(), typedObjectRef.GroupVersionKind())
+	})
+}
+
+// eventDeletionKey returns the deletion key of the event metrics of the given object. The key omits the object's GVK,
+// since events may be recorded for objects without populated type metadata.
+func eventDeletionKey(key client.ObjectKey) string {
+	return deletionKey("Event", schema.GroupVersionKind{}, key)
+}
+
+// RecordReconcileResult records the outcome of a reconcile for the given controller, classified from the values returned by the reconciler
+// as one of ReconcileResultDone, ReconcileResultRequeue, ReconcileResultError, or ReconcileResultTerminal.
+func (m *Metrics) RecordReconcileResult(controllerName string, res reconcile.Result, err error) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesReconcileResult) {
+		return
+	}
+
+	var result string
+	switch {
+	case errors.Is(err, reconcile.TerminalError(nil)):
+		result = ReconcileResultTerminal
+	case err != nil:
+		result = ReconcileResultError
+	case !res.IsZero():
+		result = ReconcileResultRequeue
+	default:
+		result = ReconcileResultDone
+	}
+
+	m.sink.RecordReconcileResult(controllerName, result)
+}
+
+// RecordActiveReconcileDuration records the time spent reconciling for the given controller, excluding time spent waiting
+// in the queue between requeues.
+func (m *Metrics) RecordActiveReconcileDuration(controllerName string, duration time.Duration) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesActiveReconcileDuration) {
+		return
+	}
+
+	m.sink.RecordActiveReconcileDuration(controllerName, duration)
+}
+
+// RecordTriggerCoalesced records a trigger for the given controller enqueueing a request already waiting in the workqueue.
+func (m *Metrics) RecordTriggerCoalesced(controllerName string) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesTriggerCoalesced) {
+		return
+	}
+
+	m.sink.RecordTriggerCoalesced(controllerName)
+}
+
+// RecordReconcileSkipped records a reconcile of the given controller skipped by a state transition with the given reason.
+func (m *Metrics) RecordReconcileSkipped(controllerName string, reason string) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesReconcileSkipped) {
+		return
+	}
+
+	m.sink.RecordReconcileSkipped(controllerName, reason)
+}
+
+// RecordControllerPaused records whether reconciliation is paused for the given controller.
+func (m *Metrics) RecordControllerPaused(controllerName string, paused bool) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesControllerPaused) {
+		return
+	}
+
+	m.sink.RecordControllerPaused(controllerName, paused)
+}
+
+// RecordManagedTypeUnservable records whether the given type managed by the controller is no longer served by the kube-apiserver.
+func (m *Metrics) RecordManagedTypeUnservable(controllerName string, gvk schema.GroupVersionKind, unservable bool) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesManagedTypeUnservable) {
+		return
+	}
+
+	m.sink.RecordManagedTypeUnservable(controllerName, gvk, unservable)
+}
+
+// RecordPendingChildDeletions records the number of child resources, by type, pending deletion for the given parent.
+// The metric reports the total across all parents, and is deleted for a given type once no children of that type are pending deletion.
+func (m *Metrics) RecordPendingChildDeletions(parent client.Object, pending map[schema.GroupVersionKind]int) {
+	if m.sink == nil || m.options.IsMetricDisabled(types.AchillesPendingChildDeletions) {
+		return
+	}
+
+	typedObjectRef := meta.MustTypedObjectRefFromObject(parent, m.scheme)
+	key := keyFunc(typedObjectRef.GroupVersionKind(), typedObjectRef.ObjectKey())
+
+	p := m.pendingChildDeletions
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous := p.byParent[key]
+
+	// update totals for all child types that were or are pending deletion
+	changed := map[schema.GroupVersionKind]struct{}{}
+	for gvk := range previous {
+		changed[gvk] = struct{}{}
+	}
+	for gvk := range pending {
+		changed[gvk] = struct{}{}
+	}
+	for gvk := range changed {
+		p.totals[gvk] += pending[gvk] - previous[gvk]
+		if p.totals[gvk] <= 0 {
+			delete(p.totals, gvk)
+			m.sink.DeletePendingChildDeletions(gvk)
+		} else {
+			m.sink.RecordPendingChildDeletions(gvk, p.totals[gvk])
+		}
+	}
+
+	if len(pending) == 0 {
+		delete(p.byParent, key)
+	} else {
+		current := make(map[schema.GroupVersionKind]int, len(pending))
+		for gvk, count := range pending {
+			current[gvk] = count
+		}
+		p.byParent[key] = current
+	}
+}
+
+func newPendingChildDeletions() *pendingChildDeletions {
+	return &pendingChildDeletions{
+		byParent: make(map[string]map[schema.GroupVersionKind]int),
+		totals:   make(map[schema.GroupVersionKind]int),
+	}
+}
+
+func keyFunc(gvk schema.GroupVersionKind, key client.ObjectKey) string {
+	return fmt.Sprintf("%s:%s", gvk, key)
 }
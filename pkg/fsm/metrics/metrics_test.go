@@ -1,7 +1,9 @@
 package metrics
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,9 +16,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ktypes "k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/reddit/achilles-sdk-api/api"
 	"github.com/reddit/achilles-sdk/pkg/fsm/types"
 	testv1alpha1 "github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
 	"github.com/reddit/achilles-sdk/pkg/meta"
@@ -489,6 +493,143 @@ func Test_RecordProcessingDuration(t *testing.T) {
 	}
 }
 
+func TestRecordProcessingStartTimesSize(t *testing.T) {
+	metrics := MustMakeMetrics(scheme, prometheus.NewRegistry())
+	metricsDisabled := MustMakeMetricsWithOptions(scheme, prometheus.NewRegistry(), types.MetricsOptions{DisableMetrics: []types.AchillesMetrics{types.AchillesProcessingStartTimesSize}})
+
+	testClaimGVK := meta.MustTypedObjectRefFromObject(&testv1alpha1.TestClaim{}, scheme).GroupVersionKind()
+	metrics.InitializeForGVK(testClaimGVK)
+	metricsDisabled.InitializeForGVK(testClaimGVK)
+
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: ktypes.NamespacedName{Name: name, Namespace: "default"}}
+	}
+	gauge := func(m *Metrics) float64 {
+		return testutil.ToFloat64(m.sink.processingStartTimesGauge.WithLabelValues(testClaimGVK.Group, testClaimGVK.Version, testClaimGVK.Kind))
+	}
+
+	// start processing two generations of claim-1 and one of claim-2
+	assert.NoError(t, metrics.RecordProcessingStart(testClaimGVK, req("claim-1"), 1))
+	assert.NoError(t, metrics.RecordProcessingStart(testClaimGVK, req("claim-1"), 2))
+	assert.NoError(t, metrics.RecordProcessingStart(testClaimGVK, req("claim-2"), 1))
+	assert.Equal(t, float64(3), gauge(metrics))
+
+	// failed processing retains start times
+	assert.NoError(t, metrics.RecordProcessingDuration(testClaimGVK, req("claim-2"), 1, false))
+	assert.Equal(t, float64(3), gauge(metrics))
+
+	// successful processing deletes all start times up to the processed generation
+	assert.NoError(t, metrics.RecordProcessingDuration(testClaimGVK, req("claim-1"), 2, true))
+	assert.Equal(t, float64(1), gauge(metrics))
+
+	assert.NoError(t, metrics.RecordProcessingDuration(testClaimGVK, req("claim-2"), 1, true))
+	assert.Equal(t, float64(0), gauge(metrics))
+
+	// disabled metric
+	assert.NoError(t, metricsDisabled.RecordProcessingStart(testClaimGVK, req("claim-1"), 1))
+	assert.Equal(t, 0, testutil.CollectAndCount(metricsDisabled.sink.processingStartTimesGauge, "achilles_processing_start_times_size"))
+}
+
+func TestRecordConditionAge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := MustMakeMetrics(scheme, reg)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	metrics.sink.conditionAge.now = func() time.Time { return now }
+
+	obj := &testv1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "test-claim", Namespace: "default"}}
+
+	// absent conditions aren't reported
+	metrics.RecordConditionAge(obj, api.TypeReady)
+	count, err := testutil.GatherAndCount(reg, "achilles_condition_age_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	obj.SetConditions(api.Condition{
+		Type:               api.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+	})
+	metrics.RecordConditionAge(obj, api.TypeReady)
+
+	expected := `
+# HELP achilles_condition_age_seconds Time elapsed since the last transition of the status condition of an Achilles resource.
+# TYPE achilles_condition_age_seconds gauge
+achilles_condition_age_seconds{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",status="False",type="Ready",version="v1alpha1"} 3600
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_condition_age_seconds"))
+
+	// the age increases between recordings
+	now = now.Add(time.Minute)
+	expected = strings.Replace(expected, "3600", "3660", 1)
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_condition_age_seconds"))
+
+	// a transition replaces the series of the previous status
+	obj.SetConditions(api.Condition{
+		Type:               api.TypeReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(now.Add(-time.Second)),
+	})
+	metrics.RecordConditionAge(obj, api.TypeReady)
+	expected = `
+# HELP achilles_condition_age_seconds Time elapsed since the last transition of the status condition of an Achilles resource.
+# TYPE achilles_condition_age_seconds gauge
+achilles_condition_age_seconds{group="test.infrared.reddit.com",kind="TestClaim",name="test-claim",namespace="default",status="True",type="Ready",version="v1alpha1"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_condition_age_seconds"))
+
+	metrics.DeleteConditionAge(obj, api.TypeReady)
+	count, err = testutil.GatherAndCount(reg, "achilles_condition_age_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestDeletionGracePeriod(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := MustMakeMetricsWithOptions(scheme, reg, types.MetricsOptions{DeletionGracePeriod: time.Minute})
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	metrics.deletions.clock = fakeClock
+
+	obj := &testv1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "test-claim", Namespace: "default"}}
+	obj.SetConditions(api.Condition{Type: api.TypeReady, Status: corev1.ConditionTrue})
+
+	series := func() int {
+		t.Helper()
+		count, err := testutil.GatherAndCount(reg, "achilles_resource_readiness", "achilles_managed_resources")
+		assert.NoError(t, err)
+		return count
+	}
+
+	metrics.RecordReadiness(obj)
+	metrics.RecordManagedResources(obj, 2)
+	// a readiness series per condition status, and the managed resources series
+	assert.Equal(t, 5, series())
+
+	// metrics survive within the grace period
+	metrics.DeleteReadiness(obj)
+	metrics.DeleteManagedResources(obj)
+	fakeClock.Step(time.Minute - time.Second)
+	assert.Equal(t, 5, series())
+
+	// and are deleted once it elapses
+	fakeClock.Step(time.Second)
+	assert.Equal(t, 0, series())
+
+	// metrics recorded again within the grace period, e.g. for recreated objects, aren't deleted
+	metrics.RecordReadiness(obj)
+	metrics.RecordManagedResources(obj, 2)
+	metrics.DeleteReadiness(obj)
+	metrics.DeleteManagedResources(obj)
+	fakeClock.Step(30 * time.Second)
+	metrics.RecordReadiness(obj)
+	fakeClock.Step(time.Hour)
+	count, err := testutil.GatherAndCount(reg, "achilles_resource_readiness")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count) // a series per condition status
+	count, err = testutil.GatherAndCount(reg, "achilles_managed_resources")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
 func TestRecordEvent(t *testing.T) {
 	scheme := runtime.NewScheme()
 	metrics := MustMakeMetrics(scheme, prometheus.NewRegistry())
@@ -521,3 +662,110 @@ func TestRecordEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordPendingChildDeletions(t *testing.T) {
+	metrics := MustMakeMetrics(scheme, prometheus.NewRegistry())
+	metricsDisabled := MustMakeMetricsWithOptions(scheme, prometheus.NewRegistry(), types.MetricsOptions{DisableMetrics: []types.AchillesMetrics{types.AchillesPendingChildDeletions}})
+
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+
+	parentA := &testv1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "parent-a", Namespace: "default"}}
+	parentB := &testv1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "parent-b", Namespace: "default"}}
+
+	gauge := func(m *Metrics, gvk schema.GroupVersionKind) float64 {
+		return testutil.ToFloat64(m.sink.pendingChildDeletionsGauge.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind))
+	}
+	count := func(m *Metrics) int {
+		return testutil.CollectAndCount(m.sink.pendingChildDeletionsGauge, "achilles_pending_child_deletions")
+	}
+
+	// first pass for parent A
+	metrics.RecordPendingChildDeletions(parentA, map[schema.GroupVersionKind]int{configMapGVK: 3, secretGVK: 1})
+	assert.Equal(t, 2, count(metrics))
+	assert.Equal(t, float64(3), gauge(metrics, configMapGVK))
+	assert.Equal(t, float64(1), gauge(metrics, secretGVK))
+
+	// parent B is deleted concurrently, totals are summed across parents
+	metrics.RecordPendingChildDeletions(parentB, map[schema.GroupVersionKind]int{configMapGVK: 2})
+	assert.Equal(t, float64(5), gauge(metrics, configMapGVK))
+
+	// parent A makes progress
+	metrics.RecordPendingChildDeletions(parentA, map[schema.GroupVersionKind]int{configMapGVK: 1})
+	assert.Equal(t, 1, count(metrics))
+	assert.Equal(t, float64(3), gauge(metrics, configMapGVK))
+
+	// parent A completes deletion
+	metrics.RecordPendingChildDeletions(parentA, map[schema.GroupVersionKind]int{})
+	assert.Equal(t, float64(2), gauge(metrics, configMapGVK))
+
+	// parent B completes deletion, the metric is deleted
+	metrics.RecordPendingChildDeletions(parentB, nil)
+	assert.Equal(t, 0, count(metrics))
+
+	// disabled metric
+	metricsDisabled.RecordPendingChildDeletions(parentA, map[schema.GroupVersionKind]int{configMapGVK: 3})
+	assert.Equal(t, 0, count(metricsDisabled))
+}
+
+func TestCustomMetrics(t *testing.T) {
+	gvk := testv1alpha1.TestClaimGroupVersionKind
+
+	t.Run("nil recorder is a no-op", func(t *testing.T) {
+		recorder := CustomMetricsFromContext(context.Background())
+		assert.Nil(t, recorder)
+		assert.NoError(t, recorder.SetGauge("capacity", "", 1, nil))
+		assert.NoError(t, recorder.AddCounter("provisioned_total", "", 1, nil))
+		assert.NoError(t, recorder.ObserveHistogram("provision_seconds", "", 1, nil))
+	})
+
+	t.Run("records metrics with GVK labels", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		ctx := NewCustomMetricsContext(context.Background(), NewCustomMetrics(reg).ForGVK(gvk))
+		recorder := CustomMetricsFromContext(ctx)
+
+		assert.NoError(t, recorder.SetGauge("capacity", "Provisioned capacity.", 3, prometheus.Labels{"tier": "gold"}))
+		assert.NoError(t, recorder.SetGauge("capacity", "Provisioned capacity.", 5, prometheus.Labels{"tier": "gold"}))
+		assert.NoError(t, recorder.AddCounter("provisioned_total", "Number of provisions.", 2, nil))
+		assert.NoError(t, recorder.ObserveHistogram("provision_seconds", "Provisioning duration.", 0.5, nil))
+
+		expected := `# HELP capacity Provisioned capacity.
+# TYPE capacity gauge
+capacity{group="test.infrared.reddit.com",kind="TestClaim",tier="gold",version="v1alpha1"} 5
+# HELP provisioned_total Number of provisions.
+# TYPE provisioned_total counter
+provisioned_total{group="test.infrared.reddit.com",kind="TestClaim",version="v1alpha1"} 2
+`
+		if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "capacity", "provisioned_total"); err != nil {
+			t.Error(err)
+		}
+		count, err := testutil.GatherAndCount(reg, "provision_seconds")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("shares metrics across controllers with the same registerer", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		claimRecorder := NewCustomMetrics(reg).ForGVK(gvk)
+		claimedRecorder := NewCustomMetrics(reg).ForGVK(testv1alpha1.TestClaimedGroupVersionKind)
+
+		assert.NoError(t, claimRecorder.AddCounter("provisioned_total", "", 1, nil))
+		assert.NoError(t, claimedRecorder.AddCounter("provisioned_total", "", 1, nil))
+
+		count, err := testutil.GatherAndCount(reg, "provisioned_total")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("inconsistent labels", func(t *testing.T) {
+		recorder := NewCustomMetrics(prometheus.NewRegistry()).ForGVK(gvk)
+
+		assert.NoError(t, recorder.SetGauge("capacity", "", 1, prometheus.Labels{"tier": "gold"}))
+		assert.Error(t, recorder.SetGauge("capacity", "", 1, prometheus.Labels{"region": "us-east-1"}))
+	})
+
+	t.Run("negative counter value", func(t *testing.T) {
+		recorder := NewCustomMetrics(prometheus.NewRegistry()).ForGVK(gvk)
+		assert.Error(t, recorder.AddCounter("provisioned_total", "", -1, nil))
+	})
+}
@@ -13,6 +13,19 @@ import (
 )
 
 const (
+	// ReconcileResultDone is a value for the "achilles_reconcile_result_total" metric's "result" label, indicating that
+	// the reconcile completed without requeueing.
+	ReconcileResultDone = "done"
+	// ReconcileResultRequeue is a value for the "achilles_reconcile_result_total" metric's "result" label, indicating that
+	// the reconcile requested a requeue.
+	ReconcileResultRequeue = "requeue"
+	// ReconcileResultError is a value for the "achilles_reconcile_result_total" metric's "result" label, indicating that
+	// the reconcile failed with an error and will be retried.
+	ReconcileResultError = "error"
+	// ReconcileResultTerminal is a value for the "achilles_reconcile_result_total" metric's "result" label, indicating that
+	// the reconcile failed with a terminal error and will not be retried.
+	ReconcileResultTerminal = "terminal"
+
 	// ConditionDeleted is a value for the "achilles_resource_readiness" metric's "type" label, indicating that the object
 	// is in terminating state.
 	ConditionDeleted = "Deleted"
@@ -26,6 +39,18 @@ type Sink struct {
 	suspendGauge                *prometheus.GaugeVec
 	processingDurationHistogram *prometheus.HistogramVec
 	eventCounter                *prometheus.CounterVec
+	pendingChildDeletionsGauge  *prometheus.GaugeVec
+	reconcileResultCounter      *prometheus.CounterVec
+	reconcileLoopGauge          *prometheus.GaugeVec
+	managedResourcesGauge       *prometheus.GaugeVec
+	activeReconcileHistogram    *prometheus.HistogramVec
+	conditionAge                *conditionAgeCollector
+	controllerPausedGauge       *prometheus.GaugeVec
+	triggerCoalescedCounter     *prometheus.CounterVec
+	statusSizeExceededGauge     *prometheus.GaugeVec
+	reconcileSkippedCounter     *prometheus.CounterVec
+	processingStartTimesGauge   *prometheus.GaugeVec
+	managedTypeUnservableGauge  *prometheus.GaugeVec
 }
 
 // NewSink returns a new achilles metrics Sink.
@@ -77,6 +102,87 @@ func NewSink() *Sink {
 			},
 			eventCounterLabel{}.names(),
 		),
+		pendingChildDeletionsGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_pending_child_deletions",
+				Help: "Number of child resources pending foreground deletion per child resource type.",
+			},
+			pendingChildDeletionsGaugeLabel{}.names(),
+		),
+		reconcileResultCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "achilles_reconcile_result_total",
+				Help: "Total number of reconciles per controller and result type (done, requeue, error, or terminal).",
+			},
+			reconcileResultCounterLabel{}.names(),
+		),
+		reconcileLoopGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_suspected_reconcile_loop",
+				Help: "Gauge reporting whether the object is suspected to be in a reconcile loop, i.e. repeatedly reconciled without status changes",
+			},
+			reconcileLoopGaugeLabel{}.names(),
+		),
+		managedResourcesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_managed_resources",
+				Help: "The number of resources managed by an Achilles resource, i.e. its managed resource refs.",
+			},
+			managedResourcesGaugeLabel{}.names(),
+		),
+		activeReconcileHistogram: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: "achilles_active_reconcile_seconds",
+				// stay consistent with controller-runtime's reconciliation duration metric, https://github.com/kubernetes-sigs/controller-runtime/blob/9516c0f9a0aa83a499b5a25907899e4edb0dd9db/pkg/internal/controller/metrics/metrics.go#L61-L62
+				Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.15, 0.2, 0.25, 0.3, 0.35, 0.4, 0.45, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0,
+					1.25, 1.5, 1.75, 2.0, 2.5, 3.0, 3.5, 4.0, 4.5, 5, 6, 7, 8, 9, 10, 15, 20, 25, 30, 40, 50, 60},
+				Help: "Histogram of the time spent actively reconciling an object per controller, excluding time spent waiting in the queue for a requeue",
+			},
+			activeReconcileHistogramLabel{}.names(),
+		),
+		conditionAge: newConditionAgeCollector(),
+		controllerPausedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_controller_paused",
+				Help: "Gauge reporting whether the controller's reconciliation is paused or not",
+			},
+			controllerPausedGaugeLabel{}.names(),
+		),
+		triggerCoalescedCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "achilles_trigger_coalesced_total",
+				Help: "Total number of triggers per controller enqueueing a request already waiting in the workqueue, which are coalesced into a single reconcile.",
+			},
+			triggerCoalescedCounterLabel{}.names(),
+		),
+		statusSizeExceededGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_status_size_exceeded",
+				Help: "Gauge reporting whether a status field (conditions or resourceRefs) of an Achilles resource exceeds its size threshold, suggesting unbounded growth.",
+			},
+			statusSizeExceededGaugeLabel{}.names(),
+		),
+		reconcileSkippedCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "achilles_reconcile_skipped_total",
+				Help: "Total number of reconciles per controller skipped by a state transition, e.g. for objects handled by another controller.",
+			},
+			reconcileSkippedCounterLabel{}.names(),
+		),
+		processingStartTimesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_processing_start_times_size",
+				Help: "Number of processing start times tracked per resource type for computing processing durations, which grows if objects never process successfully.",
+			},
+			processingStartTimesSizeGaugeLabel{}.names(),
+		),
+		managedTypeUnservableGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "achilles_managed_type_unservable",
+				Help: "Gauge reporting whether a type managed by the controller is no longer served by the kube-apiserver, e.g. because its CRD was deleted.",
+			},
+			managedTypeUnservableGaugeLabel{}.names(),
+		),
 	}
 }
 
@@ -88,6 +194,18 @@ func (r *Sink) Reset() {
 	r.suspendGauge.Reset()
 	r.processingDurationHistogram.Reset()
 	r.eventCounter.Reset()
+	r.pendingChildDeletionsGauge.Reset()
+	r.reconcileResultCounter.Reset()
+	r.reconcileLoopGauge.Reset()
+	r.managedResourcesGauge.Reset()
+	r.activeReconcileHistogram.Reset()
+	r.conditionAge.reset()
+	r.controllerPausedGauge.Reset()
+	r.triggerCoalescedCounter.Reset()
+	r.statusSizeExceededGauge.Reset()
+	r.reconcileSkippedCounter.Reset()
+	r.processingStartTimesGauge.Reset()
+	r.managedTypeUnservableGauge.Reset()
 }
 
 // Collectors returns a slice of Prometheus collectors, which can be used to register them in a metrics registry.
@@ -99,6 +217,18 @@ func (r *Sink) Collectors() []prometheus.Collector {
 		r.suspendGauge,
 		r.processingDurationHistogram,
 		r.eventCounter,
+		r.pendingChildDeletionsGauge,
+		r.reconcileResultCounter,
+		r.reconcileLoopGauge,
+		r.managedResourcesGauge,
+		r.activeReconcileHistogram,
+		r.conditionAge,
+		r.controllerPausedGauge,
+		r.triggerCoalescedCounter,
+		r.statusSizeExceededGauge,
+		r.reconcileSkippedCounter,
+		r.processingStartTimesGauge,
+		r.managedTypeUnservableGauge,
 	}
 }
 
@@ -268,6 +398,20 @@ func (r *Sink) RecordProcessingDuration(
 	).Observe(duration.Seconds())
 }
 
+// RecordProcessingStartTimesSize records the number of processing start times tracked for the given GVK.
+func (r *Sink) RecordProcessingStartTimesSize(
+	gvk schema.GroupVersionKind,
+	size int,
+) {
+	r.processingStartTimesGauge.WithLabelValues(
+		processingStartTimesSizeGaugeLabel{
+			group:   gvk.Group,
+			version: gvk.Version,
+			kind:    gvk.Kind,
+		}.values()...,
+	).Set(float64(size))
+}
+
 // RecordEvent increments the counter for the given controller, qualified by the associated object GVK and object ref
 // and reconciled object ref.
 func (r *Sink) RecordEvent(
@@ -307,3 +451,257 @@ func (r *Sink) DeleteEvent(
 		}.partialValues(),
 	)
 }
+
+// RecordPendingChildDeletions records the number of child resources of the given type pending deletion.
+func (r *Sink) RecordPendingChildDeletions(
+	gvk schema.GroupVersionKind,
+	pending int,
+) {
+	r.pendingChildDeletionsGauge.WithLabelValues(
+		pendingChildDeletionsGaugeLabel{
+			group:   gvk.Group,
+			version: gvk.Version,
+			kind:    gvk.Kind,
+		}.values()...,
+	).Set(float64(pending))
+}
+
+// DeletePendingChildDeletions deletes the pending child deletions metric for the given type.
+func (r *Sink) DeletePendingChildDeletions(
+	gvk schema.GroupVersionKind,
+) bool {
+	return r.pendingChildDeletionsGauge.DeleteLabelValues(
+		pendingChildDeletionsGaugeLabel{
+			group:   gvk.Group,
+			version: gvk.Version,
+			kind:    gvk.Kind,
+		}.values()...,
+	)
+}
+
+// RecordReconcileResult increments the counter for the given controller and reconcile result type.
+func (r *Sink) RecordReconcileResult(
+	controllerName string,
+	result string,
+) {
+	r.reconcileResultCounter.WithLabelValues(
+		reconcileResultCounterLabel{
+			controller: controllerName,
+			result:     result,
+		}.values()...,
+	).Inc()
+}
+
+// RecordSuspectedReconcileLoop records whether the object is suspected to be in a reconcile loop or not.
+func (r *Sink) RecordSuspectedReconcileLoop(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+	suspected bool,
+) {
+	var value float64
+	if suspected {
+		value = 1
+	}
+	r.reconcileLoopGauge.WithLabelValues(
+		reconcileLoopGaugeLabel{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			name:      ref.Name,
+			namespace: ref.Namespace,
+		}.values()...,
+	).Set(value)
+}
+
+// DeleteSuspectedReconcileLoop deletes the suspected reconcile loop metric for the given object.
+func (r *Sink) DeleteSuspectedReconcileLoop(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+) bool {
+	return r.reconcileLoopGauge.DeleteLabelValues(
+		reconcileLoopGaugeLabel{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			name:      ref.Name,
+			namespace: ref.Namespace,
+		}.values()...,
+	)
+}
+
+// RecordManagedResources records the number of resources managed by the given object.
+func (r *Sink) RecordManagedResources(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+	count int,
+) {
+	r.managedResourcesGauge.WithLabelValues(
+		managedResourcesGaugeLabel{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			name:      ref.Name,
+			namespace: ref.Namespace,
+		}.values()...,
+	).Set(float64(count))
+}
+
+// DeleteManagedResources deletes the managed resources metric for the given object.
+func (r *Sink) DeleteManagedResources(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+) bool {
+	return r.managedResourcesGauge.DeleteLabelValues(
+		managedResourcesGaugeLabel{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			name:      ref.Name,
+			namespace: ref.Namespace,
+		}.values()...,
+	)
+}
+
+// RecordStatusSizeExceeded records whether the given status field of the object exceeds its size threshold.
+func (r *Sink) RecordStatusSizeExceeded(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+	field string,
+	exceeded bool,
+) {
+	var value float64
+	if exceeded {
+		value = 1
+	}
+	r.statusSizeExceededGauge.WithLabelValues(
+		statusSizeExceededGaugeLabel{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			name:      ref.Name,
+			namespace: ref.Namespace,
+			field:     field,
+		}.values()...,
+	).Set(value)
+}
+
+// DeleteStatusSizeExceeded deletes the status size metrics of all status fields of the given object.
+func (r *Sink) DeleteStatusSizeExceeded(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+) int {
+	return r.statusSizeExceededGauge.DeletePartialMatch(
+		statusSizeExceededGaugeLabel{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			name:      ref.Name,
+			namespace: ref.Namespace,
+		}.partialValues(),
+	)
+}
+
+// RecordActiveReconcileDuration records the duration of a single reconcile for the given controller.
+func (r *Sink) RecordActiveReconcileDuration(
+	controllerName string,
+	duration time.Duration,
+) {
+	r.activeReconcileHistogram.WithLabelValues(
+		activeReconcileHistogramLabel{
+			controller: controllerName,
+		}.values()...,
+	).Observe(duration.Seconds())
+}
+
+// RecordConditionAge records the last transition time of the condition, from which the condition's age is reported.
+func (r *Sink) RecordConditionAge(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+	condition api.Condition,
+) {
+	r.conditionAge.record(
+		conditionGaugeLabel{
+			group:         gvk.Group,
+			version:       gvk.Version,
+			kind:          gvk.Kind,
+			name:          ref.Name,
+			namespace:     ref.Namespace,
+			conditionType: condition.Type.String(),
+			status:        string(condition.Status),
+		},
+		condition.LastTransitionTime.Time,
+	)
+}
+
+// DeleteConditionAge deletes the age metric of the condition, regardless of its status.
+func (r *Sink) DeleteConditionAge(
+	ref client.ObjectKey,
+	gvk schema.GroupVersionKind,
+	conditionType api.ConditionType,
+) bool {
+	return r.conditionAge.delete(
+		conditionGaugeLabel{
+			group:         gvk.Group,
+			version:       gvk.Version,
+			kind:          gvk.Kind,
+			name:          ref.Name,
+			namespace:     ref.Namespace,
+			conditionType: conditionType.String(),
+		},
+	)
+}
+
+// RecordControllerPaused records whether the controller's reconciliation is paused or not.
+func (r *Sink) RecordControllerPaused(
+	controllerName string,
+	paused bool,
+) {
+	var value float64
+	if paused {
+		value = 1
+	}
+	r.controllerPausedGauge.WithLabelValues(
+		controllerPausedGaugeLabel{
+			controller: controllerName,
+		}.values()...,
+	).Set(value)
+}
+
+// RecordTriggerCoalesced increments the counter of coalesced triggers for the given controller.
+func (r *Sink) RecordTriggerCoalesced(controllerName string) {
+	r.triggerCoalescedCounter.WithLabelValues(
+		triggerCoalescedCounterLabel{
+			controller: controllerName,
+		}.values()...,
+	).Inc()
+}
+
+// RecordReconcileSkipped increments the counter of skipped reconciles for the given controller and reason.
+func (r *Sink) RecordReconcileSkipped(controllerName string, reason string) {
+	r.reconcileSkippedCounter.WithLabelValues(
+		reconcileSkippedCounterLabel{
+			controller: controllerName,
+			reason:     reason,
+		}.values()...,
+	).Inc()
+}
+
+// RecordManagedTypeUnservable records whether the given type managed by the controller is unservable.
+func (r *Sink) RecordManagedTypeUnservable(
+	controllerName string,
+	gvk schema.GroupVersionKind,
+	unservable bool,
+) {
+	var value float64
+	if unservable {
+		value = 1
+	}
+	r.managedTypeUnservableGauge.WithLabelValues(
+		managedTypeUnservableGaugeLabel{
+			controller: controllerName,
+			group:      gvk.Group,
+			version:    gvk.Version,
+			kind:       gvk.Kind,
+		}.values()...,
+	).Set(value)
+}
@@ -140,6 +140,28 @@ func (c suspendGaugeLabel) values() []string {
 	}
 }
 
+type pendingChildDeletionsGaugeLabel struct {
+	group   string
+	version string
+	kind    string
+}
+
+func (c pendingChildDeletionsGaugeLabel) names() []string {
+	return []string{
+		"group",
+		"version",
+		"kind",
+	}
+}
+
+func (c pendingChildDeletionsGaugeLabel) values() []string {
+	return []string{
+		c.group,
+		c.version,
+		c.kind,
+	}
+}
+
 type processingDurationHistogramLabel struct {
 	group   string
 	version string
@@ -213,3 +235,234 @@ func (c eventCounterLabel) partialValues() prometheus.Labels {
 		"objNamespace": c.objNamespace,
 	}
 }
+
+type reconcileResultCounterLabel struct {
+	controller string
+	result     string
+}
+
+func (c reconcileResultCounterLabel) names() []string {
+	return []string{
+		"controller",
+		"result",
+	}
+}
+
+func (c reconcileResultCounterLabel) values() []string {
+	return []string{
+		c.controller,
+		c.result,
+	}
+}
+
+type managedResourcesGaugeLabel struct {
+	group     string
+	version   string
+	kind      string
+	name      string
+	namespace string
+}
+
+func (c managedResourcesGaugeLabel) names() []string {
+	return []string{
+		"group",
+		"version",
+		"kind",
+		"name",
+		"namespace",
+	}
+}
+
+func (c managedResourcesGaugeLabel) values() []string {
+	return []string{
+		c.group,
+		c.version,
+		c.kind,
+		c.name,
+		c.namespace,
+	}
+}
+
+type reconcileLoopGaugeLabel struct {
+	group     string
+	version   string
+	kind      string
+	name      string
+	namespace string
+}
+
+func (c reconcileLoopGaugeLabel) names() []string {
+	return []string{
+		"group",
+		"version",
+		"kind",
+		"name",
+		"namespace",
+	}
+}
+
+func (c reconcileLoopGaugeLabel) values() []string {
+	return []string{
+		c.group,
+		c.version,
+		c.kind,
+		c.name,
+		c.namespace,
+	}
+}
+
+type activeReconcileHistogramLabel struct {
+	controller string
+}
+
+func (c activeReconcileHistogramLabel) names() []string {
+	return []string{
+		"controller",
+	}
+}
+
+func (c activeReconcileHistogramLabel) values() []string {
+	return []string{
+		c.controller,
+	}
+}
+
+type controllerPausedGaugeLabel struct {
+	controller string
+}
+
+func (c controllerPausedGaugeLabel) names() []string {
+	return []string{
+		"controller",
+	}
+}
+
+func (c controllerPausedGaugeLabel) values() []string {
+	return []string{
+		c.controller,
+	}
+}
+
+type triggerCoalescedCounterLabel struct {
+	controller string
+}
+
+func (c triggerCoalescedCounterLabel) names() []string {
+	return []string{
+		"controller",
+	}
+}
+
+func (c triggerCoalescedCounterLabel) values() []string {
+	return []string{
+		c.controller,
+	}
+}
+
+type statusSizeExceededGaugeLabel struct {
+	group     string
+	version   string
+	kind      string
+	name      string
+	namespace string
+	field     string
+}
+
+func (c statusSizeExceededGaugeLabel) names() []string {
+	return []string{
+		"group",
+		"version",
+		"kind",
+		"name",
+		"namespace",
+		"field",
+	}
+}
+
+func (c statusSizeExceededGaugeLabel) values() []string {
+	return []string{
+		c.group,
+		c.version,
+		c.kind,
+		c.name,
+		c.namespace,
+		c.field,
+	}
+}
+
+// partialValues returns the label values identifying the object, used for deleting the metrics of all its status fields.
+func (c statusSizeExceededGaugeLabel) partialValues() prometheus.Labels {
+	return prometheus.Labels{
+		"group":     c.group,
+		"version":   c.version,
+		"kind":      c.kind,
+		"name":      c.name,
+		"namespace": c.namespace,
+	}
+}
+
+type reconcileSkippedCounterLabel struct {
+	controller string
+	reason     string
+}
+
+func (c reconcileSkippedCounterLabel) names() []string {
+	return []string{
+		"controller",
+		"reason",
+	}
+}
+
+func (c reconcileSkippedCounterLabel) values() []string {
+	return []string{
+		c.controller,
+		c.reason,
+	}
+}
+
+type processingStartTimesSizeGaugeLabel struct {
+	group   string
+	version string
+	kind    string
+}
+
+func (c processingStartTimesSizeGaugeLabel) names() []string {
+	return []string{
+		"group",
+		"version",
+		"kind",
+	}
+}
+
+func (c processingStartTimesSizeGaugeLabel) values() []string {
+	return []string{
+		c.group,
+		c.version,
+		c.kind,
+	}
+}
+
+type managedTypeUnservableGaugeLabel struct {
+	controller string
+	group      string
+	version    string
+	kind       string
+}
+
+func (c managedTypeUnservableGaugeLabel) names() []string {
+	return []string{
+		"controller",
+		"group",
+		"version",
+		"kind",
+	}
+}
+
+func (c managedTypeUnservableGaugeLabel) values() []string {
+	return []string{
+		c.controller,
+		c.group,
+		c.version,
+		c.kind,
+	}
+}
@@ -0,0 +1,102 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultRequeuePageSize is the number of objects listed per page when requeuing all objects.
+const defaultRequeuePageSize = 500
+
+// Requeuer enqueues all objects of a controller's reconciled type on demand, e.g. to force reconciliation of every
+// object after a change in reconciliation logic, triggered by a signal or admin endpoint.
+// It's wired up to a controller through WithControllerHandle(requeuer.ControllerFunc()).
+type Requeuer struct {
+	reader   client.Reader
+	list     client.ObjectList
+	pageSize int64
+
+	mu    sync.RWMutex
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+}
+
+// NewRequeuer returns a Requeuer listing objects into list, which must be the list type of the controller's reconciled type.
+// Since the cache doesn't support paginated lists, reader should read directly from the API server (e.g. the manager's APIReader).
+func NewRequeuer(reader client.Reader, list client.ObjectList) *Requeuer {
+	return &Requeuer{
+		reader:   reader,
+		list:     list,
+		pageSize: defaultRequeuePageSize,
+	}
+}
+
+// WithPageSize sets the number of objects listed per page. Defaults to 500.
+func (r *Requeuer) WithPageSize(pageSize int64) *Requeuer {
+	r.pageSize = pageSize
+	return r
+}
+
+// ControllerFunc returns the ControllerFunc wiring up the Requeuer with the controller's work queue.
+func (r *Requeuer) ControllerFunc() ControllerFunc {
+	return func(c controller.Controller) {
+		if err := c.Watch(r); err != nil {
+			c.GetLogger().Error(err, "watching requeuer")
+		}
+	}
+}
+
+// Start implements source.Source.
+func (r *Requeuer) Start(_ context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queue = queue
+	return nil
+}
+
+// RequeueAll lists all objects of the reconciled type, page by page, and enqueues them for reconciliation.
+// Additional list options (e.g. client.InNamespace) narrow down the requeued objects.
+// Returns the number of enqueued objects.
+func (r *Requeuer) RequeueAll(ctx context.Context, opts ...client.ListOption) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.queue == nil {
+		return 0, errors.New("requeuer has not been started by a controller")
+	}
+
+	var count int
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	listOpts.Limit = r.pageSize
+	for {
+		list := r.list.DeepCopyObject().(client.ObjectList)
+		if err := r.reader.List(ctx, list, listOpts); err != nil {
+			return count, fmt.Errorf("listing objects: %w", err)
+		}
+
+		if err := meta.EachListItem(list, func(o runtime.Object) error {
+			obj, ok := o.(client.Object)
+			if !ok {
+				return fmt.Errorf("unexpected list item type %T", o)
+			}
+			r.queue.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			count++
+			return nil
+		}); err != nil {
+			return count, err
+		}
+
+		if list.GetContinue() == "" {
+			return count, nil
+		}
+		listOpts.Continue = list.GetContinue()
+	}
+}
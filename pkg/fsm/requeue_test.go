@@ -0,0 +1,97 @@
+package fsm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+// pagingReader paginates lists of TestClaims, which the fake client doesn't support
+type pagingReader struct {
+	client.Reader
+	pages int
+}
+
+func (r *pagingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	all := &v1alpha1.TestClaimList{}
+	if err := r.Reader.List(ctx, all, &client.ListOptions{Namespace: listOpts.Namespace}); err != nil {
+		return err
+	}
+
+	var start int
+	if listOpts.Continue != "" {
+		var err error
+		if start, err = strconv.Atoi(listOpts.Continue); err != nil {
+			return fmt.Errorf("invalid continue token %q", listOpts.Continue)
+		}
+	}
+	end := min(start+int(listOpts.Limit), len(all.Items))
+
+	page := list.(*v1alpha1.TestClaimList)
+	page.Items = all.Items[start:end]
+	if end < len(all.Items) {
+		page.Continue = strconv.Itoa(end)
+	}
+	r.pages++
+	return nil
+}
+
+func TestRequeuer_RequeueAll(t *testing.T) {
+	var objs []client.Object
+	expected := sets.New[reconcile.Request]()
+	for i := range 7 {
+		claim := &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("claim-%d", i), Namespace: "default"}}
+		objs = append(objs, claim)
+		expected.Insert(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+	}
+	// objects excluded by list options aren't requeued
+	objs = append(objs, &v1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"}})
+
+	reader := &pagingReader{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+	requeuer := NewRequeuer(reader, &v1alpha1.TestClaimList{}).WithPageSize(3)
+
+	ctx := context.Background()
+	if _, err := requeuer.RequeueAll(ctx); err == nil {
+		t.Error("expected error requeuing before the requeuer is started")
+	}
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	if err := requeuer.Start(ctx, q); err != nil {
+		t.Fatalf("starting requeuer: %s", err)
+	}
+
+	count, err := requeuer.RequeueAll(ctx, client.InNamespace("default"))
+	if err != nil {
+		t.Fatalf("requeuing all objects: %s", err)
+	}
+	if count != expected.Len() {
+		t.Errorf("expected %d requeued objects, got %d", expected.Len(), count)
+	}
+	if reader.pages != 3 {
+		t.Errorf("expected 3 listed pages, got %d", reader.pages)
+	}
+
+	actual := sets.New[reconcile.Request]()
+	for q.Len() > 0 {
+		req, _ := q.Get()
+		actual.Insert(req)
+		q.Done(req)
+	}
+	if !actual.Equal(expected) {
+		t.Errorf("unexpected requeued requests, missing %v, extra %v", expected.Difference(actual).UnsortedList(), actual.Difference(expected).UnsortedList())
+	}
+}
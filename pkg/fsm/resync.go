@@ -0,0 +1,95 @@
+package fsm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var _ source.Source = &resyncSource{}
+
+// resyncSource periodically replays all cached objects of a type to an event handler as generic events, analogous to
+// an informer resync. Replaying objects from the shared cache rather than configuring a dedicated cache with its own
+// sync period resyncs a single type without duplicating its informer.
+type resyncSource struct {
+	log      *zap.SugaredLogger
+	reader   client.Reader
+	list     client.ObjectList
+	interval time.Duration
+	handler  handler.EventHandler
+}
+
+// newResyncSource returns a source resyncing objects of the given type read from reader, typically the manager's cache-backed client.
+func newResyncSource(
+	log *zap.SugaredLogger,
+	scheme *runtime.Scheme,
+	reader client.Reader,
+	gvk schema.GroupVersionKind,
+	interval time.Duration,
+	h handler.EventHandler,
+) (*resyncSource, error) {
+	o, err := scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, fmt.Errorf("constructing list of type %s: %w", gvk, err)
+	}
+	list, ok := o.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected list type %T for type %s", o, gvk)
+	}
+
+	return &resyncSource{
+		log:      log,
+		reader:   reader,
+		list:     list,
+		interval: interval,
+		handler:  h,
+	}, nil
+}
+
+// Start implements source.Source, resyncing every interval until the context is done.
+func (s *resyncSource) Start(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.resync(ctx, queue); err != nil {
+					s.log.Errorf("resyncing %T: %s", s.list, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// resync replays all objects of the source's type to the event handler.
+func (s *resyncSource) resync(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	list := s.list.DeepCopyObject().(client.ObjectList)
+	if err := s.reader.List(ctx, list); err != nil {
+		return fmt.Errorf("listing objects: %w", err)
+	}
+
+	return meta.EachListItem(list, func(o runtime.Object) error {
+		obj, ok := o.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected list item type %T", o)
+		}
+		s.handler.Generic(ctx, event.GenericEvent{Object: obj}, queue)
+		return nil
+	})
+}
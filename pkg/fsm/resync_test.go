@@ -0,0 +1,89 @@
+package fsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// countingQueue counts the requests added to the queue
+type countingQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	mu    sync.Mutex
+	added map[reconcile.Request]int
+}
+
+func (q *countingQueue) Add(req reconcile.Request) {
+	q.mu.Lock()
+	q.added[req]++
+	q.mu.Unlock()
+	q.TypedRateLimitingInterface.Add(req)
+}
+
+func (q *countingQueue) count(req reconcile.Request) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.added[req]
+}
+
+func TestResyncSource(t *testing.T) {
+	owned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "default", Labels: map[string]string{"owner": "claim"}}}
+	unowned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unowned", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(owned, unowned).Build()
+
+	// enqueue the owner of config maps labelled with one
+	h := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+		if owner, ok := o.GetLabels()["owner"]; ok {
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: owner, Namespace: o.GetNamespace()}}}
+		}
+		return nil
+	})
+
+	const interval = 50 * time.Millisecond
+	src, err := newResyncSource(zaptest.NewLogger(t).Sugar(), scheme, c, corev1.SchemeGroupVersion.WithKind("ConfigMap"), interval, h)
+	if err != nil {
+		t.Fatalf("constructing resync source: %s", err)
+	}
+
+	q := &countingQueue{
+		TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]()),
+		added:                      map[reconcile.Request]int{},
+	}
+	defer q.ShutDown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	if err := src.Start(ctx, q); err != nil {
+		t.Fatalf("starting resync source: %s", err)
+	}
+
+	// objects are resynced once per interval
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Name: "claim", Namespace: "default"}}
+	deadline := time.After(5 * time.Second)
+	for q.count(req) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected 3 resyncs, got %d", q.count(req))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Errorf("expected 3 resyncs to take at least %s, took %s", 3*interval, elapsed)
+	}
+
+	if len(q.added) != 1 {
+		t.Errorf("expected only the owner to be enqueued, got %v", q.added)
+	}
+}
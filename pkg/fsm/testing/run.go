@@ -0,0 +1,151 @@
+// Package fsmtesting provides helpers for unit testing FSMs without a running controller or kube-apiserver.
+package fsmtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zaptest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apitypes "github.com/reddit/achilles-sdk-api/pkg/types"
+	"github.com/reddit/achilles-sdk/pkg/fsm"
+	"github.com/reddit/achilles-sdk/pkg/fsm/internal"
+	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+// defaultMaxReconciles is the default maximum number of reconciles performed by RunToCompletion.
+const defaultMaxReconciles = 20
+
+// errNotConverged is returned if the FSM doesn't reach steady state within the maximum number of reconciles.
+var errNotConverged = errors.New("FSM did not converge")
+
+// Reconcile is the trace of a single reconcile.
+type Reconcile struct {
+	// States are the names of the states entered during the reconcile, in order.
+	States []string
+	// Result is the result returned by the reconcile.
+	Result ctrl.Result
+	// Err is the error returned by the reconcile.
+	Err error
+}
+
+// Trace is the sequence of reconciles performed by RunToCompletion.
+type Trace []Reconcile
+
+// States returns the names of the states entered across all reconciles, in order.
+func (t Trace) States() []string {
+	var states []string
+	for _, r := range t {
+		states = append(states, r.States...)
+	}
+	return states
+}
+
+// String returns a human-readable summary of the trace, one line per reconcile.
+func (t Trace) String() string {
+	var b strings.Builder
+	for i, r := range t {
+		fmt.Fprintf(&b, "reconcile %d: states=[%s] result=%+v", i, strings.Join(r.States, ", "), r.Result)
+		if r.Err != nil {
+			fmt.Fprintf(&b, " error=%q", r.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+type options struct {
+	maxReconciles int
+}
+
+// Option configures RunToCompletion.
+type Option func(*options)
+
+// WithMaxReconciles sets the maximum number of reconciles performed before the FSM is considered non-converging.
+// Defaults to 20.
+func WithMaxReconciles(n int) Option {
+	return func(o *options) {
+		o.maxReconciles = n
+	}
+}
+
+// RunToCompletion drives reconciles of obj through the FSM built by builder, using the client c
+// (e.g. a fake client populated with obj), until the FSM reaches steady state, i.e. a reconcile completes
+// without error or requeue. Requeues are performed immediately, without waiting for the requested duration.
+// Returns the trace of all reconciles performed.
+//
+// The test fails if the FSM doesn't converge within the maximum number of reconciles (see WithMaxReconciles),
+// or if a reconcile returns a terminal error.
+func RunToCompletion[T any, Obj apitypes.FSMResource[T]](
+	t testing.TB,
+	builder *fsm.Builder[T, Obj],
+	obj Obj,
+	c client.Client,
+	opts ...Option,
+) Trace {
+	t.Helper()
+
+	trace, err := runToCompletion(context.Background(), t, builder, obj, c, opts...)
+	if err != nil {
+		t.Fatalf("running FSM to completion: %s\n%s", err, trace)
+	}
+	return trace
+}
+
+func runToCompletion[T any, Obj apitypes.FSMResource[T]](
+	ctx context.Context,
+	t testing.TB,
+	builder *fsm.Builder[T, Obj],
+	obj Obj,
+	c client.Client,
+	opts ...Option,
+) (Trace, error) {
+	o := &options{maxReconciles: defaultMaxReconciles}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if err := builder.Validate(); err != nil {
+		return nil, fmt.Errorf("validating builder: %w", err)
+	}
+
+	scheme := c.Scheme()
+	m := metrics.MustMakeMetrics(scheme, prometheus.NewRegistry())
+	m.InitializeForGVK(meta.MustGVKForObject(obj, scheme))
+
+	r, ok := builder.Reconciler(zaptest.NewLogger(t).Sugar(), scheme, c, m).(internal.Reconciler)
+	if !ok {
+		return nil, fmt.Errorf("builder returned an unexpected reconciler type")
+	}
+
+	var states []string
+	r.SetStateObserver(func(state string) {
+		states = append(states, state)
+	})
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+
+	var trace Trace
+	for range o.maxReconciles {
+		states = nil
+		res, err := r.Reconcile(ctx, req)
+		trace = append(trace, Reconcile{States: states, Result: res, Err: err})
+
+		if errors.Is(err, reconcile.TerminalError(nil)) {
+			return trace, fmt.Errorf("terminal error: %w", err)
+		}
+		if err == nil && res.IsZero() {
+			return trace, nil
+		}
+	}
+
+	return trace, fmt.Errorf("%w after %d reconciles", errNotConverged, o.maxReconciles)
+}
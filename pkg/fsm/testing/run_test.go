@@ -0,0 +1,132 @@
+package fsmtesting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/reddit/achilles-sdk/pkg/fsm"
+	"github.com/reddit/achilles-sdk/pkg/fsm/types"
+	intscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+type state = types.State[*v1alpha1.TestClaim]
+
+func newTestClaim() *v1alpha1.TestClaim {
+	return &v1alpha1.TestClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-claim", Namespace: "default"},
+	}
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(intscheme.MustNewScheme()).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		Build()
+}
+
+// Test_RunToCompletion is an example of testing an FSM that provisions a ConfigMap and waits for it to be populated
+// by another actor.
+func Test_RunToCompletion(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	claim := newTestClaim()
+	c := newFakeClient(claim)
+
+	awaitPopulated := &state{
+		Name: "await-populated",
+		Transition: func(ctx context.Context, _ *v1alpha1.TestClaim, _ *types.OutputSet) (*state, types.Result) {
+			actual := &corev1.ConfigMap{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+				return nil, types.ErrorResult(err)
+			}
+			if actual.Data["populated"] != "true" {
+				// simulate another actor populating the ConfigMap
+				actual.Data = map[string]string{"populated": "true"}
+				if err := c.Update(ctx, actual); err != nil {
+					return nil, types.ErrorResult(err)
+				}
+				return nil, types.RequeueResultWithBackoff("waiting for ConfigMap to be populated")
+			}
+			return nil, types.DoneResult()
+		},
+	}
+	provision := &state{
+		Name: "provision",
+		Transition: func(_ context.Context, _ *v1alpha1.TestClaim, out *types.OutputSet) (*state, types.Result) {
+			out.Apply(cm.DeepCopy())
+			return awaitPopulated, types.DoneResult()
+		},
+	}
+
+	builder := fsm.NewBuilder(&v1alpha1.TestClaim{}, provision, c.Scheme()).
+		Manages(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+	trace := RunToCompletion(t, builder, claim, c)
+
+	assert.Equal(t, []string{"provision", "await-populated", "provision", "await-populated"}, trace.States())
+	assert.Len(t, trace, 2)
+	assert.True(t, trace[0].Result.Requeue)
+	assert.NoError(t, trace[1].Err)
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}))
+}
+
+func Test_runToCompletion_Errors(t *testing.T) {
+	tcs := []struct {
+		name          string
+		result        types.Result
+		options       []Option
+		expectedErr   error
+		expectedTrace int
+	}{
+		{
+			name:          "non-converging",
+			result:        types.RequeueResultWithBackoff("never done"),
+			expectedErr:   errNotConverged,
+			expectedTrace: defaultMaxReconciles,
+		},
+		{
+			name:          "non-converging with max reconciles",
+			result:        types.ErrorResult(errors.New("retryable")),
+			options:       []Option{WithMaxReconciles(3)},
+			expectedErr:   errNotConverged,
+			expectedTrace: 3,
+		},
+		{
+			name:          "terminal error",
+			result:        types.ErrorResult(reconcile.TerminalError(errors.New("fatal"))),
+			expectedErr:   reconcile.TerminalError(nil),
+			expectedTrace: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := newTestClaim()
+			c := newFakeClient(claim)
+
+			initialState := &state{
+				Name: "state",
+				Transition: func(_ context.Context, _ *v1alpha1.TestClaim, _ *types.OutputSet) (*state, types.Result) {
+					return nil, tc.result
+				},
+			}
+			builder := fsm.NewBuilder(&v1alpha1.TestClaim{}, initialState, c.Scheme())
+
+			trace, err := runToCompletion(context.Background(), t, builder, claim, c, tc.options...)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Len(t, trace, tc.expectedTrace)
+			for _, r := range trace {
+				assert.Equal(t, []string{"state"}, r.States)
+			}
+		})
+	}
+}
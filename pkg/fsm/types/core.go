@@ -34,4 +34,7 @@ type State[T client.Object] struct {
 	// (indicating the state has not completed successfully and will be retried).
 	// The condition Type should be exported so they can be consumed by external systems.
 	Condition api.Condition
+	// Next optionally declares the states that Transition may return, for static validation of the FSM graph
+	// (see Builder.ValidateGraph). It doesn't affect reconciliation, in which the next state is determined by Transition.
+	Next []*State[T]
 }
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search for the next occurrence of a cron schedule, so that schedules that never occur
+// (e.g. on February 30th) are reported as errors.
+const cronSearchYears = 5
+
+// cronDescriptors are the supported shorthands for common cron schedules.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField is the range of values of a cron schedule field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{name: "month", min: 1, max: 12}
+	// both 0 and 7 denote Sunday
+	cronDayOfWeek = cronField{name: "day of week", min: 0, max: 7}
+)
+
+// cronSchedule is a parsed cron schedule. Each field is a bitset of the values at which the schedule occurs.
+type cronSchedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	// whether the day of month and day of week fields are unrestricted, see matchesDay
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// parseCron parses a standard five field cron spec ("minute hour day-of-month month day-of-week"). Fields support
+// wildcards ("*"), lists ("1,15"), ranges ("1-5"), and steps ("*/15", "0-30/10"). Descriptors such as "@daily" are
+// also supported.
+func parseCron(spec string) (*cronSchedule, error) {
+	expanded := spec
+	if descriptor, ok := cronDescriptors[strings.TrimSpace(spec)]; ok {
+		expanded = descriptor
+	}
+
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	for _, f := range []struct {
+		field cronField
+		expr  string
+		bits  *uint64
+	}{
+		{field: cronMinute, expr: fields[0], bits: &s.minutes},
+		{field: cronHour, expr: fields[1], bits: &s.hours},
+		{field: cronDayOfMonth, expr: fields[2], bits: &s.daysOfMonth},
+		{field: cronMonth, expr: fields[3], bits: &s.months},
+		{field: cronDayOfWeek, expr: fields[4], bits: &s.daysOfWeek},
+	} {
+		if *f.bits, err = f.field.parse(f.expr); err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %w", spec, err)
+		}
+	}
+	// Sunday may be specified as 7
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek |= 1
+	}
+	s.anyDayOfMonth = strings.HasPrefix(fields[2], "*")
+	s.anyDayOfWeek = strings.HasPrefix(fields[4], "*")
+
+	return &s, nil
+}
+
+// parse returns the bitset of values matched by a field expression.
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepExpr, f.name)
+			}
+		}
+
+		start, end := f.min, f.max
+		if rangeExpr != "*" {
+			startExpr, endExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if start, err = f.value(startExpr); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if end, err = f.value(endExpr); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				// a single value, steps of which run to the end of the field's range
+				end = start
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of the field.
+func (f cronField) value(expr string) (int, error) {
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, must be between %d and %d", expr, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// next returns the first occurrence of the schedule strictly after t, in t's location.
+// Returns false if the schedule doesn't occur within cronSearchYears.
+func (s *cronSchedule) next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		// advance the first mismatching field, resetting the fields below it
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// matchesDay returns true if t's day matches the schedule. Following cron conventions, if both the day of month and
+// day of week are restricted, the day matches if either does.
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
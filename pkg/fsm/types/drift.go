@@ -0,0 +1,105 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+// DriftReason is the reason a managed resource has drifted from its desired state.
+type DriftReason string
+
+const (
+	// DriftReasonMissing indicates that the managed resource doesn't exist.
+	DriftReasonMissing DriftReason = "Missing"
+	// DriftReasonModified indicates that the managed resource differs from its desired state.
+	DriftReasonModified DriftReason = "Modified"
+)
+
+// DriftEntry describes a managed resource that has drifted from its desired state.
+type DriftEntry struct {
+	// Ref is the reference to the drifted managed resource.
+	Ref api.TypedObjectRef
+	// Reason is the reason the managed resource has drifted.
+	Reason DriftReason
+}
+
+// DetectDrift compares the desired managed resources of the parent against their live state, and returns an entry for
+// each managed resource that has drifted, without applying any changes. A managed resource has drifted if it doesn't
+// exist, or if any field set on the desired object differs from the live object, using the same semantic comparison of
+// numeric values as the applicator. Fields absent from the desired object, status, and server-populated metadata are ignored,
+// such that only the desired object's spec (or equivalent top level fields), labels, and annotations are compared.
+func DetectDrift(
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	parent ResourceManagerObject,
+	desired []client.Object,
+) ([]DriftEntry, error) {
+	var drifted []DriftEntry
+
+	for _, d := range desired {
+		ref, err := meta.TypedObjectRefFromObject(d, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("getting typed object ref for %T %s: %w", d, client.ObjectKeyFromObject(d), err)
+		}
+
+		live, err := meta.NewObjectForGVK(scheme, ref.GroupVersionKind())
+		if err != nil {
+			return nil, fmt.Errorf("constructing new %s: %w", ref.GroupVersionKind(), err)
+		}
+
+		if err := c.Get(ctx, ref.ObjectKey(), live); err != nil {
+			if k8serrors.IsNotFound(err) {
+				drifted = append(drifted, DriftEntry{Ref: *ref, Reason: DriftReasonMissing})
+				continue
+			}
+			return nil, fmt.Errorf("getting managed resource %s of %s: %w", ref, client.ObjectKeyFromObject(parent), err)
+		}
+
+		desiredFields, err := comparableFields(d)
+		if err != nil {
+			return nil, fmt.Errorf("converting desired %s: %w", ref, err)
+		}
+		liveFields, err := comparableFields(live)
+		if err != nil {
+			return nil, fmt.Errorf("converting live %s: %w", ref, err)
+		}
+
+		if !io.SemanticDeepDerivative(desiredFields, liveFields) {
+			drifted = append(drifted, DriftEntry{Ref: *ref, Reason: DriftReasonModified})
+		}
+	}
+
+	return drifted, nil
+}
+
+// comparableFields returns the object's fields compared for drift, i.e. all fields except for type metadata, status,
+// and metadata other than labels and annotations.
+func comparableFields(obj client.Object) (map[string]interface{}, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(u, "apiVersion")
+	delete(u, "kind")
+	delete(u, "status")
+
+	labels, _, _ := unstructured.NestedFieldNoCopy(u, "metadata", "labels")
+	annotations, _, _ := unstructured.NestedFieldNoCopy(u, "metadata", "annotations")
+	u["metadata"] = map[string]interface{}{
+		"labels":      labels,
+		"annotations": annotations,
+	}
+
+	return u, nil
+}
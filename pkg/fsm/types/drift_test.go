@@ -0,0 +1,75 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	intscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
+	testv1alpha1 "github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+func Test_DetectDrift(t *testing.T) {
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	newConfigMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "test"},
+			},
+			Data: data,
+		}
+	}
+
+	matching := newConfigMap("matching", map[string]string{"foo": "bar"})
+	drifted := newConfigMap("drifted", map[string]string{"foo": "bar"})
+	missing := newConfigMap("missing", map[string]string{"foo": "bar"})
+
+	liveMatching := matching.DeepCopy()
+	liveMatching.Labels["other"] = "other" // set by another actor
+	liveDrifted := drifted.DeepCopy()
+	liveDrifted.Data["foo"] = "changed"
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deployment",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](3),
+		},
+	}
+	// desired replicas with a different numeric type than the live object
+	desiredDeployment := &unstructured.Unstructured{}
+	desiredDeployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	desiredDeployment.SetName(deployment.Name)
+	desiredDeployment.SetNamespace(deployment.Namespace)
+	assert.NoError(t, unstructured.SetNestedField(desiredDeployment.Object, float64(3), "spec", "replicas"))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(liveMatching, liveDrifted, deployment).Build()
+
+	parent := &testv1alpha1.TestClaimed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "parent",
+		},
+	}
+
+	actual, err := DetectDrift(context.Background(), c, scheme, parent, []client.Object{matching, drifted, missing, desiredDeployment})
+	assert.NoError(t, err)
+	assert.Equal(t, []DriftEntry{
+		{Ref: *meta.MustTypedObjectRefFromObject(drifted, scheme), Reason: DriftReasonModified},
+		{Ref: *meta.MustTypedObjectRefFromObject(missing, scheme), Reason: DriftReasonMissing},
+	}, actual)
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileFilter restricts reconciliation to objects matching all of its criteria, e.g. to shard objects across
+// controller deployments by tenant. The zero value matches all objects.
+type ReconcileFilter struct {
+	// Namespaces, if not empty, restricts reconciliation to objects in the listed namespaces.
+	Namespaces []string
+	// Selector, if not nil, restricts reconciliation to objects whose labels match the selector.
+	Selector labels.Selector
+}
+
+// Matches returns true if the object should be reconciled.
+func (f ReconcileFilter) Matches(obj client.Object) bool {
+	if len(f.Namespaces) > 0 && !slices.Contains(f.Namespaces, obj.GetNamespace()) {
+		return false
+	}
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	return true
+}
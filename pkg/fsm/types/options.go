@@ -1,10 +1,18 @@
 package types
 
 import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/reddit/achilles-sdk-api/api"
 	"github.com/reddit/achilles-sdk-api/pkg/types"
+	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/logging"
 )
 
 // ReconcilerOptions are options for tuning the behavior of an FSM reconciler.
@@ -21,8 +29,118 @@ type ReconcilerOptions[T any, Obj types.FSMResource[T]] struct {
 	// provided by default.
 	DisableReadyCondition bool
 
+	// ReadyConditionType, if not empty, is the type of the top-level status condition rolling up all other status conditions,
+	// for APIs whose canonical readiness condition isn't "Ready" (e.g. "Available"). Defaults to "Ready".
+	ReadyConditionType api.ConditionType
+
+	// PropagateChildReadiness, if true, appends the reason and message of the most severe non-ready managed resource
+	// to the message of the "Ready" status condition when the object isn't ready. Only managed resources with a "Ready"
+	// status condition are considered. For nested managed resources, the root cause is propagated.
+	PropagateChildReadiness bool
+
 	// MetricsOptions are options for tuning the metrics instrumentation of this reconciler.
 	MetricsOptions MetricsOptions
+
+	// RedditLabelKeys, if a managed resource type is present, restricts the standard Reddit labels applied to
+	// managed resources of that type to the listed label keys. An empty list disables Reddit labels for that type.
+	// Owner references are applied regardless.
+	RedditLabelKeys map[schema.GroupVersionKind][]string
+
+	// ManagedResourceRefGracePeriod, if positive, is the duration for which a managed resource ref is retained in status
+	// after its object is first observed missing (without being explicitly deleted by the controller).
+	// The object is requeued once the grace period elapses, so that the refs are pruned on time.
+	// If zero, refs for missing objects are pruned immediately.
+	ManagedResourceRefGracePeriod time.Duration
+
+	// MinRequeueInterval, if positive, is the minimum duration before requeuing for requeue results with a specified
+	// duration. Shorter (or negative) durations are raised to this interval. Requeues with exponential backoff are unaffected.
+	MinRequeueInterval time.Duration
+
+	// MaxConditionMessageLength, if positive, is the maximum length, in characters, of status condition messages written
+	// by the reconciler. Longer messages are truncated with an ellipsis (see status.TruncateMessage).
+	MaxConditionMessageLength int
+
+	// MaxStatesPerReconcile, if positive, is the maximum number of states executed in a single reconcile.
+	// Once reached, the reconciler requeues immediately and resumes from the next state, bounding reconcile latency
+	// and load on the kube-apiserver for FSMs with many states.
+	MaxStatesPerReconcile int
+
+	// TraceIDsFunc, if not nil, returns the trace and span IDs of the reconcile context, which are added to all logs
+	// of the reconcile alongside the reconcile ID, correlating logs with traces when tracing is enabled.
+	TraceIDsFunc logging.TraceIDsFunc
+
+	// ReconcileFilter, if not nil, restricts reconciliation to objects matching the filter. Objects that don't match are
+	// skipped, and their metrics deleted, so objects that stop matching the filter are no longer reported.
+	ReconcileFilter *ReconcileFilter
+
+	// TransientErrorGracePeriod, if positive, is the duration for which reconciles failing with transient errors
+	// (e.g. DNS or connection failures reaching the kube-apiserver) leave the object's status conditions unchanged.
+	// Transient errors persisting beyond the grace period are surfaced on status conditions like any other error.
+	// If zero, transient errors are surfaced immediately.
+	TransientErrorGracePeriod time.Duration
+
+	// WithoutDefaultOwnerRefs, if true, prevents the controller reference to the reconciled object from being set by
+	// default on managed resources. Owner references explicitly requested for an output (e.g. through io.WithOwnerRef) are still set.
+	WithoutDefaultOwnerRefs bool
+
+	// VersionAnnotation, if not empty, is the version of the controller (e.g. its build version) recorded on the reconciled
+	// object and its applied outputs in the io.ReconciledByVersionAnnotationKey annotation. For outputs, the annotation
+	// doesn't trigger patches on its own, see io.WithVersionAnnotation.
+	VersionAnnotation string
+
+	// ShadowApplicator, if not nil, is the applicator to which outputs are additionally applied and deleted on a best-effort
+	// basis, e.g. for mirroring managed resources to a secondary cluster. Failures are logged and don't fail the reconcile.
+	ShadowApplicator *io.ClientApplicator
+
+	// DefaultReadyFuncs are custom resource readiness checks applied by GetUnreadyResources (and therefore
+	// TransitionWhenReady) for all transitions of this reconciler, in addition to those passed per call.
+	DefaultReadyFuncs []CustomResourceReadyFunc
+
+	// ReadyEventRecorder, if not nil, records an event whenever the object's ready condition transitions to True.
+	// Reconciles leaving the object ready don't record further events.
+	ReadyEventRecorder ReadyEventRecorder
+
+	// RequeueEventRecorder, if not nil, records the Warning events of results returned by RequeueResultWithEvent.
+	RequeueEventRecorder RequeueEventRecorder
+
+	// PauseSwitch, if not nil, pauses reconciliation while true. Reconciles of a paused controller complete immediately
+	// without reading or changing any object, and resume once the switch is set to false.
+	PauseSwitch *atomic.Bool
+
+	// ReconcileReadCache, if true, memoizes Gets through io.ClientApplicators for the duration of each reconcile (see
+	// io.NewReadCacheContext), so that objects read in multiple states are only fetched once.
+	ReconcileReadCache bool
+
+	// ReconcileLoopThreshold is the number of consecutive reconciles, each leaving the object's status unchanged without
+	// requesting a requeue, after which the object is suspected to be in a reconcile loop. Suspected loops are logged and
+	// reported by the "achilles_suspected_reconcile_loop" metric. Defaults to 10 if zero, negative values disable detection.
+	ReconcileLoopThreshold int
+
+	// ReconcileLoopWindow is the maximum interval between consecutive reconciles counted towards ReconcileLoopThreshold.
+	// Defaults to one second if zero.
+	ReconcileLoopWindow time.Duration
+
+	// StatusConditionsThreshold is the number of status conditions above which the object's conditions are suspected to
+	// grow without bound, e.g. due to a bug adding conditions of ever-changing types. Exceeding objects are logged and
+	// reported by the "achilles_status_size_exceeded" metric. Defaults to 50 if zero, negative values disable the check.
+	StatusConditionsThreshold int
+
+	// StatusManagedResourcesThreshold is the number of managed resource refs above which the object's refs are suspected
+	// to grow without bound, reported like StatusConditionsThreshold. Controllers legitimately managing many resources
+	// should raise it. Defaults to 1000 if zero, negative values disable the check.
+	StatusManagedResourcesThreshold int
+}
+
+// ReadyEventRecorder records events for objects becoming ready, implemented by events.EventRecorder.
+type ReadyEventRecorder interface {
+	// RecordReady records a ready event for the given object, message defaults to "Object is ready" if empty.
+	RecordReady(obj client.Object, message string)
+}
+
+// RequeueEventRecorder records warning events for requeue results, implemented by events.EventRecorder.
+type RequeueEventRecorder interface {
+	// RecordWarning records a warning event for the given object.
+	RecordWarning(obj client.Object, reason string, message string)
 }
 
 // AchillesMetrics represents various achilles metrics.
@@ -47,6 +165,30 @@ const (
 	AchillesSuspend = "ResourceSuspend"
 	// AchillesProcessingDuration
 	AchillesProcessingDuration = "ProcessingDuration"
+	// AchillesPendingChildDeletions number of child resources pending foreground deletion.
+	AchillesPendingChildDeletions = "PendingChildDeletions"
+	// AchillesReconcileResult number of reconciles per result type.
+	AchillesReconcileResult = "ReconcileResult"
+	// AchillesSuspectedReconcileLoop whether the resource is suspected to be in a reconcile loop.
+	AchillesSuspectedReconcileLoop = "SuspectedReconcileLoop"
+	// AchillesManagedResources number of resources managed by the resource.
+	AchillesManagedResources = "ManagedResources"
+	// AchillesActiveReconcileDuration duration of reconciles, excluding time spent waiting for requeues.
+	AchillesActiveReconcileDuration = "ActiveReconcileDuration"
+	// AchillesConditionAge time elapsed since the last transition of status conditions.
+	AchillesConditionAge = "ConditionAge"
+	// AchillesControllerPaused whether the controller's reconciliation is paused.
+	AchillesControllerPaused = "ControllerPaused"
+	// AchillesTriggerCoalesced number of triggers coalesced with a request already waiting in the workqueue.
+	AchillesTriggerCoalesced = "TriggerCoalesced"
+	// AchillesStatusSizeExceeded whether status fields of the resource exceed their size threshold.
+	AchillesStatusSizeExceeded = "StatusSizeExceeded"
+	// AchillesReconcileSkipped number of reconciles skipped by a state transition.
+	AchillesReconcileSkipped = "ReconcileSkipped"
+	// AchillesProcessingStartTimesSize number of processing start times tracked for computing processing durations.
+	AchillesProcessingStartTimesSize = "ProcessingStartTimesSize"
+	// AchillesManagedTypeUnservable whether a type managed by the controller is no longer served by the kube-apiserver.
+	AchillesManagedTypeUnservable = "ManagedTypeUnservable"
 )
 
 // MetricsOptions are options for tuning the metrics instrumentation of this reconciler.
@@ -55,6 +197,13 @@ type MetricsOptions struct {
 	ConditionTypes []api.ConditionType
 	// DisableMetrics is a list of metrics to be disabled.
 	DisableMetrics []AchillesMetrics
+	// CustomMetricsRegisterer, if set, is the registerer for user-defined metrics recorded from transitions
+	// through metrics.CustomMetricsFromContext. If nil, recording custom metrics is a no-op.
+	CustomMetricsRegisterer prometheus.Registerer
+	// DeletionGracePeriod, if positive, is the duration for which the metrics of deleted objects are retained before
+	// deletion, so that metrics of objects recreated within the grace period don't reset. Metrics recorded again
+	// within the grace period aren't deleted. Only applies to metrics constructed with metrics.MustMakeMetricsWithOptions.
+	DeletionGracePeriod time.Duration
 }
 
 // IsMetricDisabled check if metric is disabled for recording.
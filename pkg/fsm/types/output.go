@@ -2,7 +2,10 @@ package types
 
 import (
 	"fmt"
+	"strings"
 
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -25,6 +28,21 @@ type OutputSet struct {
 	deleted *sets.ObjectSet
 
 	applyOpts map[string][]io.ApplyOption
+
+	// tracks objects of the reconciled type that should be requeued after outputs are applied
+	requeueRefs []api.TypedObjectRef
+
+	// tracks mutations of the reconciled object's metadata
+	selfPatches []func(client.Object)
+
+	// tracks types of controller-owned objects to prune
+	prunes []Prune
+}
+
+// Prune is a request to delete the controller-owned objects of a type for which Keep returns false.
+type Prune struct {
+	GVK  schema.GroupVersionKind
+	Keep func(client.Object) bool
 }
 
 // OutputObject is a tuple of an object and an optional list of client apply options.
@@ -50,6 +68,34 @@ func (s *OutputSet) Apply(o client.Object, applyOpts ...io.ApplyOption) {
 	s.applyOpts[s.key(o)] = applyOpts
 }
 
+// ApplyWhen signals creation or update of an object to the server, with optional client apply options,
+// only if cond returns true. cond is evaluated once, at staging time (i.e. when ApplyWhen is invoked), not when
+// outputs are applied to the server. Returns true if the object was staged for apply.
+// This is useful for gating outputs on the readiness of other resources, for example:
+//
+//	out.ApplyWhen(childB, func() bool { return status.ResourceReady(childA) })
+func (s *OutputSet) ApplyWhen(o client.Object, cond func() bool, applyOpts ...io.ApplyOption) bool {
+	if !cond() {
+		return false
+	}
+	s.Apply(o, applyOpts...)
+	return true
+}
+
+// ApplyExclusive signals creation or update of chosen and deletion of the alternatives, for mutually exclusive objects,
+// e.g. where a spec toggle chooses between managing a Deployment or a StatefulSet. Alternatives referring to chosen are
+// ignored, so callers may pass the refs of all candidates.
+func (s *OutputSet) ApplyExclusive(chosen client.Object, alternatives ...api.TypedObjectRef) {
+	chosenRef := meta.MustTypedObjectRefFromObject(chosen, s.scheme)
+	for _, ref := range alternatives {
+		if ref.GroupVersionKind() == chosenRef.GroupVersionKind() && ref.ObjectKey() == chosenRef.ObjectKey() {
+			continue
+		}
+		s.DeleteByRef(ref)
+	}
+	s.Apply(chosen)
+}
+
 // ApplyAll is equivalent to calling Apply(obj) for all supplied objects.
 func (s *OutputSet) ApplyAll(objs ...client.Object) {
 	for _, o := range objs {
@@ -57,6 +103,39 @@ func (s *OutputSet) ApplyAll(objs ...client.Object) {
 	}
 }
 
+// Merge merges the outputs of other into this OutputSet, for composing sub-transitions that each build their own OutputSet.
+// Applied and deleted objects, prunes, requeue refs, and self patches are unioned.
+// An object applied in one set and deleted in the other is a conflict, in which case an error is returned and this
+// OutputSet is left unmodified. An object applied in both sets resolves to that of other, along with its apply options,
+// and a warning is logged if the two objects differ.
+func (s *OutputSet) Merge(log *zap.SugaredLogger, other *OutputSet) error {
+	var conflicts []string
+	for _, o := range s.applied.Intersection(other.deleted).List() {
+		conflicts = append(conflicts, s.key(o))
+	}
+	for _, o := range s.deleted.Intersection(other.applied).List() {
+		conflicts = append(conflicts, s.key(o))
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("objects both applied and deleted: %s", strings.Join(conflicts, ", "))
+	}
+
+	for _, o := range other.ListAppliedOutputs() {
+		if existing := s.applied.GetByRef(*meta.MustTypedObjectRefFromObject(o.Object, s.scheme)); existing != nil && !equality.Semantic.DeepEqual(existing, o.Object) {
+			log.Warnf("merged output %s applied with different content, using the last applied object", s.key(o.Object))
+		}
+		s.Apply(o.Object, o.ApplyOpts...)
+	}
+	for _, o := range other.ListDeleted() {
+		s.Delete(o)
+	}
+	s.prunes = append(s.prunes, other.prunes...)
+	s.requeueRefs = append(s.requeueRefs, other.requeueRefs...)
+	s.selfPatches = append(s.selfPatches, other.selfPatches...)
+
+	return nil
+}
+
 // GetApplied returns the set of objects to be applied.
 func (s *OutputSet) GetApplied() *sets.ObjectSet {
 	return s.applied
@@ -85,6 +164,7 @@ func (s *OutputSet) DeleteAll(objs ...client.Object) {
 }
 
 // DeleteByRef is the same as Delete, but takes an api.TypedObjectRef instead of an object.
+// As with Delete, objects that don't exist are ignored, so the deletion only takes effect if the object exists.
 func (s *OutputSet) DeleteByRef(typedObjRef api.TypedObjectRef) {
 	apiVersion, kind := typedObjRef.GroupVersionKind().ToAPIVersionAndKind()
 	objMeta := &v1.PartialObjectMetadata{
@@ -100,6 +180,44 @@ func (s *OutputSet) DeleteByRef(typedObjRef api.TypedObjectRef) {
 	s.Delete(objMeta)
 }
 
+// Prune signals deletion of all objects of the given type controlled by the reconciled object for which keep returns false,
+// e.g. for deleting objects that are no longer produced. Objects are listed when outputs are applied, and objects applied
+// in the same OutputSet are never pruned. Objects that aren't controlled by the reconciled object are never pruned.
+// For namespace-scoped reconciled objects, only objects in the same namespace are considered.
+func (s *OutputSet) Prune(gvk schema.GroupVersionKind, keep func(client.Object) bool) {
+	s.prunes = append(s.prunes, Prune{GVK: gvk, Keep: keep})
+}
+
+// ListPrunes returns the prune requests signaled through Prune.
+func (s *OutputSet) ListPrunes() []Prune {
+	return s.prunes
+}
+
+// RequeueRef signals that the referenced object should be reconciled once this state's outputs are applied,
+// rather than waiting for a watch event to propagate. This is useful for transitions that mutate a sibling object.
+// Only objects of the reconciled type can be requeued. Requeueing the object being reconciled is a no-op.
+func (s *OutputSet) RequeueRef(ref api.TypedObjectRef) {
+	s.requeueRefs = append(s.requeueRefs, ref)
+}
+
+// PatchSelf signals a mutation of the reconciled object's labels and annotations, applied to the server as a patch of
+// the object's metadata after the state's other outputs are applied. Mutations of other fields are ignored.
+// Mutations are applied in the order in which they're signaled, so conflicting mutations across states resolve to
+// that of the last state.
+func (s *OutputSet) PatchSelf(mutate func(obj client.Object)) {
+	s.selfPatches = append(s.selfPatches, mutate)
+}
+
+// ListSelfPatches returns the mutations of the reconciled object's metadata signaled through PatchSelf.
+func (s *OutputSet) ListSelfPatches() []func(client.Object) {
+	return s.selfPatches
+}
+
+// ListRequeueRefs returns the references of objects to requeue.
+func (s *OutputSet) ListRequeueRefs() []api.TypedObjectRef {
+	return s.requeueRefs
+}
+
 // ListAppliedOutputs lists all objects from the output set along with their associated apply options.
 func (s *OutputSet) ListAppliedOutputs() []OutputObject {
 	var outputs []OutputObject
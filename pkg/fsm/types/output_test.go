@@ -5,11 +5,15 @@ import (
 	"unsafe"
 
 	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/reddit/achilles-sdk-api/api"
 	"github.com/reddit/achilles-sdk/pkg/internal/scheme"
 	"github.com/reddit/achilles-sdk/pkg/io"
+	"github.com/reddit/achilles-sdk/pkg/meta"
 	"github.com/reddit/achilles-sdk/pkg/sets"
 )
 
@@ -77,6 +81,162 @@ func Test_OutputSet(t *testing.T) {
 	}
 }
 
+func Test_OutputSet_ApplyWhen(t *testing.T) {
+	scheme, err := scheme.NewScheme()
+	if err != nil {
+		t.Fatalf("building scheme: %s", err)
+	}
+	outputSet := NewOutputSet(scheme)
+
+	gated := cm("gated", "ns")
+	ungated := cm("ungated", "ns")
+	applyOpts := []io.ApplyOption{io.AsUpdate()}
+
+	var evaluated int
+	if outputSet.ApplyWhen(gated, func() bool { evaluated++; return false }) {
+		t.Errorf("expected gated object to not be staged")
+	}
+	if !outputSet.ApplyWhen(ungated, func() bool { evaluated++; return true }, applyOpts...) {
+		t.Errorf("expected ungated object to be staged")
+	}
+
+	// predicates are evaluated exactly once at staging time
+	if diff := cmp.Diff(evaluated, 2); diff != "" {
+		t.Errorf("unexpected number of predicate evaluations: (-got +want)\n%s", diff)
+	}
+
+	if outputSet.applied.Has(gated) {
+		t.Errorf("unexpected existence of gated object in applied set")
+	}
+	if !outputSet.applied.Has(ungated) {
+		t.Errorf("expected existence of ungated object in applied set")
+	}
+	if !applyOptsEqual(outputSet.applyOpts[outputSet.key(ungated)], applyOpts) {
+		t.Errorf("unexpected apply options for ungated object")
+	}
+}
+
+func Test_OutputSet_ApplyExclusive(t *testing.T) {
+	scheme, err := scheme.NewScheme()
+	if err != nil {
+		t.Fatalf("building scheme: %s", err)
+	}
+	outputSet := NewOutputSet(scheme)
+
+	chosen := cm("chosen", "ns")
+	alternative := cm("alternative", "ns")
+	otherType := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "chosen", Namespace: "ns"}}
+
+	outputSet.ApplyExclusive(
+		chosen,
+		*meta.MustTypedObjectRefFromObject(alternative, scheme),
+		*meta.MustTypedObjectRefFromObject(chosen, scheme),
+		*meta.MustTypedObjectRefFromObject(otherType, scheme),
+	)
+
+	if !outputSet.applied.Has(chosen) {
+		t.Errorf("expected existence of chosen object in applied set")
+	}
+	if outputSet.deleted.Has(chosen) {
+		t.Errorf("unexpected existence of chosen object in deleted set")
+	}
+	if diff := cmp.Diff(outputSet.applied.Len(), 1); diff != "" {
+		t.Errorf("unexpected number of applied objects: (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(outputSet.GetDeleted().ListRefs(), []api.TypedObjectRef{
+		*meta.MustTypedObjectRefFromObject(alternative, scheme),
+		*meta.MustTypedObjectRefFromObject(otherType, scheme),
+	}); diff != "" {
+		t.Errorf("unexpected deleted objects: (-got +want)\n%s", diff)
+	}
+}
+
+func Test_OutputSet_Merge(t *testing.T) {
+	scheme, err := scheme.NewScheme()
+	if err != nil {
+		t.Fatalf("building scheme: %s", err)
+	}
+
+	t.Run("union", func(t *testing.T) {
+		observedZapCore, observedLogs := observer.New(zap.WarnLevel)
+		log := zap.New(observedZapCore).Sugar()
+
+		shared := cm("shared", "ns")
+		sharedOverride := cm("shared", "ns")
+		sharedOverride.Data = map[string]string{"key": "value"}
+		applyOpts := []io.ApplyOption{io.AsUpdate()}
+
+		outputSet := NewOutputSet(scheme)
+		outputSet.Apply(cm("a", "ns"))
+		outputSet.Apply(shared)
+		outputSet.Delete(cm("stale-a", "ns"))
+		outputSet.RequeueRef(*meta.MustTypedObjectRefFromObject(cm("a", "ns"), scheme))
+
+		other := NewOutputSet(scheme)
+		other.Apply(cm("b", "ns"))
+		other.Apply(sharedOverride, applyOpts...)
+		other.Delete(cm("stale-b", "ns"))
+		other.Prune(corev1.SchemeGroupVersion.WithKind("ConfigMap"), nil)
+
+		if err := outputSet.Merge(log, other); err != nil {
+			t.Fatalf("merging output sets: %s", err)
+		}
+
+		if !outputSet.applied.Equal(sets.NewObjectSet(scheme, cm("a", "ns"), cm("b", "ns"), shared)) {
+			t.Errorf("unexpected applied set %v", outputSet.applied.ListRefs())
+		}
+		if !outputSet.deleted.Equal(sets.NewObjectSet(scheme, cm("stale-a", "ns"), cm("stale-b", "ns"))) {
+			t.Errorf("unexpected deleted set %v", outputSet.deleted.ListRefs())
+		}
+		if diff := cmp.Diff(len(outputSet.ListRequeueRefs()), 1); diff != "" {
+			t.Errorf("unexpected number of requeue refs: (-got +want)\n%s", diff)
+		}
+		if diff := cmp.Diff(len(outputSet.ListPrunes()), 1); diff != "" {
+			t.Errorf("unexpected number of prunes: (-got +want)\n%s", diff)
+		}
+
+		// the object applied last wins
+		if actual := sets.Get(outputSet.applied, shared); actual != sharedOverride {
+			t.Errorf("expected object applied in other to win, got %v", actual)
+		}
+		if !applyOptsEqual(outputSet.applyOpts[outputSet.key(shared)], applyOpts) {
+			t.Errorf("unexpected apply options for shared object")
+		}
+		if diff := cmp.Diff(observedLogs.Len(), 1); diff != "" {
+			t.Errorf("unexpected number of warnings: (-got +want)\n%s", diff)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		observedZapCore, observedLogs := observer.New(zap.WarnLevel)
+		log := zap.New(observedZapCore).Sugar()
+
+		outputSet := NewOutputSet(scheme)
+		outputSet.Apply(cm("a", "ns"))
+		outputSet.Delete(cm("b", "ns"))
+
+		other := NewOutputSet(scheme)
+		other.Delete(cm("a", "ns"))
+		other.Apply(cm("b", "ns"))
+		other.Apply(cm("c", "ns"))
+
+		if err := outputSet.Merge(log, other); err == nil {
+			t.Fatalf("expected error merging conflicting output sets")
+		}
+
+		// unmodified on conflict
+		if !outputSet.applied.Equal(sets.NewObjectSet(scheme, cm("a", "ns"))) {
+			t.Errorf("unexpected applied set %v", outputSet.applied.ListRefs())
+		}
+		if !outputSet.deleted.Equal(sets.NewObjectSet(scheme, cm("b", "ns"))) {
+			t.Errorf("unexpected deleted set %v", outputSet.deleted.ListRefs())
+		}
+		if diff := cmp.Diff(observedLogs.Len(), 0); diff != "" {
+			t.Errorf("unexpected number of warnings: (-got +want)\n%s", diff)
+		}
+	})
+}
+
 func cm(name, namespace string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
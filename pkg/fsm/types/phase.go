@@ -0,0 +1,51 @@
+package types
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+// Phase is the lifecycle phase of a reconciled object.
+type Phase string
+
+const (
+	// PhaseCreating is the phase of objects whose status hasn't yet been recorded by their controller.
+	PhaseCreating Phase = "Creating"
+	// PhaseUpdating is the phase of existing objects, whether or not their spec changed since they were last reconciled.
+	PhaseUpdating Phase = "Updating"
+	// PhaseDeleting is the phase of objects marked for deletion.
+	PhaseDeleting Phase = "Deleting"
+)
+
+// ConditionedObject is a k8s resource with status conditions.
+type ConditionedObject interface {
+	client.Object
+	api.Conditioned
+}
+
+// ReconcilePhase returns the lifecycle phase of the reconciled object, for transitions that behave differently when
+// creating, updating, or deleting it.
+// Objects marked for deletion are Deleting. Otherwise, objects are Creating until any of their status conditions has
+// observed a generation, i.e. until their first reconcile records status, and Updating thereafter. In particular, an
+// object whose first reconcile failed is Updating on subsequent reconciles, since its status records the failure.
+func ReconcilePhase(obj ConditionedObject) Phase {
+	if meta.WasDeleted(obj) {
+		return PhaseDeleting
+	}
+	if conditionsObservedGeneration(obj) == 0 {
+		return PhaseCreating
+	}
+	return PhaseUpdating
+}
+
+// conditionsObservedGeneration returns the most recent generation observed by any of the object's status conditions,
+// or zero if none have observed a generation.
+func conditionsObservedGeneration(obj api.Conditioned) int64 {
+	var observed int64
+	for _, c := range obj.GetConditions() {
+		observed = max(observed, c.ObservedGeneration)
+	}
+	return observed
+}
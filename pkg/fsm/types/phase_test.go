@@ -0,0 +1,78 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/reddit/achilles-sdk-api/api"
+	testv1alpha1 "github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+func Test_ReconcilePhase(t *testing.T) {
+	now := metav1.Now()
+
+	claim := func(generation int64, deletionTimestamp *metav1.Time, conditions ...api.Condition) *testv1alpha1.TestClaim {
+		obj := &testv1alpha1.TestClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foobar",
+				Generation:        generation,
+				DeletionTimestamp: deletionTimestamp,
+			},
+		}
+		obj.Status.Conditions = conditions
+		return obj
+	}
+
+	tcs := []struct {
+		name     string
+		obj      *testv1alpha1.TestClaim
+		expected Phase
+	}{
+		{
+			name:     "first reconcile",
+			obj:      claim(1, nil),
+			expected: PhaseCreating,
+		},
+		{
+			name:     "first reconcile after spec change",
+			obj:      claim(3, nil),
+			expected: PhaseCreating,
+		},
+		{
+			name:     "conditions without observed generation",
+			obj:      claim(1, nil, api.Condition{Type: api.TypeReady}),
+			expected: PhaseCreating,
+		},
+		{
+			name:     "steady state",
+			obj:      claim(2, nil, api.Condition{Type: api.TypeReady, ObservedGeneration: 2}),
+			expected: PhaseUpdating,
+		},
+		{
+			name: "spec changed",
+			obj: claim(3, nil,
+				api.Condition{Type: api.TypeReady, ObservedGeneration: 2},
+				api.Condition{Type: "State", ObservedGeneration: 1},
+			),
+			expected: PhaseUpdating,
+		},
+		{
+			name:     "deleted before first reconcile",
+			obj:      claim(1, &now),
+			expected: PhaseDeleting,
+		},
+		{
+			name:     "deleted",
+			obj:      claim(2, &now, api.Condition{Type: api.TypeReady, ObservedGeneration: 2}),
+			expected: PhaseDeleting,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ReconcilePhase(tc.obj))
+		})
+	}
+}
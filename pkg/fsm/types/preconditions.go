@@ -0,0 +1,57 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Precondition is a check that must be satisfied before a state's transition is executed.
+type Precondition[T client.Object] struct {
+	// Reason is the status condition reason reported if the precondition isn't satisfied.
+	Reason string
+
+	// Check returns true if the precondition is satisfied. Otherwise, it returns false along with a message
+	// describing the unsatisfied precondition. A non-nil error fails the state with an error result.
+	Check func(ctx context.Context, obj T) (satisfied bool, msg string, err error)
+
+	// RequeueAfter is the duration to wait before requeueing if the precondition isn't satisfied.
+	// If zero, the requeue is performed with exponential backoff.
+	RequeueAfter time.Duration
+}
+
+// result returns the Result for an unsatisfied precondition.
+func (p Precondition[T]) result(msg string) Result {
+	if p.RequeueAfter > 0 {
+		return RequeueResultWithReason(msg, p.Reason, p.RequeueAfter)
+	}
+	return RequeueResultWithReasonAndBackoff(msg, p.Reason)
+}
+
+// WithPreconditions returns a copy of the state whose transition first evaluates the preconditions in order.
+// The first unsatisfied precondition requeues the reconciler with its own reason and message, and the state's transition
+// is only executed once all preconditions are satisfied.
+func WithPreconditions[T client.Object](state *State[T], preconditions ...Precondition[T]) *State[T] {
+	s := *state
+	transition := state.Transition
+
+	s.Transition = func(ctx context.Context, obj T, out *OutputSet) (*State[T], Result) {
+		for _, p := range preconditions {
+			satisfied, msg, err := p.Check(ctx, obj)
+			if err != nil {
+				return nil, ErrorResultWithReason(err, p.Reason)
+			}
+			if !satisfied {
+				return nil, p.result(msg)
+			}
+		}
+
+		if transition == nil {
+			return nil, DoneResult()
+		}
+		return transition(ctx, obj, out)
+	}
+
+	return &s
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	testv1alpha1 "github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+func Test_WithPreconditions(t *testing.T) {
+	var transitioned bool
+	state := &State[*testv1alpha1.TestClaimed]{
+		Name: "state",
+		Transition: func(ctx context.Context, obj *testv1alpha1.TestClaimed, out *OutputSet) (*State[*testv1alpha1.TestClaimed], Result) {
+			transitioned = true
+			return successState, DoneResult()
+		},
+	}
+
+	hasAnnotation := func(key string) Precondition[*testv1alpha1.TestClaimed] {
+		return Precondition[*testv1alpha1.TestClaimed]{
+			Reason: "MissingAnnotation",
+			Check: func(_ context.Context, obj *testv1alpha1.TestClaimed) (bool, string, error) {
+				if _, ok := obj.GetAnnotations()[key]; !ok {
+					return false, "missing annotation " + key, nil
+				}
+				return true, "", nil
+			},
+			RequeueAfter: 5 * time.Second,
+		}
+	}
+	hasLabel := func(key string) Precondition[*testv1alpha1.TestClaimed] {
+		return Precondition[*testv1alpha1.TestClaimed]{
+			Reason: "MissingLabel",
+			Check: func(_ context.Context, obj *testv1alpha1.TestClaimed) (bool, string, error) {
+				if _, ok := obj.GetLabels()[key]; !ok {
+					return false, "missing label " + key, nil
+				}
+				return true, "", nil
+			},
+		}
+	}
+	failing := Precondition[*testv1alpha1.TestClaimed]{
+		Reason: "CheckFailed",
+		Check: func(_ context.Context, _ *testv1alpha1.TestClaimed) (bool, string, error) {
+			return false, "", errors.New("check failed")
+		},
+	}
+
+	tcs := []struct {
+		name                 string
+		preconditions        []Precondition[*testv1alpha1.TestClaimed]
+		annotations          map[string]string
+		labels               map[string]string
+		expectedNextState    *State[*testv1alpha1.TestClaimed]
+		expectedResult       Result
+		expectedTransitioned bool
+	}{
+		{
+			name:                 "all preconditions satisfied",
+			preconditions:        []Precondition[*testv1alpha1.TestClaimed]{hasAnnotation("foo"), hasLabel("bar")},
+			annotations:          map[string]string{"foo": ""},
+			labels:               map[string]string{"bar": ""},
+			expectedNextState:    successState,
+			expectedResult:       DoneResult(),
+			expectedTransitioned: true,
+		},
+		{
+			name:              "earliest failing precondition is reported",
+			preconditions:     []Precondition[*testv1alpha1.TestClaimed]{hasAnnotation("foo"), hasLabel("bar")},
+			expectedNextState: nil,
+			expectedResult:    RequeueResultWithReason("missing annotation foo", "MissingAnnotation", 5*time.Second),
+		},
+		{
+			name:              "later failing precondition is reported once earlier preconditions are satisfied",
+			preconditions:     []Precondition[*testv1alpha1.TestClaimed]{hasAnnotation("foo"), hasLabel("bar")},
+			annotations:       map[string]string{"foo": ""},
+			expectedNextState: nil,
+			expectedResult:    RequeueResultWithReasonAndBackoff("missing label bar", "MissingLabel"),
+		},
+		{
+			name:              "precondition error",
+			preconditions:     []Precondition[*testv1alpha1.TestClaimed]{failing, hasAnnotation("foo")},
+			expectedNextState: nil,
+			expectedResult:    ErrorResultWithReason(errors.New("check failed"), "CheckFailed"),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			transitioned = false
+			obj := &testv1alpha1.TestClaimed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foobar",
+					Annotations: tc.annotations,
+					Labels:      tc.labels,
+				},
+			}
+
+			wrapped := WithPreconditions(state, tc.preconditions...)
+			actualNextState, actualResult := wrapped.Transition(context.Background(), obj, nil)
+
+			assert.Equal(t, state.Name, wrapped.Name)
+			assert.Equal(t, tc.expectedNextState, actualNextState)
+			assert.Equal(t, tc.expectedResult, actualResult)
+			assert.Equal(t, tc.expectedTransitioned, transitioned)
+		})
+	}
+}
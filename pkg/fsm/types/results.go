@@ -2,13 +2,16 @@ package types
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/reddit/achilles-sdk-api/api"
+	"github.com/reddit/achilles-sdk/pkg/status"
 )
 
 const (
@@ -24,7 +27,8 @@ const (
 // If requeueAfter is populated, the FSM will terminate and requeue with the specified duration.
 // If done is true, the FSM will continue to the next transition function.
 // The state's corresponding status condition's status will be False if err or requeueAfter is populated,
-// and True if done is true. The status condition's message will be populated with the err or requeueMsg string.
+// and True if done is true. The status condition's message will be populated with the err (sanitized through
+// status.SanitizeErrorMessage) or requeueMsg string.
 type Result struct {
 	// Done, if true and Err is nil, causes the FSM to progress to the next state. Else, the FSM will retry from the initial state.
 	Done bool
@@ -44,15 +48,25 @@ type Result struct {
 	// This is managed by the underlying reconciler.
 	RequeueAfterCompletionState string
 
+	// RecordEvent, if true, signals the reconciler to record a Warning event with the result's reason and requeue message.
+	// Consecutive events with the same reason are recorded once per object, see RequeueResultWithEvent.
+	RecordEvent bool
+
 	// CustomStatusCondition, if not nil and Done is true, is the status condition to set, regardless of the result type.
 	// This allows callers to customize the status condition message, status, and reason.
 	CustomStatusCondition *ResultStatusCondition
+
+	// Skipped, if true and Done is true, terminates the FSM without applying the state's outputs or updating the object's status.
+	// Reason is the reason for skipping. See SkipResult.
+	Skipped bool
 }
 
 type ResultStatusCondition struct {
 	Status  corev1.ConditionStatus
 	Reason  api.ConditionReason
 	Message string
+	// ObservedGeneration, if set, is the generation observed by the condition. Defaults to the reconciled object's generation.
+	ObservedGeneration int64
 }
 
 // Get resolves the Result into controller-runtime's reconcile.Result and error.
@@ -80,14 +94,27 @@ func (r Result) Get(log *zap.SugaredLogger) (reconcile.Result, error) {
 	return reconcile.Result{}, nil
 }
 
+// WithMinRequeueInterval returns the result with its requeue duration raised to the specified minimum interval,
+// guarding against tight requeue loops from durations computed as negative or near zero.
+// Requeues with exponential backoff (i.e. without a specified duration) and error results are returned unmodified.
+func (r Result) WithMinRequeueInterval(interval time.Duration) Result {
+	if r.Err == nil && r.RequeueMsg != "" && r.RequeueAfter != 0 && r.RequeueAfter < interval {
+		r.RequeueAfter = interval
+	}
+	return r
+}
+
 // GetMessageAndReason returns the message and reason for failed states.
+// Error messages are sanitized, and recognized errors default to a reason categorizing the error.
 func (r Result) GetMessageAndReason() (string, api.ConditionReason) {
 	var message, defaultReason string
 
 	// message
 	if r.Err != nil {
-		message = r.Err.Error()
-		defaultReason = DefaultErrorReason
+		message, defaultReason = status.SanitizeErrorMessage(r.Err)
+		if defaultReason == "" {
+			defaultReason = DefaultErrorReason
+		}
 	} else {
 		message = r.RequeueMsg + " (requeued)"
 		defaultReason = DefaultRequeueReason
@@ -152,6 +179,37 @@ func RequeueResultWithReason(msg string, reason string, requeueAfter time.Durati
 	}
 }
 
+// RequeueResultWithEvent is the same as RequeueResultWithReason, but additionally signals the reconciler to record a Warning
+// event on the reconciled object with the specified reason and msg. To avoid spamming events, an event is only recorded
+// if the object's previous reconcile didn't requeue with an event of the same reason.
+// Events are only recorded if the reconciler is configured with a ReconcilerOptions.RequeueEventRecorder, which is the
+// case for controllers set up through the FSM builder.
+func RequeueResultWithEvent(msg string, reason string, requeueAfter time.Duration) Result {
+	r := RequeueResultWithReason(msg, reason, requeueAfter)
+	r.RecordEvent = true
+	return r
+}
+
+// BranchResult returns the candidate state named chosen as the next state along with a done result, for transitions that
+// branch between several next states. Declaring all candidates in one place keeps the branches of complex transitions
+// readable, e.g.
+//
+//	return BranchResult(map[string]*State[T]{"create": createState, "update": updateState}, branch)
+//
+// A chosen name that isn't among the candidates is a programming error, which fails the transition with reason "InvalidBranch".
+func BranchResult[T client.Object](candidates map[string]*State[T], chosen string) (*State[T], Result) {
+	next, ok := candidates[chosen]
+	if !ok {
+		names := make([]string, 0, len(candidates))
+		for name := range candidates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, ErrorResultWithReason(fmt.Errorf("branch %q is not among candidates %v", chosen, names), "InvalidBranch")
+	}
+	return next, DoneResult()
+}
+
 // RequeueResult returns a new requeue result, which will trigger a requeue after the specified duration.
 // The message will be logged and surfaced as a status condition message on the reconciled object.
 func RequeueResult(msg string, requeueAfter time.Duration) Result {
@@ -169,6 +227,65 @@ func RequeueResultWithReasonAndBackoff(msg, reason string) Result {
 	return RequeueResultWithReason(msg, reason, 0)
 }
 
+// RequeueResultAtNextCron returns a new requeue result, which will trigger a requeue at the next occurrence of the cron
+// schedule spec, for transitions that act at specific wall-clock times, e.g. "0 2 * * *" for 02:00 every night.
+// spec is a standard five field cron spec ("minute hour day-of-month month day-of-week") or a descriptor such as "@daily",
+// evaluated in the local time zone.
+// An invalid spec is a programming error, which fails the transition with reason "InvalidCronSpec".
+func RequeueResultAtNextCron(spec string) Result {
+	return requeueResultAtNextCron(spec, time.Now())
+}
+
+func requeueResultAtNextCron(spec string, now time.Time) Result {
+	schedule, err := parseCron(spec)
+	if err != nil {
+		return ErrorResultWithReason(err, "InvalidCronSpec")
+	}
+	next, ok := schedule.next(now)
+	if !ok {
+		return ErrorResultWithReason(fmt.Errorf("cron spec %q has no occurrence within %d years", spec, cronSearchYears), "InvalidCronSpec")
+	}
+	return RequeueResult(fmt.Sprintf("waiting for next occurrence of %q at %s", spec, next.Format(time.RFC3339)), next.Sub(now))
+}
+
+// RequeueBeforeSoonest returns a result that requeues lead before the soonest of expiries, e.g. for renewing child
+// certificates before they expire. As with DoneAndRequeueAfterCompletion, the FSM proceeds to the next state and
+// requeues once all states are completed. The state's status condition remains True, with reason "RenewingBeforeExpiry".
+// Expiries that have passed or are within lead requeue immediately.
+// If there are no expiries, a done result is returned.
+func RequeueBeforeSoonest(expiries []time.Time, lead time.Duration) Result {
+	return requeueBeforeSoonest(expiries, lead, time.Now())
+}
+
+func requeueBeforeSoonest(expiries []time.Time, lead time.Duration, now time.Time) Result {
+	if len(expiries) == 0 {
+		return DoneResult()
+	}
+
+	soonest := expiries[0]
+	for _, expiry := range expiries[1:] {
+		if expiry.Before(soonest) {
+			soonest = expiry
+		}
+	}
+
+	requeueAfter := soonest.Add(-lead).Sub(now)
+	if requeueAfter <= 0 {
+		// a zero duration would requeue with exponential backoff
+		requeueAfter = time.Millisecond
+	}
+	msg := fmt.Sprintf("renewing %s before the soonest expiry at %s", lead, soonest.Format(time.RFC3339))
+	result := DoneAndRequeueAfterCompletion(msg, requeueAfter)
+	result.Reason = "RenewingBeforeExpiry"
+	// pending renewals aren't failures, so the state's status condition remains True
+	result.CustomStatusCondition = &ResultStatusCondition{
+		Status:  corev1.ConditionTrue,
+		Reason:  result.Reason,
+		Message: msg,
+	}
+	return result
+}
+
 // DoneAndRequeueResult returns a new requeue result, which will trigger a requeue after the specified duration.
 func DoneAndRequeueResult(msg string, requeueAfter time.Duration) Result {
 	return Result{
@@ -186,6 +303,18 @@ func DoneResult() Result {
 	}
 }
 
+// SkipResult returns a done result that terminates the FSM without changing the object's status conditions and without requeuing.
+// This is for reconciles determining that the object isn't their responsibility, e.g. because it's handled by another
+// controller in split-ownership scenarios. reason is a concise upper camel case string recorded in the
+// achilles_reconcile_skipped_total metric.
+func SkipResult(reason string) Result {
+	return Result{
+		Done:    true,
+		Skipped: true,
+		Reason:  api.ConditionReason(reason),
+	}
+}
+
 // DoneAndRequeueAfterCompletionWithBackoff returns a result that signals successful reconciliation of the current state,
 // and causes the FSM to requeue after all state transitions are completed, even if successful.
 // The object's status condition of type=Ready will be set to false.
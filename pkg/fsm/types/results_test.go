@@ -0,0 +1,243 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	testv1alpha1 "github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+func TestResult_WithMinRequeueInterval(t *testing.T) {
+	const interval = 10 * time.Second
+	err := errors.New("error")
+
+	cases := []struct {
+		name     string
+		result   Result
+		expected Result
+	}{
+		{
+			name:     "sub-floor requeue",
+			result:   RequeueResult("requeue", time.Second),
+			expected: RequeueResult("requeue", interval),
+		},
+		{
+			name:     "negative requeue",
+			result:   RequeueResult("requeue", -time.Minute),
+			expected: RequeueResult("requeue", interval),
+		},
+		{
+			name:     "requeue above floor",
+			result:   RequeueResult("requeue", time.Minute),
+			expected: RequeueResult("requeue", time.Minute),
+		},
+		{
+			name:     "sub-floor requeue after completion",
+			result:   DoneAndRequeueResult("requeue", time.Second),
+			expected: DoneAndRequeueResult("requeue", interval),
+		},
+		{
+			name:     "requeue with backoff",
+			result:   RequeueResultWithBackoff("requeue"),
+			expected: RequeueResultWithBackoff("requeue"),
+		},
+		{
+			name:     "error",
+			result:   ErrorResult(err),
+			expected: ErrorResult(err),
+		},
+		{
+			name:     "done",
+			result:   DoneResult(),
+			expected: DoneResult(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.result.WithMinRequeueInterval(interval)
+			if diff := cmp.Diff(tc.expected, actual, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBranchResult(t *testing.T) {
+	createState := &State[*testv1alpha1.TestClaimed]{Name: "create"}
+	updateState := &State[*testv1alpha1.TestClaimed]{Name: "update"}
+	candidates := map[string]*State[*testv1alpha1.TestClaimed]{
+		"create": createState,
+		"update": updateState,
+	}
+
+	next, result := BranchResult(candidates, "update")
+	if next != updateState {
+		t.Errorf("expected next state %q, got %v", updateState.Name, next)
+	}
+	if diff := cmp.Diff(DoneResult(), result, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+
+	next, result = BranchResult(candidates, "delete")
+	if next != nil {
+		t.Errorf("expected no next state, got %v", next)
+	}
+	if result.Err == nil || result.Err.Error() != `branch "delete" is not among candidates [create update]` {
+		t.Errorf("unexpected error %v", result.Err)
+	}
+	if result.Reason != "InvalidBranch" {
+		t.Errorf("expected reason InvalidBranch, got %q", result.Reason)
+	}
+}
+
+func TestRequeueResultAtNextCron(t *testing.T) {
+	// Thursday
+	now := time.Date(2026, time.October, 15, 18, 30, 45, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{
+			name:     "nightly",
+			spec:     "0 2 * * *",
+			expected: time.Date(2026, time.October, 16, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "later today",
+			spec:     "45 18 * * *",
+			expected: time.Date(2026, time.October, 15, 18, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "current minute is excluded",
+			spec:     "30 18 * * *",
+			expected: time.Date(2026, time.October, 16, 18, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "step",
+			spec:     "*/20 * * * *",
+			expected: time.Date(2026, time.October, 15, 18, 40, 0, 0, time.UTC),
+		},
+		{
+			name:     "range with step and list",
+			spec:     "0 1-5/2,22 * * *",
+			expected: time.Date(2026, time.October, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of week",
+			spec:     "0 9 * * 1",
+			expected: time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Sunday as 7",
+			spec:     "0 9 * * 7",
+			expected: time.Date(2026, time.October, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			spec:     "0 0 1 * 6",
+			expected: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "next year",
+			spec:     "0 0 29 2 *",
+			expected: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "descriptor",
+			spec:     "@monthly",
+			expected: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := requeueResultAtNextCron(tc.spec, now)
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %s", result.Err)
+			}
+			if diff := cmp.Diff(tc.expected.Sub(now), result.RequeueAfter); diff != "" {
+				t.Errorf("unexpected requeue duration (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, spec := range []string{
+		"",
+		"0 2 * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@sometimes",
+		"0 0 30 2 *",
+	} {
+		result := RequeueResultAtNextCron(spec)
+		if result.Err == nil {
+			t.Errorf("expected error for spec %q", spec)
+		}
+		if result.Reason != "InvalidCronSpec" {
+			t.Errorf("expected reason InvalidCronSpec for spec %q, got %q", spec, result.Reason)
+		}
+	}
+}
+
+func TestRequeueBeforeSoonest(t *testing.T) {
+	const lead = time.Hour
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		expiries []time.Time
+		expected time.Duration
+	}{
+		{
+			name: "soonest expiry",
+			expiries: []time.Time{
+				now.Add(72 * time.Hour),
+				now.Add(24 * time.Hour),
+				now.Add(48 * time.Hour),
+			},
+			expected: 23 * time.Hour,
+		},
+		{
+			name:     "expiry within lead",
+			expiries: []time.Time{now.Add(72 * time.Hour), now.Add(30 * time.Minute)},
+			expected: time.Millisecond,
+		},
+		{
+			name:     "past expiry",
+			expiries: []time.Time{now.Add(-time.Hour), now.Add(24 * time.Hour)},
+			expected: time.Millisecond,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := requeueBeforeSoonest(tc.expiries, lead, now)
+			if !result.IsDone() || !result.RequeueAfterCompletion {
+				t.Errorf("expected done result requeueing after completion, got %v", result)
+			}
+			if result.Reason != "RenewingBeforeExpiry" {
+				t.Errorf("expected reason RenewingBeforeExpiry, got %q", result.Reason)
+			}
+			if diff := cmp.Diff(tc.expected, result.RequeueAfter); diff != "" {
+				t.Errorf("unexpected requeue duration (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(DoneResult(), RequeueBeforeSoonest(nil, lead), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("unexpected result without expiries (-want +got):\n%s", diff)
+	}
+}
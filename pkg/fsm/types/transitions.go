@@ -2,6 +2,8 @@ package types
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"sort"
@@ -9,11 +11,18 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	batch "k8s.io/api/batch/v1"
 	coordination "k8s.io/api/coordination/v1"
 	core "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/reddit/achilles-sdk-api/api"
 	apitypes "github.com/reddit/achilles-sdk-api/pkg/types"
@@ -31,6 +40,8 @@ type ResourceManagerObject interface {
 type getUnreadyResourcesOptions struct {
 	// customReadyFuncs is a list of custom resource readiness checks.
 	customReadyFuncs []customResourceReadyFunc
+	// readyStabilization is the duration for which resources must be continuously ready to be considered ready.
+	readyStabilization time.Duration
 }
 
 // customResourceReadyFunc is a tuple of a resource type and a function that determines if the resource is ready.
@@ -39,6 +50,23 @@ type customResourceReadyFunc struct {
 	ReadyFunc func(o any) (ready, matched bool)
 }
 
+// CustomResourceReadyFunc is a custom resource readiness check, created with MakeCustomReadyFunc, AllConditionsTrue, or CrossplaneReady.
+type CustomResourceReadyFunc = customResourceReadyFunc
+
+type defaultReadyFuncsContextKey struct{}
+
+// NewDefaultReadyFuncsContext returns a new Context, derived from ctx, which carries custom resource readiness checks
+// applied by GetUnreadyResources in addition to those passed with WithCustomReadyFuncs.
+func NewDefaultReadyFuncsContext(ctx context.Context, customReadyFuncs ...CustomResourceReadyFunc) context.Context {
+	return context.WithValue(ctx, defaultReadyFuncsContextKey{}, customReadyFuncs)
+}
+
+// defaultReadyFuncsFromContext returns the custom resource readiness checks carried by ctx, if any.
+func defaultReadyFuncsFromContext(ctx context.Context) []customResourceReadyFunc {
+	customReadyFuncs, _ := ctx.Value(defaultReadyFuncsContextKey{}).([]customResourceReadyFunc)
+	return customReadyFuncs
+}
+
 // GetUnreadyResourcesOption adds optional semantics to GetUnreadyResources.
 type GetUnreadyResourcesOption func(*getUnreadyResourcesOptions)
 
@@ -49,6 +77,13 @@ func WithCustomReadyFuncs(customReadyFuncs ...customResourceReadyFunc) GetUnread
 	}
 }
 
+// withReadyStabilization considers resources ready only once their Ready condition has been True for the given duration.
+func withReadyStabilization(window time.Duration) GetUnreadyResourcesOption {
+	return func(o *getUnreadyResourcesOptions) {
+		o.readyStabilization = window
+	}
+}
+
 // MakeCustomReadyFunc creates a customResourceReadyFunc from a function that determines if a resource is ready.
 func MakeCustomReadyFunc[T any](readyFunc func(T) bool) customResourceReadyFunc {
 	return customResourceReadyFunc{
@@ -63,6 +98,85 @@ func MakeCustomReadyFunc[T any](readyFunc func(T) bool) customResourceReadyFunc
 	}
 }
 
+// AllConditionsTrue creates a customResourceReadyFunc that considers a resource ready if and only if all of the
+// specified status conditions are True. Conditions are read from the resource's "status.conditions" field, so this
+// works for any resource following Kubernetes condition conventions. Missing conditions are considered not ready.
+func AllConditionsTrue(conditionTypes ...api.ConditionType) customResourceReadyFunc {
+	return customResourceReadyFunc{
+		Type: reflect.TypeFor[client.Object](),
+		ReadyFunc: func(o any) (ready, matched bool) {
+			statuses, ok := conditionStatuses(o)
+			if !ok {
+				return false, false
+			}
+			for _, conditionType := range conditionTypes {
+				if statuses[string(conditionType)] != string(core.ConditionTrue) {
+					return false, true
+				}
+			}
+			return true, true
+		},
+	}
+}
+
+// CrossplaneReady creates a customResourceReadyFunc for Crossplane resources,
+// which are ready if and only if both their Ready and Synced conditions are True.
+func CrossplaneReady() customResourceReadyFunc {
+	return AllConditionsTrue(api.TypeReady, api.TypeSynced)
+}
+
+// readySince returns the last transition time of the object's Ready condition read from its "status.conditions" field.
+// Returns false if the object has no Ready condition with a transition time.
+func readySince(o client.Object) (time.Time, bool) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(u, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if conditionType, _, _ := unstructured.NestedString(condition, "type"); conditionType != string(api.TypeReady) {
+			continue
+		}
+		lastTransitionTime, _, _ := unstructured.NestedString(condition, "lastTransitionTime")
+		since, err := time.Parse(time.RFC3339, lastTransitionTime)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return since, true
+	}
+	return time.Time{}, false
+}
+
+// conditionStatuses returns a map of condition type to condition status read from the object's "status.conditions" field.
+// Returns false if the object cannot be converted to unstructured data.
+func conditionStatuses(o any) (map[string]string, bool) {
+	if _, ok := o.(client.Object); !ok {
+		return nil, false
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, false
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(u, "status", "conditions")
+	statuses := make(map[string]string, len(conditions))
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		conditionStatus, _, _ := unstructured.NestedString(condition, "status")
+		statuses[conditionType] = conditionStatus
+	}
+	return statuses, true
+}
+
 // GetUnreadyResources returns a list of child resources managed by obj that are not marked as ready,
 // determined by reading the state of the child resources from the kube-apiserver.
 // This function understands readiness of Achilles CRDs, and can be extended with
@@ -71,6 +185,7 @@ func MakeCustomReadyFunc[T any](readyFunc func(T) bool) customResourceReadyFunc
 // Custom resource checks are performed in the order they are provided, with the first matching readiness
 // function that returns ready determining the readiness of the resource.
 // The resource is considered unready if and only if no custom readiness function matches and returns true.
+// Default checks carried by ctx (see NewDefaultReadyFuncsContext) are performed after those provided.
 func GetUnreadyResources(
 	ctx context.Context,
 	c client.Client,
@@ -83,6 +198,7 @@ func GetUnreadyResources(
 	for _, o := range options {
 		o(opts)
 	}
+	opts.customReadyFuncs = append(opts.customReadyFuncs, defaultReadyFuncsFromContext(ctx)...)
 
 	unreadyResources := []client.Object{}
 
@@ -92,16 +208,15 @@ func GetUnreadyResources(
 	}
 
 	for _, o := range managedResources {
+		var ready bool
 		switch res := o.(type) {
 		case api.Conditioned: // achilles resources
-			if !status.ResourceReady(res) {
-				unreadyResources = append(unreadyResources, o)
-			}
+			ready = status.ResourceReady(res)
 		case *coordination.Lease, *core.ConfigMap:
 			// These resources don't have status and they are ready as soon as created.
 			continue
 		default:
-			var foundReadyFunc, ready bool
+			var foundReadyFunc bool
 			for _, customReadyFunc := range opts.customReadyFuncs {
 				var matched bool
 				ready, matched = customReadyFunc.ReadyFunc(res)
@@ -112,13 +227,21 @@ func GetUnreadyResources(
 					}
 				}
 			}
-			if !ready {
-				unreadyResources = append(unreadyResources, o)
-			}
 			if !foundReadyFunc {
 				log.Warnf("Recource %T doesn't have readiness flag so it won't be ever considered ready", res)
 			}
 		}
+
+		// resources that only recently became ready may be flapping
+		if ready && opts.readyStabilization > 0 {
+			if since, ok := readySince(o); ok && time.Since(since) < opts.readyStabilization {
+				ready = false
+			}
+		}
+
+		if !ready {
+			unreadyResources = append(unreadyResources, o)
+		}
 	}
 
 	return unreadyResources, nil
@@ -133,6 +256,9 @@ type transitionWhenReadyOpts struct {
 	// resources is a list of resources to check for readiness. If empty, all child resources of the parent object are checked.
 	resources []client.Object
 
+	// readyStabilization is the duration for which resources must be continuously ready to be considered ready.
+	readyStabilization time.Duration
+
 	getUnreadyResourcesFn func(
 		ctx context.Context,
 		c client.Client,
@@ -157,6 +283,16 @@ func WithResources(resources ...client.Object) func(*transitionWhenReadyOpts) {
 	}
 }
 
+// WithReadyStabilization requires resources checked by TransitionWhenReady to be continuously ready for the given duration,
+// measured from the last transition of their Ready condition, guarding against resources that momentarily report ready
+// before failing. A resource becoming unready resets the duration. Resources without a Ready condition, e.g. those
+// whose readiness is determined by custom ready funcs, are considered ready as soon as their readiness check passes.
+func WithReadyStabilization(window time.Duration) func(*transitionWhenReadyOpts) {
+	return func(o *transitionWhenReadyOpts) {
+		o.readyStabilization = window
+	}
+}
+
 // WithGetUnreadyResourcesFn sets the function to use for getting unready resources in TransitionWhenReady.
 // If not set, GetUnreadyResources is used.
 func WithGetUnreadyResourcesFn(fn func(
@@ -174,7 +310,7 @@ func WithGetUnreadyResourcesFn(fn func(
 
 // TransitionWhenReady is a state transition function that returns the next state if all specified resources are marked Ready.
 // If no resources are specified, the function will check all child resources of the parent object.
-// Crossplane resources must have both Ready and Synced conditions set to True.
+// Crossplane resources must have both Ready and Synced conditions set to True (see CrossplaneReady).
 // If any in-scope resources are not ready, requeues reconcile loop in 10 seconds.
 func TransitionWhenReady[T ResourceManagerObject](
 	c client.Client,
@@ -196,7 +332,11 @@ func TransitionWhenReady[T ResourceManagerObject](
 		obj T,
 		out *OutputSet,
 	) (*State[T], Result) {
-		unreadyResources, err := opts.getUnreadyResourcesFn(ctx, c, scheme, log, obj)
+		var getUnreadyResourcesOpts []GetUnreadyResourcesOption
+		if opts.readyStabilization > 0 {
+			getUnreadyResourcesOpts = append(getUnreadyResourcesOpts, withReadyStabilization(opts.readyStabilization))
+		}
+		unreadyResources, err := opts.getUnreadyResourcesFn(ctx, c, scheme, log, obj, getUnreadyResourcesOpts...)
 		if err != nil {
 			return nil, ErrorResult(err)
 		}
@@ -244,6 +384,467 @@ func TransitionWhenReady[T ResourceManagerObject](
 	}
 }
 
+type TransitionWhenAnnotationPresentOption func(*transitionWhenAnnotationPresentOpts)
+
+type transitionWhenAnnotationPresentOpts struct {
+	// requeueAfter is the duration to wait before requeueing the reconcile loop. Defaults to 10 seconds.
+	requeueAfter time.Duration
+
+	// value, if set, is the value the annotation must have. If nil, only the presence of the annotation is checked.
+	value *string
+}
+
+// WithAnnotationValue requires the annotation checked by TransitionWhenAnnotationPresent to have the specified value.
+// If not set, any value (including the empty string) satisfies the check.
+func WithAnnotationValue(value string) TransitionWhenAnnotationPresentOption {
+	return func(o *transitionWhenAnnotationPresentOpts) {
+		o.value = &value
+	}
+}
+
+// WithAnnotationRequeueAfter sets the requeue duration for TransitionWhenAnnotationPresent. If not set, the default is 10 seconds.
+func WithAnnotationRequeueAfter(requeueAfter time.Duration) TransitionWhenAnnotationPresentOption {
+	return func(o *transitionWhenAnnotationPresentOpts) {
+		o.requeueAfter = requeueAfter
+	}
+}
+
+// TransitionWhenAnnotationPresent is a state transition function that returns the next state once the reconciled object
+// has the annotation with the specified key. The object is re-fetched from the kube-apiserver so that annotations set by
+// other controllers since the start of the reconcile are observed.
+// If the annotation is absent (or doesn't match the value specified with WithAnnotationValue), requeues reconcile loop in 10 seconds.
+func TransitionWhenAnnotationPresent[T client.Object](
+	c client.Client,
+	scheme *runtime.Scheme,
+	log *zap.SugaredLogger,
+	key string,
+	next *State[T],
+	options ...TransitionWhenAnnotationPresentOption,
+) TransitionFunc[T] {
+	opts := &transitionWhenAnnotationPresentOpts{
+		requeueAfter: 10 * time.Second,
+	}
+	for _, o := range options {
+		o(opts)
+	}
+
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		current := obj.DeepCopyObject().(T)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			return nil, ErrorResultf("getting %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+		}
+
+		value, ok := current.GetAnnotations()[key]
+		if ok && (opts.value == nil || value == *opts.value) {
+			return next, DoneResult()
+		}
+
+		var msg string
+		if opts.value == nil {
+			msg = fmt.Sprintf("waiting for annotation %q", key)
+		} else {
+			msg = fmt.Sprintf("waiting for annotation %q to have value %q", key, *opts.value)
+		}
+		if tof, err := meta.TypedObjectRefFromObject(current, scheme); err == nil {
+			log.Debugf("%s on %s", msg, tof)
+		}
+		return nil, RequeueResult(msg, opts.requeueAfter)
+	}
+}
+
+// TransitionWhenFunc is a state transition function that returns the next state once check reports done, e.g. for
+// readiness depending on state outside of Kubernetes such as an external API.
+// While not done, requeues reconcile loop after requeueAfter (or with exponential backoff if zero) with the message
+// returned by check, which is surfaced on the state's status condition.
+// If check returns an error, the transition fails with the error rather than requeueing.
+func TransitionWhenFunc[T client.Object](
+	check func(ctx context.Context, obj T) (done bool, msg string, err error),
+	requeueAfter time.Duration,
+	next *State[T],
+) TransitionFunc[T] {
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		done, msg, err := check(ctx, obj)
+		if err != nil {
+			return nil, ErrorResultf("checking condition: %w", err)
+		}
+		if done {
+			return next, DoneResult()
+		}
+
+		if msg == "" {
+			msg = "waiting for condition"
+		}
+		return nil, RequeueResult(msg, requeueAfter)
+	}
+}
+
+// SecretLastRotatedAnnotationKey is the annotation recording the time, in RFC 3339 format, at which a Secret managed by
+// RotateSecret was last rotated.
+const SecretLastRotatedAnnotationKey = "infrared.reddit.com/last-rotated"
+
+// RotateSecret is a state transition function that outputs the Secret with the specified name, in the reconciled object's
+// namespace, with data returned by generate. The Secret is regenerated only if it doesn't exist or if its last rotation
+// (as recorded in the SecretLastRotatedAnnotationKey annotation) is at least interval ago, otherwise its current data is
+// retained, so the transition is idempotent across reconciles.
+// Rotation is only evaluated when the object is reconciled, so the controller must reconcile at least once per interval
+// (e.g. through a sync period) for rotation to happen on time.
+func RotateSecret[T client.Object](
+	c client.Client,
+	scheme *runtime.Scheme,
+	name string,
+	generate func() map[string][]byte,
+	interval time.Duration,
+	next *State[T],
+) TransitionFunc[T] {
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		secret := &core.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: obj.GetNamespace(),
+			},
+		}
+		gvk, err := apiutil.GVKForObject(secret, scheme)
+		if err != nil {
+			return nil, ErrorResultf("getting GVK for Secret: %w", err)
+		}
+		secret.SetGroupVersionKind(gvk)
+
+		current := &core.Secret{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(secret), current); err != nil && !k8serrors.IsNotFound(err) {
+			return nil, ErrorResultf("getting Secret %s: %w", client.ObjectKeyFromObject(secret), err)
+		}
+
+		now := time.Now()
+		// a missing or malformed annotation (including on first creation) triggers rotation
+		lastRotated, err := time.Parse(time.RFC3339, current.GetAnnotations()[SecretLastRotatedAnnotationKey])
+		if err != nil || !now.Before(lastRotated.Add(interval)) {
+			secret.Data = generate()
+			lastRotated = now
+		} else {
+			secret.Data = current.Data
+		}
+		secret.SetAnnotations(map[string]string{
+			SecretLastRotatedAnnotationKey: lastRotated.UTC().Format(time.RFC3339),
+		})
+
+		out.Apply(secret)
+		return next, DoneResult()
+	}
+}
+
+// TransitionOnServerSupport is a state transition function that branches on whether the kube-apiserver serves the
+// specified GVK (see meta.ServerSupports), e.g. for managing PodDisruptionBudgets of policy/v1 or policy/v1beta1 depending
+// on the cluster version. Returns supported if the GVK is served, otherwise unsupported.
+// Discovery errors fail the transition rather than being treated as the GVK not being served.
+func TransitionOnServerSupport[T client.Object](
+	discoveryClient discovery.DiscoveryInterface,
+	gvk schema.GroupVersionKind,
+	supported *State[T],
+	unsupported *State[T],
+) TransitionFunc[T] {
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		ok, err := meta.ServerSupports(discoveryClient, gvk)
+		if err != nil {
+			return nil, ErrorResultf("checking server support of %s: %w", gvk, err)
+		}
+		if ok {
+			return supported, DoneResult()
+		}
+		return unsupported, DoneResult()
+	}
+}
+
+// blueGreenRollbackAnnotationPrefix is the prefix of annotations recording rolled back swaps of BlueGreenSwap.
+const blueGreenRollbackAnnotationPrefix = "blue-green-rollback.infrared.reddit.com/"
+
+// BlueGreenRollbackAnnotationKey returns the key of the annotation recording, on the reconciled object, the time
+// (in RFC 3339 format) at which BlueGreenSwap rolled back the swap to the new version with the specified name.
+// Removing the annotation causes the swap to be reattempted. Names exceeding the maximum length of annotation key
+// names are replaced with a hash of the name.
+func BlueGreenRollbackAnnotationKey(name string) string {
+	if len(name) > validation.LabelValueMaxLength {
+		sum := sha256.Sum256([]byte(name))
+		name = hex.EncodeToString(sum[:])[:validation.LabelValueMaxLength]
+	}
+	return blueGreenRollbackAnnotationPrefix + name
+}
+
+// BlueGreenSwap is a state transition function that replaces a managed resource without downtime by creating its new
+// version, waiting for it to become ready, and only then deleting its old version. oldObj and newObj return the old and
+// new version of the resource for the reconciled object, and must differ in name. New versions are read from the
+// kube-apiserver and considered ready once ready returns true.
+//
+// The handoff spans multiple reconciles. While the new version isn't ready, it's applied and the reconcile loop is
+// requeued in 10 seconds (or sooner if the timeout elapses first). Once it's ready, the old version is deleted and
+// next is returned.
+// If the new version isn't ready within timeout of its creation, it's deleted, leaving the old version in place,
+// and rollback is returned. The rollback is recorded on the reconciled object in an annotation keyed by the new
+// version's name (see BlueGreenRollbackAnnotationKey), and subsequent reconciles return rollback without recreating
+// the new version until newObj returns a version with a different name.
+func BlueGreenSwap[T client.Object](
+	c client.Client,
+	scheme *runtime.Scheme,
+	oldObj func(obj T) client.Object,
+	newObj func(obj T) client.Object,
+	ready func(obj client.Object) bool,
+	timeout time.Duration,
+	next *State[T],
+	rollback *State[T],
+) TransitionFunc[T] {
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		old := oldObj(obj)
+		desired := newObj(obj)
+		for _, o := range []client.Object{old, desired} {
+			gvk, err := apiutil.GVKForObject(o, scheme)
+			if err != nil {
+				return nil, ErrorResultf("getting GVK for %T: %w", o, err)
+			}
+			o.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+
+		rollbackKey := BlueGreenRollbackAnnotationKey(desired.GetName())
+		if _, ok := obj.GetAnnotations()[rollbackKey]; ok {
+			// the swap to this version was already rolled back
+			return rollback, DoneResult()
+		}
+
+		msg := fmt.Sprintf("waiting for %T %s to become ready", desired, client.ObjectKeyFromObject(desired))
+		current := desired.DeepCopyObject().(client.Object)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, ErrorResultf("getting %T %s: %w", desired, client.ObjectKeyFromObject(desired), err)
+			}
+
+			// create the new version, which is then awaited on subsequent reconciles
+			out.Apply(desired)
+			return nil, RequeueResult(msg, 10*time.Second)
+		}
+
+		if ready(current) {
+			out.Apply(desired)
+			out.Delete(old)
+			return next, DoneResult()
+		}
+
+		remaining := timeout - time.Since(current.GetCreationTimestamp().Time)
+		if remaining <= 0 {
+			out.Delete(desired)
+			out.PatchSelf(func(o client.Object) {
+				annotations := o.GetAnnotations()
+				if annotations == nil {
+					annotations = map[string]string{}
+				}
+				annotations[rollbackKey] = time.Now().UTC().Format(time.RFC3339)
+				o.SetAnnotations(annotations)
+			})
+			return rollback, DoneResult()
+		}
+
+		out.Apply(desired)
+		return nil, RequeueResult(msg, min(remaining, 10*time.Second))
+	}
+}
+
+// ObservedGenerationFunc returns the generation of the object most recently observed by its controller.
+// Returns false if the object isn't handled by the function.
+type ObservedGenerationFunc func(obj client.Object) (observedGeneration int64, ok bool)
+
+type TransitionWhenGenerationObservedOption func(*transitionWhenGenerationObservedOpts)
+
+type transitionWhenGenerationObservedOpts struct {
+	// requeueAfter is the duration to wait before requeueing the reconcile loop. Defaults to 10 seconds.
+	requeueAfter time.Duration
+
+	// observedGenerationFuncs are consulted in order before falling back to the object's "status.observedGeneration" field.
+	observedGenerationFuncs []ObservedGenerationFunc
+}
+
+// WithObservedGenerationFuncs adds functions for reading the observed generation of objects that don't report
+// it in a "status.observedGeneration" field. The first function that handles an object wins.
+func WithObservedGenerationFuncs(fns ...ObservedGenerationFunc) TransitionWhenGenerationObservedOption {
+	return func(o *transitionWhenGenerationObservedOpts) {
+		o.observedGenerationFuncs = append(o.observedGenerationFuncs, fns...)
+	}
+}
+
+// WithGenerationObservedRequeueAfter sets the requeue duration for TransitionWhenGenerationObserved. If not set, the default is 10 seconds.
+func WithGenerationObservedRequeueAfter(requeueAfter time.Duration) TransitionWhenGenerationObservedOption {
+	return func(o *transitionWhenGenerationObservedOpts) {
+		o.requeueAfter = requeueAfter
+	}
+}
+
+// TransitionWhenGenerationObserved is a state transition function that returns the next state once the controllers of
+// all specified children have observed their latest spec, i.e. "status.observedGeneration" equals "metadata.generation".
+// The children are re-fetched from the kube-apiserver, so only their name, namespace, and type need to be populated.
+// Children that don't report "status.observedGeneration" must be handled with WithObservedGenerationFuncs, otherwise
+// they are never considered caught up.
+// If any children are lagging, requeues reconcile loop in 10 seconds.
+func TransitionWhenGenerationObserved[T client.Object](
+	c client.Client,
+	scheme *runtime.Scheme,
+	log *zap.SugaredLogger,
+	children []client.Object,
+	next *State[T],
+	options ...TransitionWhenGenerationObservedOption,
+) TransitionFunc[T] {
+	opts := &transitionWhenGenerationObservedOpts{
+		requeueAfter: 10 * time.Second,
+	}
+	for _, o := range options {
+		o(opts)
+	}
+
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		var laggingNames []string
+		for _, child := range children {
+			current := child.DeepCopyObject().(client.Object)
+			if err := c.Get(ctx, client.ObjectKeyFromObject(child), current); err != nil {
+				return nil, ErrorResultf("getting %T %s: %w", child, client.ObjectKeyFromObject(child), err)
+			}
+
+			// a missing observed generation is indistinguishable from one that hasn't been reported yet, so it's treated as lagging
+			observedGeneration, _ := opts.observedGeneration(current)
+			if observedGeneration >= current.GetGeneration() {
+				continue
+			}
+
+			tof, err := meta.TypedObjectRefFromObject(current, scheme)
+			if err != nil {
+				return nil, ErrorResultf("getting typed object ref for %T %s: %w", current, client.ObjectKeyFromObject(current), err)
+			}
+			// The length of 3 chosen arbitrarily to keep the message reasonably brief while still providing some info
+			if len(laggingNames) < 3 {
+				laggingNames = append(laggingNames, tof.String())
+			}
+			log.Debugf("managed resource %s has generation %d, observed generation %d", tof, current.GetGeneration(), observedGeneration)
+		}
+
+		if len(laggingNames) == 0 {
+			return next, DoneResult()
+		}
+
+		msg := fmt.Sprintf("some managed resources have not observed their latest generation. First three:\n%s",
+			strings.Join(laggingNames, ",\n"))
+		return nil, RequeueResult(msg, opts.requeueAfter)
+	}
+}
+
+// observedGeneration returns the object's observed generation, read with the first matching ObservedGenerationFunc
+// or otherwise from the "status.observedGeneration" field.
+func (o *transitionWhenGenerationObservedOpts) observedGeneration(obj client.Object) (int64, bool) {
+	for _, fn := range o.observedGenerationFuncs {
+		if observedGeneration, ok := fn(obj); ok {
+			return observedGeneration, true
+		}
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return 0, false
+	}
+	observedGeneration, found, err := unstructured.NestedInt64(u, "status", "observedGeneration")
+	if err != nil || !found {
+		return 0, false
+	}
+	return observedGeneration, true
+}
+
+// TransitionWhenJobComplete is a state transition function that waits for the Job with the specified key to finish.
+// Returns next once the Job has completed successfully, or onFailure once it has failed (e.g. by exceeding its backoff
+// limit or active deadline). While the Job is running, requeues reconcile loop in 10 seconds.
+// If the Job doesn't exist, e.g. because it was deleted mid-wait or isn't yet observed by the cache, the reconcile loop
+// is also requeued, relying on preceding states to (re)create the Job.
+func TransitionWhenJobComplete[T client.Object](
+	c client.Client,
+	scheme *runtime.Scheme,
+	jobRef client.ObjectKey,
+	next *State[T],
+	onFailure *State[T],
+) TransitionFunc[T] {
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		job := &batch.Job{}
+		if err := c.Get(ctx, jobRef, job); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, RequeueResult(fmt.Sprintf("waiting for Job %s to exist", jobRef), 10*time.Second)
+			}
+			return nil, ErrorResultf("getting Job %s: %w", jobRef, err)
+		}
+
+		switch {
+		case jobConditionTrue(job, batch.JobComplete):
+			return next, DoneResult()
+		case jobConditionTrue(job, batch.JobFailed):
+			return onFailure, DoneResult()
+		}
+
+		name := jobRef.String()
+		if tof, err := meta.TypedObjectRefFromObject(job, scheme); err == nil {
+			name = tof.String()
+		}
+		return nil, RequeueResult(fmt.Sprintf("waiting for %s to complete", name), 10*time.Second)
+	}
+}
+
+// jobConditionTrue returns true if the Job has a condition of the specified type with status True.
+func jobConditionTrue(job *batch.Job, conditionType batch.JobConditionType) bool {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == conditionType && cond.Status == core.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingChildDeletionsRecorder records the number of child resources, by type, pending deletion for a parent resource.
+// It is implemented by metrics.Metrics.
+type PendingChildDeletionsRecorder interface {
+	RecordPendingChildDeletions(parent client.Object, pending map[schema.GroupVersionKind]int)
+}
+
+type DeleteChildrenForegroundOption func(*deleteChildrenForegroundOpts)
+
+type deleteChildrenForegroundOpts struct {
+	// recorder, if set, records the number of child resources pending deletion on each pass.
+	recorder PendingChildDeletionsRecorder
+}
+
+// WithPendingChildDeletionsRecorder records the number of child resources pending deletion on each pass of DeleteChildrenForeground,
+// exposed as the "achilles_pending_child_deletions" metric when passed a metrics.Metrics.
+func WithPendingChildDeletionsRecorder(recorder PendingChildDeletionsRecorder) DeleteChildrenForegroundOption {
+	return func(o *deleteChildrenForegroundOpts) {
+		o.recorder = recorder
+	}
+}
+
 // DeleteChildrenForeground is a generic state that implements foreground cascading deletion
 // of children resources (i.e. resources managed by the parent resource).
 //
@@ -260,7 +861,13 @@ func DeleteChildrenForeground[T ResourceManagerObject](
 	scheme *runtime.Scheme,
 	log *zap.SugaredLogger,
 	next *State[T],
+	options ...DeleteChildrenForegroundOption,
 ) TransitionFunc[T] {
+	opts := &deleteChildrenForegroundOpts{}
+	for _, o := range options {
+		o(opts)
+	}
+
 	return func(
 		ctx context.Context,
 		parent T,
@@ -294,6 +901,14 @@ func DeleteChildrenForeground[T ResourceManagerObject](
 			}
 		}
 
+		if opts.recorder != nil {
+			pending := map[schema.GroupVersionKind]int{}
+			for _, ref := range extantChildRefs {
+				pending[ref.GroupVersionKind()]++
+			}
+			opts.recorder.RecordPendingChildDeletions(parent, pending)
+		}
+
 		// update resource refs
 		parent.SetManagedResources(extantChildRefs)
 		if err := c.ApplyStatus(ctx, parent); err != nil {
@@ -309,6 +924,71 @@ func DeleteChildrenForeground[T ResourceManagerObject](
 	}
 }
 
+// EnsureChildMetadata is a state transition function that ensures all managed resources of the reconciled object have
+// the specified labels and annotations, e.g. for backfilling a newly required label onto existing children, and then
+// returns the next state.
+// Only keys missing from a child are added. Keys already set by another actor, even to a different value, are left
+// unchanged so that the transition never fights other label or annotation managers. Only children missing any of the
+// specified keys are patched, and the patch only sets those keys, so the transition is a no-op once all children have them.
+func EnsureChildMetadata[T ResourceManagerObject](
+	c client.Client,
+	scheme *runtime.Scheme,
+	labels map[string]string,
+	annotations map[string]string,
+	next *State[T],
+) TransitionFunc[T] {
+	return func(
+		ctx context.Context,
+		obj T,
+		out *OutputSet,
+	) (*State[T], Result) {
+		children, err := readManagedResources(ctx, c, scheme, obj)
+		if err != nil {
+			return nil, ErrorResultf("reading managed resources: %w", err)
+		}
+
+		for _, child := range children {
+			if containsKeys(child.GetLabels(), labels) && containsKeys(child.GetAnnotations(), annotations) {
+				continue
+			}
+
+			original := child.DeepCopyObject().(client.Object)
+			child.SetLabels(addMissingKeys(child.GetLabels(), labels))
+			child.SetAnnotations(addMissingKeys(child.GetAnnotations(), annotations))
+			if err := c.Patch(ctx, child, client.MergeFrom(original)); client.IgnoreNotFound(err) != nil {
+				return nil, ErrorResultf("patching metadata of managed resource %T %s: %w", child, client.ObjectKeyFromObject(child), err)
+			}
+		}
+
+		return next, DoneResult()
+	}
+}
+
+// containsKeys returns true if m contains all keys of entries, regardless of their values.
+func containsKeys(m, entries map[string]string) bool {
+	for k := range entries {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// addMissingKeys returns a copy of m with the entries whose keys are missing from m added, retaining existing values.
+func addMissingKeys(m, entries map[string]string) map[string]string {
+	if len(entries) == 0 {
+		return m
+	}
+	merged := make(map[string]string, len(m)+len(entries))
+	for k, v := range entries {
+		merged[k] = v
+	}
+	for k, v := range m {
+		merged[k] = v
+	}
+	return merged
+}
+
 // readManagedResources reads and returns all managed resources of the specified parent.
 // Managed resources that are not found are ignored.
 func readManagedResources(
@@ -338,3 +1018,46 @@ func readManagedResources(
 	}
 	return managedResources, nil
 }
+
+// GetManagedResource reads the managed resource of type T with the specified name from the parent's managed resource refs.
+// Returns false if the parent doesn't reference a managed resource of type T with the specified name,
+// or if the referenced resource is not found. T must be a concrete pointer type (e.g. *corev1.ConfigMap), otherwise an error is returned.
+func GetManagedResource[T client.Object](
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	parent ResourceManagerObject,
+	name string,
+) (T, bool, error) {
+	var zero T
+
+	// T must be a concrete pointer type, e.g. *corev1.ConfigMap, from which a new object can be constructed
+	typ := reflect.TypeOf(zero)
+	if typ == nil || typ.Kind() != reflect.Pointer {
+		return zero, false, fmt.Errorf("constructing new %s, type must be a pointer to a struct", reflect.TypeFor[T]())
+	}
+	managedObj, ok := reflect.New(typ.Elem()).Interface().(T)
+	if !ok {
+		return zero, false, fmt.Errorf("constructing new %T", zero)
+	}
+	gvk, err := apiutil.GVKForObject(managedObj, scheme)
+	if err != nil {
+		return zero, false, fmt.Errorf("getting GVK for %T: %w", managedObj, err)
+	}
+
+	for _, res := range parent.GetManagedResources() {
+		if res.Name != name || res.GroupVersionKind() != gvk {
+			continue
+		}
+
+		if err := c.Get(ctx, res.ObjectKey(), managedObj); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return zero, false, nil
+			}
+			return zero, false, fmt.Errorf("getting managed resource %s %s: %w", gvk.Kind, res.ObjectKey(), err)
+		}
+		return managedObj, true, nil
+	}
+
+	return zero, false, nil
+}
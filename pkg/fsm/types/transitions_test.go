@@ -3,13 +3,22 @@ package types
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap/zaptest"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -120,6 +129,132 @@ func Test_GetUnreadyResources(t *testing.T) {
 	}
 }
 
+func Test_GetUnreadyResources_DefaultReadyFuncs(t *testing.T) {
+	log := zaptest.NewLogger(t).Sugar()
+	scheme := intscheme.MustNewScheme()
+
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}
+	}
+	defaultReady := newSecret("default-ready")
+	perCallReady := newSecret("per-call-ready")
+	unready := newSecret("unready")
+
+	parent := &testv1alpha1.TestClaimed{
+		ObjectMeta: metav1.ObjectMeta{Name: "foobar"},
+		Status: testv1alpha1.TestClaimedStatus{
+			Resources: []api.TypedObjectRef{
+				*meta.MustTypedObjectRefFromObject(defaultReady, scheme),
+				*meta.MustTypedObjectRefFromObject(perCallReady, scheme),
+				*meta.MustTypedObjectRefFromObject(unready, scheme),
+			},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(parent, defaultReady, perCallReady, unready).
+		Build()
+
+	ctx := NewDefaultReadyFuncsContext(context.Background(), MakeCustomReadyFunc(func(o *corev1.Secret) bool {
+		return o.GetName() == "default-ready"
+	}))
+
+	// default funcs apply without being passed per call
+	unreadyResources, err := GetUnreadyResources(ctx, c, scheme, log, parent)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []client.Object{perCallReady, unready}, unreadyResources)
+
+	// per-call funcs augment the defaults
+	unreadyResources, err = GetUnreadyResources(ctx, c, scheme, log, parent, WithCustomReadyFuncs(
+		MakeCustomReadyFunc(func(o *corev1.Secret) bool {
+			return o.GetName() == "per-call-ready"
+		}),
+	))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []client.Object{unready}, unreadyResources)
+
+	// TransitionWhenReady applies the defaults
+	next := &State[*testv1alpha1.TestClaimed]{Name: "next"}
+	actualNext, result := TransitionWhenReady(c, scheme, log, next, WithResources(defaultReady))(ctx, parent, NewOutputSet(scheme))
+	assert.Equal(t, next, actualNext)
+	assert.True(t, result.IsDone())
+}
+
+func Test_AllConditionsTrue(t *testing.T) {
+	newResource := func(conditions ...map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("database.example.org/v1alpha1")
+		u.SetKind("Instance")
+		u.SetName("instance")
+		var cs []interface{}
+		for _, c := range conditions {
+			cs = append(cs, c)
+		}
+		if cs != nil {
+			assert.NoError(t, unstructured.SetNestedSlice(u.Object, cs, "status", "conditions"))
+		}
+		return u
+	}
+	condition := func(conditionType api.ConditionType, status corev1.ConditionStatus) map[string]interface{} {
+		return map[string]interface{}{"type": string(conditionType), "status": string(status)}
+	}
+
+	tcs := []struct {
+		name          string
+		obj           any
+		expectedReady bool
+		expectedMatch bool
+	}{
+		{
+			name:          "both conditions true",
+			obj:           newResource(condition(api.TypeReady, corev1.ConditionTrue), condition(api.TypeSynced, corev1.ConditionTrue)),
+			expectedReady: true,
+			expectedMatch: true,
+		},
+		{
+			name:          "one of two conditions true",
+			obj:           newResource(condition(api.TypeReady, corev1.ConditionTrue), condition(api.TypeSynced, corev1.ConditionFalse)),
+			expectedReady: false,
+			expectedMatch: true,
+		},
+		{
+			name:          "one of two conditions missing",
+			obj:           newResource(condition(api.TypeSynced, corev1.ConditionTrue)),
+			expectedReady: false,
+			expectedMatch: true,
+		},
+		{
+			name:          "no conditions",
+			obj:           newResource(),
+			expectedReady: false,
+			expectedMatch: true,
+		},
+		{
+			name:          "typed object",
+			obj:           &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: "Ready", Status: corev1.ConditionTrue}, {Type: "Synced", Status: corev1.ConditionTrue}}}},
+			expectedReady: true,
+			expectedMatch: true,
+		},
+		{
+			name:          "not an object",
+			obj:           "foo",
+			expectedReady: false,
+			expectedMatch: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ready, matched := CrossplaneReady().ReadyFunc(tc.obj)
+			assert.Equal(t, tc.expectedReady, ready)
+			assert.Equal(t, tc.expectedMatch, matched)
+		})
+	}
+}
+
 func Test_TransitionWhenReady(t *testing.T) {
 	requeueDuration := 10 * time.Second
 	log := zaptest.NewLogger(t).Sugar()
@@ -252,6 +387,495 @@ func Test_TransitionWhenReady(t *testing.T) {
 
 }
 
+func Test_TransitionWhenReady_ReadyStabilization(t *testing.T) {
+	const window = 5 * time.Minute
+	log := zaptest.NewLogger(t).Sugar()
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	readyCondition := func(since time.Time) api.Condition {
+		condition := status.NewReadyCondition(0)
+		condition.LastTransitionTime = metav1.NewTime(since)
+		return condition
+	}
+
+	child := &testv1alpha1.TestClaimed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "child",
+		},
+		Status: testv1alpha1.TestClaimedStatus{
+			ConditionedStatus: api.ConditionedStatus{
+				Conditions: []api.Condition{readyCondition(time.Now().Add(-time.Minute))},
+			},
+		},
+	}
+	parent := &testv1alpha1.TestClaimed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "parent",
+		},
+		Status: testv1alpha1.TestClaimedStatus{
+			Resources: []api.TypedObjectRef{*meta.MustTypedObjectRefFromObject(child, scheme)},
+		},
+	}
+
+	ctx := context.Background()
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(parent, child).
+		WithStatusSubresource(parent, child).
+		Build()
+
+	transition := TransitionWhenReady(c, scheme, log, successState, WithReadyStabilization(window))
+	updateReadiness := func(condition api.Condition) {
+		actual := &testv1alpha1.TestClaimed{}
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(child), actual))
+		actual.Status.Conditions = []api.Condition{condition}
+		assert.NoError(t, c.Status().Update(ctx, actual))
+	}
+
+	// ready for less than the window
+	next, result := transition(ctx, parent, NewOutputSet(scheme))
+	assert.Nil(t, next)
+	assert.True(t, result.HasRequeue())
+
+	// without stabilization, the child is ready immediately
+	next, result = TransitionWhenReady(c, scheme, log, successState)(ctx, parent, NewOutputSet(scheme))
+	assert.Equal(t, successState, next)
+	assert.True(t, result.IsDone())
+
+	// ready for longer than the window
+	updateReadiness(readyCondition(time.Now().Add(-2 * window)))
+	next, result = transition(ctx, parent, NewOutputSet(scheme))
+	assert.Equal(t, successState, next)
+	assert.True(t, result.IsDone())
+
+	// a flap resets the window
+	updateReadiness(status.NewUnreadyCondition(0))
+	updateReadiness(readyCondition(time.Now()))
+	next, result = transition(ctx, parent, NewOutputSet(scheme))
+	assert.Nil(t, next)
+	assert.True(t, result.HasRequeue())
+}
+
+func Test_TransitionWhenAnnotationPresent(t *testing.T) {
+	const key = "example.com/ready"
+	requeueDuration := 10 * time.Second
+	log := zaptest.NewLogger(t).Sugar()
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	waitingForKey := Result{
+		RequeueAfter: requeueDuration,
+		RequeueMsg:   `waiting for annotation "example.com/ready"`,
+	}
+	waitingForValue := Result{
+		RequeueAfter: requeueDuration,
+		RequeueMsg:   `waiting for annotation "example.com/ready" to have value "true"`,
+	}
+
+	tcs := []struct {
+		name    string
+		options []TransitionWhenAnnotationPresentOption
+		// annotations to set on the object before each successive reconcile
+		annotations       []map[string]string
+		expectedNextState []*State[*testv1alpha1.TestClaimed]
+		expectedResult    []Result
+	}{
+		{
+			name: "presence only",
+			annotations: []map[string]string{
+				nil,
+				{"other": "annotation"},
+				{key: ""},
+			},
+			expectedNextState: []*State[*testv1alpha1.TestClaimed]{nil, nil, successState},
+			expectedResult:    []Result{waitingForKey, waitingForKey, DoneResult()},
+		},
+		{
+			name:    "value match",
+			options: []TransitionWhenAnnotationPresentOption{WithAnnotationValue("true")},
+			annotations: []map[string]string{
+				nil,
+				{key: "false"},
+				{key: "true"},
+			},
+			expectedNextState: []*State[*testv1alpha1.TestClaimed]{nil, nil, successState},
+			expectedResult:    []Result{waitingForValue, waitingForValue, DoneResult()},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := &testv1alpha1.TestClaimed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foobar",
+				},
+			}
+			c := fake.NewClientBuilder().
+				WithObjects(obj).
+				WithScheme(scheme).
+				Build()
+
+			transition := TransitionWhenAnnotationPresent[*testv1alpha1.TestClaimed](
+				c,
+				scheme,
+				log,
+				key,
+				successState,
+				append(tc.options, WithAnnotationRequeueAfter(requeueDuration))...,
+			)
+
+			for i, annotations := range tc.annotations {
+				// another controller updates the annotations
+				actual := &testv1alpha1.TestClaimed{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(obj), actual))
+				actual.SetAnnotations(annotations)
+				assert.NoError(t, c.Update(ctx, actual))
+
+				// the (stale) object passed to the transition has no annotations, the state must re-fetch it
+				actualNextState, actualResult := transition(ctx, obj.DeepCopy(), nil)
+
+				assert.Equal(t, tc.expectedNextState[i], actualNextState)
+				assert.Equal(t, tc.expectedResult[i], actualResult)
+			}
+		})
+	}
+}
+
+func Test_TransitionWhenFunc(t *testing.T) {
+	checkErr := errors.New("external API unavailable")
+
+	tcs := []struct {
+		name              string
+		done              bool
+		msg               string
+		err               error
+		expectedNextState *State[*testv1alpha1.TestClaimed]
+		expectedResult    Result
+	}{
+		{
+			name:              "done",
+			done:              true,
+			expectedNextState: successState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name:           "not done",
+			msg:            "waiting for external database",
+			expectedResult: RequeueResult("waiting for external database", 30*time.Second),
+		},
+		{
+			name:           "not done without message",
+			expectedResult: RequeueResult("waiting for condition", 30*time.Second),
+		},
+		{
+			name:           "error",
+			done:           true,
+			err:            checkErr,
+			expectedResult: ErrorResultf("checking condition: %w", checkErr),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &testv1alpha1.TestClaimed{ObjectMeta: metav1.ObjectMeta{Name: "foobar"}}
+
+			var checked *testv1alpha1.TestClaimed
+			transition := TransitionWhenFunc(
+				func(_ context.Context, obj *testv1alpha1.TestClaimed) (bool, string, error) {
+					checked = obj
+					return tc.done, tc.msg, tc.err
+				},
+				30*time.Second,
+				successState,
+			)
+
+			actualNextState, actualResult := transition(context.Background(), obj, nil)
+
+			assert.Same(t, obj, checked)
+			assert.Equal(t, tc.expectedNextState, actualNextState)
+			assert.Equal(t, tc.expectedResult, actualResult)
+			if tc.err != nil {
+				assert.ErrorIs(t, actualResult.Err, checkErr)
+			}
+		})
+	}
+}
+
+func Test_RotateSecret(t *testing.T) {
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	existingData := map[string][]byte{"password": []byte("old")}
+	generatedData := map[string][]byte{"password": []byte("new")}
+
+	tcs := []struct {
+		name            string
+		existing        *corev1.Secret
+		expectedData    map[string][]byte
+		expectedRotated bool
+	}{
+		{
+			name:            "first creation",
+			expectedData:    generatedData,
+			expectedRotated: true,
+		},
+		{
+			name: "before interval",
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "credentials",
+					Namespace:   "default",
+					Annotations: map[string]string{SecretLastRotatedAnnotationKey: time.Now().Add(-30 * time.Minute).UTC().Format(time.RFC3339)},
+				},
+				Data: existingData,
+			},
+			expectedData: existingData,
+		},
+		{
+			name: "after interval",
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "credentials",
+					Namespace:   "default",
+					Annotations: map[string]string{SecretLastRotatedAnnotationKey: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)},
+				},
+				Data: existingData,
+			},
+			expectedData:    generatedData,
+			expectedRotated: true,
+		},
+		{
+			name: "missing annotation",
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "credentials",
+					Namespace: "default",
+				},
+				Data: existingData,
+			},
+			expectedData:    generatedData,
+			expectedRotated: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := &testv1alpha1.TestClaimed{ObjectMeta: metav1.ObjectMeta{Name: "foobar", Namespace: "default"}}
+
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.existing != nil {
+				builder = builder.WithObjects(tc.existing)
+			}
+			c := builder.Build()
+
+			var generated int
+			transition := RotateSecret[*testv1alpha1.TestClaimed](
+				c,
+				scheme,
+				"credentials",
+				func() map[string][]byte {
+					generated++
+					return generatedData
+				},
+				time.Hour,
+				successState,
+			)
+
+			out := NewOutputSet(scheme)
+			startedAt := time.Now().Truncate(time.Second)
+			actualNextState, actualResult := transition(ctx, obj, out)
+
+			assert.Equal(t, successState, actualNextState)
+			assert.Equal(t, DoneResult(), actualResult)
+
+			applied := out.ListApplied()
+			if !assert.Len(t, applied, 1) {
+				return
+			}
+			secret, ok := applied[0].(*corev1.Secret)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, "credentials", secret.Name)
+			assert.Equal(t, "default", secret.Namespace)
+			assert.Equal(t, tc.expectedData, secret.Data)
+
+			lastRotated, err := time.Parse(time.RFC3339, secret.Annotations[SecretLastRotatedAnnotationKey])
+			assert.NoError(t, err)
+			if tc.expectedRotated {
+				assert.Equal(t, 1, generated)
+				assert.False(t, lastRotated.Before(startedAt))
+			} else {
+				assert.Equal(t, 0, generated)
+				assert.Equal(t, tc.existing.Annotations[SecretLastRotatedAnnotationKey], secret.Annotations[SecretLastRotatedAnnotationKey])
+			}
+		})
+	}
+}
+
+func Test_BlueGreenSwap(t *testing.T) {
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	rollbackState := &State[*testv1alpha1.TestClaimed]{Name: "rollback"}
+	obj := &testv1alpha1.TestClaimed{ObjectMeta: metav1.ObjectMeta{Name: "foobar", Namespace: "default"}}
+	blue := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app-blue", Namespace: "default"}}
+	green := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app-green", Namespace: "default"}}
+
+	transition := func(c client.Client) TransitionFunc[*testv1alpha1.TestClaimed] {
+		return BlueGreenSwap[*testv1alpha1.TestClaimed](
+			c,
+			scheme,
+			func(*testv1alpha1.TestClaimed) client.Object { return blue.DeepCopy() },
+			func(*testv1alpha1.TestClaimed) client.Object { return green.DeepCopy() },
+			func(o client.Object) bool { return o.(*appsv1.Deployment).Status.ReadyReplicas > 0 },
+			time.Hour,
+			successState,
+			rollbackState,
+		)
+	}
+	names := func(objs []client.Object) []string {
+		var names []string
+		for _, o := range objs {
+			names = append(names, o.GetName())
+		}
+		return names
+	}
+
+	t.Run("swap", func(t *testing.T) {
+		ctx := context.Background()
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(blue.DeepCopy()).Build()
+
+		// creates the new version
+		out := NewOutputSet(scheme)
+		actualNextState, actualResult := transition(c)(ctx, obj, out)
+		assert.Nil(t, actualNextState)
+		assert.Equal(t, RequeueResult("waiting for *v1.Deployment default/app-green to become ready", 10*time.Second), actualResult)
+		assert.Equal(t, []string{"app-green"}, names(out.ListApplied()))
+		assert.Empty(t, out.ListDeleted())
+
+		// waits for the new version to become ready
+		created := green.DeepCopy()
+		created.CreationTimestamp = metav1.Now()
+		assert.NoError(t, c.Create(ctx, created))
+
+		out = NewOutputSet(scheme)
+		actualNextState, actualResult = transition(c)(ctx, obj, out)
+		assert.Nil(t, actualNextState)
+		assert.True(t, actualResult.HasRequeue())
+		assert.Empty(t, out.ListDeleted())
+
+		// deletes the old version once the new version is ready
+		created.Status.ReadyReplicas = 1
+		assert.NoError(t, c.Status().Update(ctx, created))
+
+		out = NewOutputSet(scheme)
+		actualNextState, actualResult = transition(c)(ctx, obj, out)
+		assert.Equal(t, successState, actualNextState)
+		assert.Equal(t, DoneResult(), actualResult)
+		assert.Equal(t, []string{"app-green"}, names(out.ListApplied()))
+		assert.Equal(t, []string{"app-blue"}, names(out.ListDeleted()))
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		ctx := context.Background()
+		created := green.DeepCopy()
+		created.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(blue.DeepCopy(), created).Build()
+
+		// deletes the new version, retaining the old version, once the timeout elapses
+		out := NewOutputSet(scheme)
+		actualNextState, actualResult := transition(c)(ctx, obj, out)
+		assert.Equal(t, rollbackState, actualNextState)
+		assert.Equal(t, DoneResult(), actualResult)
+		assert.Empty(t, out.ListApplied())
+		assert.Equal(t, []string{"app-green"}, names(out.ListDeleted()))
+
+		// records the rollback on the reconciled object
+		rolledBack := obj.DeepCopy()
+		for _, patch := range out.ListSelfPatches() {
+			patch(rolledBack)
+		}
+		assert.Contains(t, rolledBack.GetAnnotations(), BlueGreenRollbackAnnotationKey("app-green"))
+		assert.NoError(t, c.Delete(ctx, created))
+
+		// doesn't recreate the rolled back version on subsequent reconciles
+		out = NewOutputSet(scheme)
+		actualNextState, actualResult = transition(c)(ctx, rolledBack, out)
+		assert.Equal(t, rollbackState, actualNextState)
+		assert.Equal(t, DoneResult(), actualResult)
+		assert.Empty(t, out.ListApplied())
+		assert.Empty(t, out.ListDeleted())
+		assert.Empty(t, out.ListSelfPatches())
+	})
+}
+
+func Test_TransitionOnServerSupport(t *testing.T) {
+	supportedState := &State[*testv1alpha1.TestClaimed]{Name: "supported"}
+	unsupportedState := &State[*testv1alpha1.TestClaimed]{Name: "unsupported"}
+	pdbV1 := schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}
+	discoveryErr := errors.New("connection refused")
+
+	tcs := []struct {
+		name              string
+		resources         []*metav1.APIResourceList
+		err               error
+		expectedNextState *State[*testv1alpha1.TestClaimed]
+		expectedResult    Result
+	}{
+		{
+			name: "supported",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "policy/v1",
+					APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+				},
+			},
+			expectedNextState: supportedState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name: "unsupported",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "policy/v1beta1",
+					APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}},
+				},
+			},
+			expectedNextState: unsupportedState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name:           "discovery error",
+			err:            discoveryErr,
+			expectedResult: ErrorResultf("checking server support of %s: %w", pdbV1, fmt.Errorf("discovering resources of group version %s: %w", pdbV1.GroupVersion(), discoveryErr)),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: tc.resources}}
+			if tc.err != nil {
+				discoveryClient.AddReactor("get", "resource", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.err
+				})
+			}
+
+			transition := TransitionOnServerSupport[*testv1alpha1.TestClaimed](discoveryClient, pdbV1, supportedState, unsupportedState)
+			actualNextState, actualResult := transition(context.Background(), &testv1alpha1.TestClaimed{}, nil)
+
+			assert.Equal(t, tc.expectedNextState, actualNextState)
+			assert.Equal(t, tc.expectedResult, actualResult)
+			if tc.err != nil {
+				assert.ErrorIs(t, actualResult.Err, tc.err)
+			}
+		})
+	}
+}
+
 func Test_DeleteChildrenForeground(t *testing.T) {
 	log := zaptest.NewLogger(t).Sugar()
 	scheme, err := intscheme.NewScheme()
@@ -274,6 +898,7 @@ func Test_DeleteChildrenForeground(t *testing.T) {
 		children          []client.Object
 		expectedNextState *State[*testv1alpha1.TestClaimed]
 		expectedResult    Result
+		expectedPending   map[schema.GroupVersionKind]int
 	}{
 		{
 			name: "no children",
@@ -284,6 +909,7 @@ func Test_DeleteChildrenForeground(t *testing.T) {
 			},
 			expectedNextState: successState,
 			expectedResult:    DoneResult(),
+			expectedPending:   map[schema.GroupVersionKind]int{},
 		},
 		{
 			name: "with children that are not deleted",
@@ -310,6 +936,9 @@ test.infrared.reddit.com/v1alpha1, Kind=TestClaimed: /child-b`,
 				Reason: "WaitingForChildDeletion",
 				Done:   false,
 			},
+			expectedPending: map[schema.GroupVersionKind]int{
+				testv1alpha1.TestClaimedGroupVersionKind: 2,
+			},
 		},
 		{
 			name: "with children that are all deleted",
@@ -327,6 +956,7 @@ test.infrared.reddit.com/v1alpha1, Kind=TestClaimed: /child-b`,
 			children:          []client.Object{},
 			expectedNextState: successState,
 			expectedResult:    DoneResult(),
+			expectedPending:   map[schema.GroupVersionKind]int{},
 		},
 	}
 
@@ -345,7 +975,14 @@ test.infrared.reddit.com/v1alpha1, Kind=TestClaimed: /child-b`,
 				Applicator: io.NewAPIPatchingApplicator(fakeC),
 			}
 
-			actualNextState, actualResult := DeleteChildrenForeground[*testv1alpha1.TestClaimed](c, scheme, log, successState)(
+			recorder := &fakePendingChildDeletionsRecorder{}
+			actualNextState, actualResult := DeleteChildrenForeground[*testv1alpha1.TestClaimed](
+				c,
+				scheme,
+				log,
+				successState,
+				WithPendingChildDeletionsRecorder(recorder),
+			)(
 				ctx,
 				tt.parent,
 				nil,
@@ -353,10 +990,19 @@ test.infrared.reddit.com/v1alpha1, Kind=TestClaimed: /child-b`,
 
 			assert.Equal(t, tt.expectedNextState, actualNextState)
 			assert.Equal(t, tt.expectedResult, actualResult)
+			assert.Equal(t, tt.expectedPending, recorder.pending)
 		})
 	}
 }
 
+type fakePendingChildDeletionsRecorder struct {
+	pending map[schema.GroupVersionKind]int
+}
+
+func (f *fakePendingChildDeletionsRecorder) RecordPendingChildDeletions(_ client.Object, pending map[schema.GroupVersionKind]int) {
+	f.pending = pending
+}
+
 func Test_ErrorResultf(t *testing.T) {
 	type args struct {
 		format string
@@ -391,3 +1037,416 @@ func Test_ErrorResultf(t *testing.T) {
 		})
 	}
 }
+
+func Test_TransitionWhenGenerationObserved(t *testing.T) {
+	log := zaptest.NewLogger(t).Sugar()
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	requeueDuration := 10 * time.Second
+	const observedGenerationAnnotation = "example.com/observed-generation"
+
+	// ConfigMaps don't have status, so their observed generation is read from an annotation
+	annotationObservedGeneration := func(obj client.Object) (int64, bool) {
+		if _, ok := obj.(*corev1.ConfigMap); !ok {
+			return 0, false
+		}
+		value, ok := obj.GetAnnotations()[observedGenerationAnnotation]
+		if !ok {
+			return 0, true
+		}
+		observedGeneration, err := strconv.ParseInt(value, 10, 64)
+		return observedGeneration, err == nil
+	}
+
+	deployment := func(generation, observedGeneration int64) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "deployment",
+				Namespace:  "default",
+				Generation: generation,
+			},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: observedGeneration,
+			},
+		}
+	}
+	configMap := func(generation int64, observedGeneration string) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "configmap",
+				Namespace:  "default",
+				Generation: generation,
+			},
+		}
+		if observedGeneration != "" {
+			cm.Annotations = map[string]string{observedGenerationAnnotation: observedGeneration}
+		}
+		return cm
+	}
+
+	tcs := []struct {
+		name              string
+		children          []client.Object
+		options           []TransitionWhenGenerationObservedOption
+		expectedNextState *State[*testv1alpha1.TestClaimed]
+		expectedResult    Result
+	}{
+		{
+			name:              "caught up",
+			children:          []client.Object{deployment(2, 2)},
+			expectedNextState: successState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name:     "lagging",
+			children: []client.Object{deployment(2, 1)},
+			expectedResult: Result{
+				RequeueAfter: requeueDuration,
+				RequeueMsg:   "some managed resources have not observed their latest generation. First three:\napps/v1, Kind=Deployment: default/deployment",
+			},
+		},
+		{
+			name:     "no observed generation without extractor",
+			children: []client.Object{configMap(1, "1")},
+			expectedResult: Result{
+				RequeueAfter: requeueDuration,
+				RequeueMsg:   "some managed resources have not observed their latest generation. First three:\n/v1, Kind=ConfigMap: default/configmap",
+			},
+		},
+		{
+			name:              "caught up with extractor",
+			children:          []client.Object{deployment(2, 2), configMap(1, "1")},
+			options:           []TransitionWhenGenerationObservedOption{WithObservedGenerationFuncs(annotationObservedGeneration)},
+			expectedNextState: successState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name:     "lagging with extractor",
+			children: []client.Object{deployment(2, 2), configMap(1, "")},
+			options:  []TransitionWhenGenerationObservedOption{WithObservedGenerationFuncs(annotationObservedGeneration)},
+			expectedResult: Result{
+				RequeueAfter: requeueDuration,
+				RequeueMsg:   "some managed resources have not observed their latest generation. First three:\n/v1, Kind=ConfigMap: default/configmap",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := &testv1alpha1.TestClaimed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foobar",
+				},
+			}
+			c := fake.NewClientBuilder().
+				WithObjects(tc.children...).
+				WithScheme(scheme).
+				Build()
+
+			// children are re-fetched, so only their keys are needed
+			var children []client.Object
+			for _, child := range tc.children {
+				key := child.DeepCopyObject().(client.Object)
+				key.SetGeneration(0)
+				key.SetAnnotations(nil)
+				children = append(children, key)
+			}
+
+			transition := TransitionWhenGenerationObserved[*testv1alpha1.TestClaimed](
+				c,
+				scheme,
+				log,
+				children,
+				successState,
+				append(tc.options, WithGenerationObservedRequeueAfter(requeueDuration))...,
+			)
+
+			actualNextState, actualResult := transition(ctx, obj, nil)
+
+			assert.Equal(t, tc.expectedNextState, actualNextState)
+			assert.Equal(t, tc.expectedResult, actualResult)
+		})
+	}
+
+	t.Run("unresolvable type", func(t *testing.T) {
+		c := fake.NewClientBuilder().
+			WithObjects(deployment(2, 1)).
+			WithScheme(scheme).
+			Build()
+
+		// the transition's scheme doesn't register the child's type, so the lagging child can't be referenced
+		transition := TransitionWhenGenerationObserved[*testv1alpha1.TestClaimed](
+			c,
+			runtime.NewScheme(),
+			log,
+			[]client.Object{&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "default"}}},
+			successState,
+		)
+
+		actualNextState, actualResult := transition(context.Background(), &testv1alpha1.TestClaimed{}, nil)
+
+		assert.Nil(t, actualNextState)
+		assert.Error(t, actualResult.Err)
+		assert.Contains(t, actualResult.Err.Error(), "getting typed object ref for *v1.Deployment default/deployment")
+	})
+}
+
+func Test_TransitionWhenJobComplete(t *testing.T) {
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	failureState := &State[*testv1alpha1.TestClaimed]{Name: "failure"}
+	jobRef := client.ObjectKey{Name: "migrate", Namespace: "default"}
+
+	job := func(conditions ...batchv1.JobCondition) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobRef.Name,
+				Namespace: jobRef.Namespace,
+			},
+			Status: batchv1.JobStatus{
+				Conditions: conditions,
+			},
+		}
+	}
+
+	tcs := []struct {
+		name              string
+		job               *batchv1.Job
+		expectedNextState *State[*testv1alpha1.TestClaimed]
+		expectedResult    Result
+	}{
+		{
+			name: "running",
+			job:  job(),
+			expectedResult: Result{
+				RequeueAfter: 10 * time.Second,
+				RequeueMsg:   "waiting for batch/v1, Kind=Job: default/migrate to complete",
+			},
+		},
+		{
+			name: "running with false conditions",
+			job:  job(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionFalse}),
+			expectedResult: Result{
+				RequeueAfter: 10 * time.Second,
+				RequeueMsg:   "waiting for batch/v1, Kind=Job: default/migrate to complete",
+			},
+		},
+		{
+			name:              "succeeded",
+			job:               job(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}),
+			expectedNextState: successState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name:              "failed",
+			job:               job(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}),
+			expectedNextState: failureState,
+			expectedResult:    DoneResult(),
+		},
+		{
+			name: "deleted",
+			expectedResult: Result{
+				RequeueAfter: 10 * time.Second,
+				RequeueMsg:   "waiting for Job default/migrate to exist",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := &testv1alpha1.TestClaimed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foobar",
+				},
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.job != nil {
+				builder = builder.WithObjects(tc.job)
+			}
+			c := builder.Build()
+
+			transition := TransitionWhenJobComplete[*testv1alpha1.TestClaimed](c, scheme, jobRef, successState, failureState)
+
+			actualNextState, actualResult := transition(ctx, obj, nil)
+
+			assert.Equal(t, tc.expectedNextState, actualNextState)
+			assert.Equal(t, tc.expectedResult, actualResult)
+		})
+	}
+}
+
+func Test_EnsureChildMetadata(t *testing.T) {
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	upToDate := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "up-to-date",
+			Namespace:   "default",
+			Labels:      map[string]string{"team": "infrared"},
+			Annotations: map[string]string{"owner": "achilles"},
+		},
+	}
+	missing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "missing",
+			Namespace: "default",
+			// labels managed by other actors are retained
+			Labels: map[string]string{"app": "foo"},
+		},
+	}
+	// values set by other actors aren't overwritten
+	conflicting := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "conflicting",
+			Namespace: "default",
+			Labels:    map[string]string{"team": "other"},
+		},
+	}
+	deleted := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deleted",
+			Namespace: "default",
+		},
+	}
+	parent := &testv1alpha1.TestClaimed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "parent",
+		},
+		Status: testv1alpha1.TestClaimedStatus{
+			Resources: []api.TypedObjectRef{
+				*meta.MustTypedObjectRefFromObject(upToDate, scheme),
+				*meta.MustTypedObjectRefFromObject(missing, scheme),
+				*meta.MustTypedObjectRefFromObject(conflicting, scheme),
+				*meta.MustTypedObjectRefFromObject(deleted, scheme),
+			},
+		},
+	}
+
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(upToDate.DeepCopy(), missing.DeepCopy(), conflicting.DeepCopy()).Build()
+	transition := EnsureChildMetadata[*testv1alpha1.TestClaimed](
+		c,
+		scheme,
+		map[string]string{"team": "infrared"},
+		map[string]string{"owner": "achilles"},
+		successState,
+	)
+
+	get := func(o client.Object) client.Object {
+		actual := o.DeepCopyObject().(client.Object)
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(o), actual))
+		return actual
+	}
+
+	actualNextState, actualResult := transition(ctx, parent, NewOutputSet(scheme))
+	assert.Equal(t, successState, actualNextState)
+	assert.Equal(t, DoneResult(), actualResult)
+
+	resourceVersions := map[string]string{}
+	for _, o := range []client.Object{upToDate, missing, conflicting} {
+		actual := get(o)
+		assert.Equal(t, "achilles", actual.GetAnnotations()["owner"])
+		resourceVersions[o.GetName()] = actual.GetResourceVersion()
+	}
+	assert.Equal(t, "infrared", get(upToDate).GetLabels()["team"])
+	assert.Equal(t, "infrared", get(missing).GetLabels()["team"])
+	assert.Equal(t, "foo", get(missing).GetLabels()["app"])
+	assert.Equal(t, "other", get(conflicting).GetLabels()["team"])
+	// the up to date child isn't patched
+	assert.Equal(t, "999", resourceVersions[upToDate.Name])
+
+	// a second pass is a no-op
+	actualNextState, actualResult = transition(ctx, parent, NewOutputSet(scheme))
+	assert.Equal(t, successState, actualNextState)
+	assert.Equal(t, DoneResult(), actualResult)
+	for _, o := range []client.Object{upToDate, missing, conflicting} {
+		assert.Equal(t, resourceVersions[o.GetName()], get(o).GetResourceVersion())
+	}
+}
+
+func Test_GetManagedResource(t *testing.T) {
+	scheme, err := intscheme.NewScheme()
+	assert.NoError(t, err)
+
+	child := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "child",
+			Namespace: "default",
+		},
+		Data: map[string]string{"foo": "bar"},
+	}
+	deletedChild := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deleted-child",
+			Namespace: "default",
+		},
+	}
+	unmanaged := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unmanaged",
+			Namespace: "default",
+		},
+	}
+	parent := &testv1alpha1.TestClaimed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "parent",
+		},
+		Status: testv1alpha1.TestClaimedStatus{
+			Resources: []api.TypedObjectRef{
+				*meta.MustTypedObjectRefFromObject(child, scheme),
+				*meta.MustTypedObjectRefFromObject(deletedChild, scheme),
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(child.DeepCopy(), unmanaged.DeepCopy()).Build()
+
+	tests := []struct {
+		name          string
+		childName     string
+		expectedFound bool
+	}{
+		{
+			name:          "managed resource found",
+			childName:     "child",
+			expectedFound: true,
+		},
+		{
+			name:      "not referenced by parent",
+			childName: "unmanaged",
+		},
+		{
+			name:      "referenced by parent but deleted",
+			childName: "deleted-child",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, found, err := GetManagedResource[*corev1.ConfigMap](context.Background(), c, scheme, parent, tt.childName)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFound, found)
+			if !tt.expectedFound {
+				assert.Nil(t, actual)
+				return
+			}
+			assert.Equal(t, tt.childName, actual.Name)
+			assert.Equal(t, child.Data, actual.Data)
+		})
+	}
+
+	// a managed resource of a different type with the same name isn't returned
+	_, found, err := GetManagedResource[*corev1.Secret](context.Background(), c, scheme, parent, "child")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// types from which a new object can't be constructed are rejected rather than panicking
+	_, found, err = GetManagedResource[client.Object](context.Background(), c, scheme, parent, "child")
+	assert.Error(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,126 @@
+package fsm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
+	"github.com/reddit/achilles-sdk/pkg/meta"
+)
+
+const (
+	// unservableThreshold is the number of consecutive checks that must report a managed type as not served before it's
+	// considered unservable, so that transient discovery blips (e.g. while the kube-apiserver's discovery is refreshed)
+	// don't trip detection.
+	unservableThreshold = 3
+	// unservableLogInterval is the minimum interval between errors logged for a managed type that remains unservable.
+	unservableLogInterval = 10 * time.Minute
+)
+
+var _ manager.Runnable = &unservableTypeDetector{}
+
+// unservableTypeDetector periodically checks through discovery that the types managed by a controller are served by the
+// kube-apiserver, surfacing types that become unservable at runtime, e.g. because their CRD was deleted. Otherwise, the
+// controller's watches of such types error continuously while reconciles managing them fail opaquely.
+type unservableTypeDetector struct {
+	log             *zap.SugaredLogger
+	discoveryClient discovery.DiscoveryInterface
+	controllerName  string
+	gvks            []schema.GroupVersionKind
+	metrics         *metrics.Metrics
+	interval        time.Duration
+	// failFast, if true, stops the detector with an error once a managed type is unservable, which stops the manager.
+	failFast bool
+	clock    clock.PassiveClock
+
+	// notServed is the number of consecutive checks reporting each managed type as not served
+	notServed map[schema.GroupVersionKind]int
+	// lastLogged is the time at which an error was last logged for each unservable managed type
+	lastLogged map[schema.GroupVersionKind]time.Time
+}
+
+func newUnservableTypeDetector(
+	log *zap.SugaredLogger,
+	discoveryClient discovery.DiscoveryInterface,
+	controllerName string,
+	gvks []schema.GroupVersionKind,
+	metrics *metrics.Metrics,
+	interval time.Duration,
+	failFast bool,
+) *unservableTypeDetector {
+	return &unservableTypeDetector{
+		log:             log,
+		discoveryClient: discoveryClient,
+		controllerName:  controllerName,
+		gvks:            gvks,
+		metrics:         metrics,
+		interval:        interval,
+		failFast:        failFast,
+		clock:           clock.RealClock{},
+		notServed:       map[schema.GroupVersionKind]int{},
+		lastLogged:      map[schema.GroupVersionKind]time.Time{},
+	}
+}
+
+// Start implements manager.Runnable, checking the managed types every interval until the context is done.
+// Returns an error once a managed type is unservable if failFast is set.
+func (d *unservableTypeDetector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if unservable := d.check(); d.failFast && len(unservable) > 0 {
+				return fmt.Errorf("types managed by controller %q are no longer served by the kube-apiserver: %v", d.controllerName, unservable)
+			}
+		}
+	}
+}
+
+// check checks whether each managed type is served, returning the unservable types.
+func (d *unservableTypeDetector) check() []schema.GroupVersionKind {
+	var unservable []schema.GroupVersionKind
+	for _, gvk := range d.gvks {
+		served, err := meta.ServerSupports(d.discoveryClient, gvk)
+		if err != nil {
+			// discovery failures are inconclusive, so they neither count towards nor reset the threshold
+			d.log.Debugf("checking whether managed type %s is served: %s", gvk, err)
+			continue
+		}
+
+		if served {
+			if d.notServed[gvk] >= unservableThreshold {
+				d.log.Infof("managed type %s is served again", gvk)
+			}
+			delete(d.notServed, gvk)
+			delete(d.lastLogged, gvk)
+			d.metrics.RecordManagedTypeUnservable(d.controllerName, gvk, false)
+			continue
+		}
+
+		d.notServed[gvk]++
+		if d.notServed[gvk] < unservableThreshold {
+			continue
+		}
+
+		unservable = append(unservable, gvk)
+		d.metrics.RecordManagedTypeUnservable(d.controllerName, gvk, true)
+
+		if lastLogged, ok := d.lastLogged[gvk]; !ok || d.clock.Since(lastLogged) >= unservableLogInterval {
+			d.log.Errorf("managed type %s is no longer served by the kube-apiserver, its CRD may have been deleted. "+
+				"Watches and reconciles managing it fail until it's served again", gvk)
+			d.lastLogged[gvk] = d.clock.Now()
+		}
+	}
+	return unservable
+}
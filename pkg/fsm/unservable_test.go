@@ -0,0 +1,142 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/reddit/achilles-sdk/pkg/fsm/metrics"
+	"github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+)
+
+func TestUnservableTypeDetector(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	testClaimGVK := v1alpha1.TestClaimGroupVersionKind
+
+	coreResources := &metav1.APIResourceList{
+		GroupVersion: corev1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap"}},
+	}
+	testResources := &metav1.APIResourceList{
+		GroupVersion: testClaimGVK.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "testclaims", Kind: "TestClaim"}},
+	}
+
+	var discoveryErr error
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{coreResources, testResources}}}
+	discoveryClient.AddReactor("get", "resource", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return discoveryErr != nil, nil, discoveryErr
+	})
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	reg := prometheus.NewRegistry()
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+
+	d := newUnservableTypeDetector(zap.New(core).Sugar(), discoveryClient, "test-claim",
+		[]schema.GroupVersionKind{configMapGVK, testClaimGVK}, metrics.MustMakeMetrics(scheme, reg), time.Minute, false)
+	d.clock = fakeClock
+
+	assertUnservable := func(t *testing.T, unservable int) {
+		t.Helper()
+		expected := fmt.Sprintf(`
+# HELP achilles_managed_type_unservable Gauge reporting whether a type managed by the controller is no longer served by the kube-apiserver, e.g. because its CRD was deleted.
+# TYPE achilles_managed_type_unservable gauge
+achilles_managed_type_unservable{controller="test-claim",group="",kind="ConfigMap",version="v1"} 0
+achilles_managed_type_unservable{controller="test-claim",group="test.infrared.reddit.com",kind="TestClaim",version="v1alpha1"} %d
+`, unservable)
+		if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "achilles_managed_type_unservable"); err != nil {
+			t.Error(err)
+		}
+	}
+	errorLogs := func() int {
+		return logs.FilterLevelExact(zapcore.ErrorLevel).FilterMessageSnippet("no longer served").Len()
+	}
+
+	// all managed types are served
+	if unservable := d.check(); len(unservable) != 0 {
+		t.Errorf("expected no unservable types, got %v", unservable)
+	}
+	assertUnservable(t, 0)
+
+	// the TestClaim CRD is deleted, which isn't reported until it's been unserved for consecutive checks
+	discoveryClient.Resources = []*metav1.APIResourceList{coreResources}
+	for i := 0; i < unservableThreshold-1; i++ {
+		if unservable := d.check(); len(unservable) != 0 {
+			t.Errorf("expected no unservable types before threshold, got %v", unservable)
+		}
+	}
+
+	// transient discovery failures don't count towards or reset the threshold
+	discoveryErr = errors.New("connection refused")
+	if unservable := d.check(); len(unservable) != 0 {
+		t.Errorf("expected no unservable types on discovery failure, got %v", unservable)
+	}
+	discoveryErr = nil
+	assertUnservable(t, 0)
+	if errorLogs() != 0 {
+		t.Errorf("expected no errors logged before threshold, got %d", errorLogs())
+	}
+
+	unservable := d.check()
+	if len(unservable) != 1 || unservable[0] != testClaimGVK {
+		t.Errorf("expected unservable type %s, got %v", testClaimGVK, unservable)
+	}
+	assertUnservable(t, 1)
+	if errorLogs() != 1 {
+		t.Errorf("expected 1 error logged, got %d", errorLogs())
+	}
+
+	// errors are rate limited while the type remains unservable
+	d.check()
+	if errorLogs() != 1 {
+		t.Errorf("expected rate limited errors, got %d", errorLogs())
+	}
+	fakeClock.SetTime(fakeClock.Now().Add(unservableLogInterval))
+	d.check()
+	if errorLogs() != 2 {
+		t.Errorf("expected error to be logged again after %s, got %d", unservableLogInterval, errorLogs())
+	}
+
+	// the TestClaim CRD is recreated
+	discoveryClient.Resources = []*metav1.APIResourceList{coreResources, testResources}
+	if unservable := d.check(); len(unservable) != 0 {
+		t.Errorf("expected no unservable types, got %v", unservable)
+	}
+	assertUnservable(t, 0)
+	if logs.FilterMessageSnippet("is served again").Len() != 1 {
+		t.Errorf("expected recovery to be logged")
+	}
+}
+
+func TestUnservableTypeDetector_FailFast(t *testing.T) {
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	d := newUnservableTypeDetector(zap.NewNop().Sugar(), discoveryClient, "test-claim",
+		[]schema.GroupVersionKind{v1alpha1.TestClaimGroupVersionKind}, metrics.MustMakeMetrics(scheme, prometheus.NewRegistry()), 10*time.Millisecond, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := d.Start(ctx)
+	if err == nil || !strings.Contains(err.Error(), "no longer served by the kube-apiserver") {
+		t.Errorf("expected error for unservable type, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected detector to fail before the context is done")
+	}
+}
@@ -1,20 +1,26 @@
 package io
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
+	"slices"
+	"strings"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/reddit/achilles-sdk/pkg/encoding/json"
 	liberrors "github.com/reddit/achilles-sdk/pkg/errors"
+	"github.com/reddit/achilles-sdk/pkg/meta"
 )
 
 // A ClientApplicator may be used to build a single 'client' that satisfies both
@@ -24,12 +30,49 @@ type ClientApplicator struct {
 	Applicator
 }
 
+// DeleteIfOwned deletes the object only if it's owned by owner, i.e. if it has an owner reference to owner, or if it's
+// labelled as managed by controllerName (see meta.RedditLabels). If controllerName is empty, only owner references are considered.
+// Returns ObjectNotOwned if the object exists but isn't owned, and nil if the object doesn't exist.
+func (c *ClientApplicator) DeleteIfOwned(ctx context.Context, o client.Object, owner client.Object, controllerName string) error {
+	current := o.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(o), current); kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("cannot get object: %w", err)
+	}
+
+	if !isOwnedBy(current, owner, controllerName) {
+		return ObjectNotOwned{Object: client.ObjectKeyFromObject(current), Owner: client.ObjectKeyFromObject(owner)}
+	}
+
+	// the UID precondition guards against deleting an object recreated since it was checked
+	if err := c.Delete(ctx, current, client.Preconditions{UID: ptr.To(current.GetUID())}); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("cannot delete object: %w", err)
+	}
+	return nil
+}
+
+// isOwnedBy returns true if o has an owner reference to owner, or is labelled as managed by controllerName if not empty.
+func isOwnedBy(o client.Object, owner client.Object, controllerName string) bool {
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return controllerName != "" && o.GetLabels()[meta.ManagedByKey] == controllerName
+}
+
 // An Applicator applies changes to an object.
 type Applicator interface {
 	Apply(context.Context, client.Object, ...ApplyOption) error
 	ApplyStatus(context.Context, client.Object, ...ApplyOption) error
 }
 
+// A ScaleApplicator applies changes to an object's scale subresource.
+type ScaleApplicator interface {
+	ApplyScale(ctx context.Context, o client.Object, replicas int32) error
+}
+
 // An ApplyOption mutates the desired object before applying
 type ApplyOption func(ctx context.Context, o client.Object, requestOpts *RequestOptions) error
 
@@ -47,11 +90,90 @@ type RequestOptions struct {
 	// managed resources.
 	WithoutOwnerRefs bool
 
+	// MergeLabels, if true, unions the object's existing labels with the desired labels (desired values take precedence),
+	// so that labels set by other actors are preserved regardless of whether the object is patched or updated.
+	MergeLabels bool
+	// RemoveLabels are label keys removed from the object when MergeLabels is true.
+	RemoveLabels []string
+
+	// MergeAnnotations, if true, unions the object's existing annotations with the desired annotations (desired values take precedence),
+	// so that annotations set by other actors are preserved regardless of whether the object is patched or updated.
+	MergeAnnotations bool
+	// RemoveAnnotations are annotation keys removed from the object when MergeAnnotations is true.
+	RemoveAnnotations []string
+
+	// AdoptExisting, if true, adopts existing objects that aren't controlled by another owner by adding the desired
+	// owner references and labels to the object's existing ones. Existing objects controlled by another owner are not modified.
+	AdoptExisting bool
+
+	// LastApplied, if true, records the applied object in the LastAppliedAnnotationKey annotation.
+	LastApplied bool
+
+	// PatchLogger, if not nil, is invoked with the body of each merge patch sent to the kube-apiserver, for debugging.
+	PatchLogger func(patch []byte)
+
+	// FieldManager, if not empty, is the name of the field manager set on create, update, and patch requests.
+	FieldManager string
+
+	// StrategicMerge, if true, patches built-in types with a strategic merge patch, which merges lists (e.g. a pod's
+	// containers) by key rather than replacing them. Other types are patched with a JSON merge patch.
+	StrategicMerge bool
+
+	// CreateOnlyFields are dot-separated field paths (e.g. "spec.selector") that are only set when the object is created.
+	// On update, the values of these fields are taken from the existing object, so that immutable fields are never changed.
+	CreateOnlyFields []string
+
+	// ignoredAnnotations are annotation keys excluded when comparing the desired and existing object.
+	ignoredAnnotations []string
+
+	// knownResourceVersion is true if the caller supplied the resource version of the existing object.
+	knownResourceVersion bool
+
 	// hasExplicitOwnerRefs is true if the caller explicitly sets ownerReferences
 	// This flag, if true, prevents the FSM reconciler from adding the default controller reference.
 	hasExplicitOwnerRefs bool
 }
 
+// requiresCurrent returns true if the options depend on the existing object.
+func (o *RequestOptions) requiresCurrent() bool {
+	return o.MergeLabels || o.MergeAnnotations || o.AdoptExisting || len(o.CreateOnlyFields) > 0
+}
+
+func (o *RequestOptions) createOptions() []client.CreateOption {
+	if o.FieldManager == "" {
+		return nil
+	}
+	return []client.CreateOption{client.FieldOwner(o.FieldManager)}
+}
+
+func (o *RequestOptions) updateOptions() []client.UpdateOption {
+	if o.FieldManager == "" {
+		return nil
+	}
+	return []client.UpdateOption{client.FieldOwner(o.FieldManager)}
+}
+
+func (o *RequestOptions) patchOptions() []client.PatchOption {
+	if o.FieldManager == "" {
+		return nil
+	}
+	return []client.PatchOption{client.FieldOwner(o.FieldManager)}
+}
+
+func (o *RequestOptions) subResourceUpdateOptions() []client.SubResourceUpdateOption {
+	if o.FieldManager == "" {
+		return nil
+	}
+	return []client.SubResourceUpdateOption{client.FieldOwner(o.FieldManager)}
+}
+
+func (o *RequestOptions) subResourcePatchOptions() []client.SubResourcePatchOption {
+	if o.FieldManager == "" {
+		return nil
+	}
+	return []client.SubResourcePatchOption{client.FieldOwner(o.FieldManager)}
+}
+
 // An APIPatchingApplicator applies changes to an object by either creating or
 // patching it in a Kubernetes API server.
 // For a detailed discussion of the reasoning behind these semantics, see this doc,
@@ -83,6 +205,12 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 
 	desired := current.DeepCopyObject().(client.Object)
 
+	// the caller supplied the resource version of the existing object, so it's updated without being read,
+	// relying on the optimistic lock to reject updates of stale resource versions
+	if updatesKnownResourceVersion(ctx, desired, opts) {
+		return a.updateKnownResourceVersion(ctx, desired, requestOpts, opts)
+	}
+
 	err := a.client.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, current)
 	if kerrors.IsNotFound(err) {
 		return a.createNewObject(ctx, current, requestOpts, opts)
@@ -95,6 +223,23 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 		return fmt.Errorf("applying options: %w", err)
 	}
 
+	if requestOpts.MergeLabels {
+		desired.SetLabels(mergeKeys(current.GetLabels(), desired.GetLabels(), requestOpts.RemoveLabels))
+	}
+	if requestOpts.MergeAnnotations {
+		desired.SetAnnotations(mergeKeys(current.GetAnnotations(), desired.GetAnnotations(), requestOpts.RemoveAnnotations))
+	}
+	if requestOpts.AdoptExisting {
+		if err := adoptExisting(current, desired); err != nil {
+			return err
+		}
+	}
+	if requestOpts.LastApplied {
+		if err := setLastAppliedAnnotation(desired); err != nil {
+			return err
+		}
+	}
+
 	// If there is no difference, we need not perform an update. We convert each into
 	// unstructured data and remove status fields before the comparison.
 	before, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
@@ -107,6 +252,15 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 		return fmt.Errorf("converting desired obj to unstructured: %w", err)
 	}
 
+	if len(requestOpts.CreateOnlyFields) > 0 {
+		if err := retainCreateOnlyFields(before, after, requestOpts.CreateOnlyFields); err != nil {
+			return fmt.Errorf("retaining create-only fields: %w", err)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(after, desired); err != nil {
+			return fmt.Errorf("converting desired obj from unstructured: %w", err)
+		}
+	}
+
 	// https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#subresources
 	hasStatusSubresource := false
 	for _, managedFields := range current.GetManagedFields() {
@@ -123,7 +277,11 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 		unstructured.RemoveNestedField(after, "status")
 	}
 
-	if reflect.DeepEqual(before, after) {
+	for _, u := range []map[string]interface{}{before, after} {
+		removeAnnotations(u, requestOpts.ignoredAnnotations)
+	}
+
+	if semanticDeepEqual(before, after) {
 		return nil
 	}
 
@@ -135,7 +293,7 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 			desired.SetResourceVersion(current.GetResourceVersion())
 		}
 
-		if err = a.client.Update(ctx, desired); err != nil {
+		if err = a.client.Update(ctx, desired, requestOpts.updateOptions()...); err != nil {
 			return fmt.Errorf("cannot update object: %w", err)
 		}
 	} else {
@@ -144,8 +302,18 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 			// ignore optimistic resource lock if `WithOptimisticLock` wasn't specified
 			desired.SetResourceVersion("")
 		}
-		p := &patch{from: desired}
-		if err = a.client.Patch(ctx, current, p); err != nil {
+		p := &patch{from: desired, logger: requestOpts.PatchLogger, patchType: types.MergePatchType}
+		if requestOpts.StrategicMerge && a.isBuiltIn(desired) {
+			p.patchType = types.StrategicMergePatchType
+		}
+		// keys absent from a JSON merge patch are left untouched, so removed keys must be explicitly nulled
+		if requestOpts.MergeLabels {
+			p.removeLabels = presentKeys(current.GetLabels(), requestOpts.RemoveLabels)
+		}
+		if requestOpts.MergeAnnotations {
+			p.removeAnnotations = presentKeys(current.GetAnnotations(), requestOpts.RemoveAnnotations)
+		}
+		if err = a.client.Patch(ctx, current, p, requestOpts.patchOptions()...); err != nil {
 			return fmt.Errorf("cannot patch object: %w", err)
 		}
 	}
@@ -153,6 +321,35 @@ func (a *APIApplicator) Apply(ctx context.Context, current client.Object, opts .
 	return nil
 }
 
+// updatesKnownResourceVersion returns true if the options update the object with the resource version of the existing
+// object (see WithKnownResourceVersion), and don't otherwise depend on the existing object.
+// The options are evaluated against throwaway copies, so neither the object nor the request options are mutated.
+func updatesKnownResourceVersion(ctx context.Context, o client.Object, opts []ApplyOption) bool {
+	requestOpts := &RequestOptions{}
+	if err := applyOpts(ctx, o.DeepCopyObject().(client.Object), requestOpts, opts); err != nil {
+		// surface option errors through the regular apply path
+		return false
+	}
+	return requestOpts.Update && requestOpts.knownResourceVersion && !requestOpts.requiresCurrent()
+}
+
+// updateKnownResourceVersion updates the object with options applied, without first reading the existing object
+func (a *APIApplicator) updateKnownResourceVersion(ctx context.Context, desired client.Object, requestOpts *RequestOptions, opts []ApplyOption) error {
+	if err := applyOpts(ctx, desired, requestOpts, opts); err != nil {
+		return fmt.Errorf("applying options: %w", err)
+	}
+	if requestOpts.LastApplied {
+		if err := setLastAppliedAnnotation(desired); err != nil {
+			return err
+		}
+	}
+
+	if err := a.client.Update(ctx, desired, requestOpts.updateOptions()...); err != nil {
+		return fmt.Errorf("cannot update object: %w", err)
+	}
+	return nil
+}
+
 // createNewObject handles creating a new object with options applied
 func (a *APIApplicator) createNewObject(ctx context.Context, obj client.Object, requestOpts *RequestOptions, opts []ApplyOption) error {
 	// apply options to obj
@@ -163,12 +360,79 @@ func (a *APIApplicator) createNewObject(ctx context.Context, obj client.Object,
 		return err
 	}
 
-	if err := a.client.Create(ctx, obj); err != nil {
+	if requestOpts.MergeLabels {
+		obj.SetLabels(mergeKeys(nil, obj.GetLabels(), requestOpts.RemoveLabels))
+	}
+	if requestOpts.MergeAnnotations {
+		obj.SetAnnotations(mergeKeys(nil, obj.GetAnnotations(), requestOpts.RemoveAnnotations))
+	}
+	if requestOpts.LastApplied {
+		if err := setLastAppliedAnnotation(obj); err != nil {
+			return err
+		}
+	}
+
+	if err := a.client.Create(ctx, obj, requestOpts.createOptions()...); err != nil {
 		return fmt.Errorf("cannot create object: %w", err)
 	}
 	return nil
 }
 
+// removeAnnotations removes the annotation keys from the unstructured object, along with its annotations if none remain.
+func removeAnnotations(u map[string]interface{}, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	for _, key := range keys {
+		unstructured.RemoveNestedField(u, "metadata", "annotations", key)
+	}
+	if annotations, _, _ := unstructured.NestedMap(u, "metadata", "annotations"); len(annotations) == 0 {
+		unstructured.RemoveNestedField(u, "metadata", "annotations")
+	}
+}
+
+// lastAppliedExcludedFields are fields excluded from the last-applied annotation because they're either
+// populated by the server or not managed through apply.
+var lastAppliedExcludedFields = [][]string{
+	{"metadata", "annotations", LastAppliedAnnotationKey},
+	{"metadata", "annotations", ReconciledByVersionAnnotationKey},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "uid"},
+	{"status"},
+}
+
+// setLastAppliedAnnotation sets the LastAppliedAnnotationKey annotation to the canonical JSON encoding of the object,
+// i.e. with object keys sorted, excluding the annotation itself to avoid recursion.
+func setLastAppliedAnnotation(o client.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return fmt.Errorf("converting obj to unstructured: %w", err)
+	}
+	for _, fields := range lastAppliedExcludedFields {
+		unstructured.RemoveNestedField(u, fields...)
+	}
+	if annotations, _, _ := unstructured.NestedMap(u, "metadata", "annotations"); len(annotations) == 0 {
+		unstructured.RemoveNestedField(u, "metadata", "annotations")
+	}
+
+	// maps are encoded with sorted keys, so the encoding is stable across applies of the same object
+	b, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("marshalling last-applied configuration: %w", err)
+	}
+
+	annotations := o.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastAppliedAnnotationKey] = string(b)
+	o.SetAnnotations(annotations)
+	return nil
+}
+
 // ApplyStatus updates the object's status subresource. If the object does not exist, an
 // error will be returned.
 func (a *APIApplicator) ApplyStatus(ctx context.Context, o client.Object, opts ...ApplyOption) error {
@@ -213,7 +477,7 @@ func (a *APIApplicator) ApplyStatus(ctx context.Context, o client.Object, opts .
 		return fmt.Errorf("copying nested field from desired unstructured: %w", err)
 	}
 
-	if (!hasBeforeStatus && !hasAfterStatus) || reflect.DeepEqual(beforeStatus, afterStatus) {
+	if (!hasBeforeStatus && !hasAfterStatus) || semanticDeepEqual(beforeStatus, afterStatus) {
 		return nil
 	}
 
@@ -231,7 +495,7 @@ func (a *APIApplicator) ApplyStatus(ctx context.Context, o client.Object, opts .
 			desired.SetResourceVersion(current.GetResourceVersion())
 		}
 
-		if err = a.client.Status().Update(ctx, desired); err != nil {
+		if err = a.client.Status().Update(ctx, desired, requestOpts.subResourceUpdateOptions()...); err != nil {
 			return fmt.Errorf("cannot update object status: %w", err)
 		}
 	} else {
@@ -240,7 +504,8 @@ func (a *APIApplicator) ApplyStatus(ctx context.Context, o client.Object, opts .
 			// ignore optimistic resource lock if `WithOptimisticLock` wasn't specified
 			desired.SetResourceVersion("")
 		}
-		if err = a.client.Status().Patch(ctx, current, &patch{from: desired}); err != nil {
+		p := &patch{from: desired, logger: requestOpts.PatchLogger, patchType: types.MergePatchType}
+		if err = a.client.Status().Patch(ctx, current, p, requestOpts.subResourcePatchOptions()...); err != nil {
 			return fmt.Errorf("cannot patch object status: %w", err)
 		}
 	}
@@ -248,11 +513,169 @@ func (a *APIApplicator) ApplyStatus(ctx context.Context, o client.Object, opts .
 	return nil
 }
 
-type patch struct{ from runtime.Object }
+// ApplyScale sets the object's replicas through its scale subresource, e.g. for scaling a Deployment without applying its spec.
+// The scale subresource is only updated if its replicas differ. The supplied object is not mutated.
+// If the object exists but its type doesn't support the scale subresource, a ScaleSubresourceUnsupported error is returned.
+func (a *APIApplicator) ApplyScale(ctx context.Context, o client.Object, replicas int32) error {
+	scale := &autoscalingv1.Scale{}
+	err := a.client.SubResource("scale").Get(ctx, o, scale)
+	if kerrors.IsNotFound(err) {
+		// the kube-apiserver responds with not found both for missing objects and for types without a scale subresource
+		if err := a.client.Get(ctx, client.ObjectKeyFromObject(o), o.DeepCopyObject().(client.Object)); kerrors.IsNotFound(err) {
+			return errors.New("object does not exist, cannot update its scale")
+		} else if err != nil {
+			return fmt.Errorf("cannot get object: %w", err)
+		}
+		gvk, err := a.client.GroupVersionKindFor(o)
+		if err != nil {
+			return fmt.Errorf("getting object GVK: %w", err)
+		}
+		return ScaleSubresourceUnsupported{GroupVersionKind: gvk}
+	} else if err != nil {
+		return fmt.Errorf("cannot get object scale: %w", err)
+	}
+
+	if scale.Spec.Replicas == replicas {
+		return nil
+	}
+
+	// the scale's resource version is retained, so the update fails on conflict if the object was concurrently scaled
+	scale.Spec.Replicas = replicas
+	if err := a.client.SubResource("scale").Update(ctx, o, client.WithSubResourceBody(scale)); err != nil {
+		return fmt.Errorf("cannot update object scale: %w", err)
+	}
+
+	return nil
+}
+
+// retainCreateOnlyFields overwrites the fields of desired at the given paths with those of current,
+// removing them from desired if they are absent on current.
+func retainCreateOnlyFields(current, desired map[string]interface{}, paths []string) error {
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		val, found, err := unstructured.NestedFieldCopy(current, fields...)
+		if err != nil {
+			return fmt.Errorf("reading field %q: %w", path, err)
+		}
+		if !found {
+			unstructured.RemoveNestedField(desired, fields...)
+			continue
+		}
+		if err := unstructured.SetNestedField(desired, val, fields...); err != nil {
+			return fmt.Errorf("setting field %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// mergeKeys returns the union of current and desired, with values of desired taking precedence, excluding removed keys.
+func mergeKeys(current, desired map[string]string, remove []string) map[string]string {
+	if current == nil && desired == nil {
+		return nil
+	}
+	merged := make(map[string]string, len(current)+len(desired))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	for _, k := range remove {
+		delete(merged, k)
+	}
+	return merged
+}
+
+// adoptExisting adds the existing owner references and labels of current to desired, so that applying desired adopts
+// the object without removing owner references or labels set by other actors.
+// Returns ObjectControlledByAnotherOwner if current is controlled by an owner other than desired's controller.
+func adoptExisting(current, desired client.Object) error {
+	if controller := metav1.GetControllerOfNoCopy(current); controller != nil {
+		if desiredController := metav1.GetControllerOfNoCopy(desired); desiredController == nil || desiredController.UID != controller.UID {
+			return ObjectControlledByAnotherOwner{Owner: *controller}
+		}
+	}
+
+	ownerRefs := desired.GetOwnerReferences()
+	for _, ref := range current.GetOwnerReferences() {
+		if !slices.ContainsFunc(ownerRefs, func(r metav1.OwnerReference) bool { return r.UID == ref.UID }) {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	desired.SetOwnerReferences(ownerRefs)
+	desired.SetLabels(mergeKeys(current.GetLabels(), desired.GetLabels(), nil))
+
+	return nil
+}
+
+// presentKeys returns the keys that are present in m.
+func presentKeys(m map[string]string, keys []string) []string {
+	var present []string
+	for _, k := range keys {
+		if _, ok := m[k]; ok {
+			present = append(present, k)
+		}
+	}
+	return present
+}
+
+type patch struct {
+	from runtime.Object
+
+	// label and annotation keys to delete, serialized as null values
+	removeLabels      []string
+	removeAnnotations []string
+
+	// logger, if not nil, is invoked with a copy of the patch data
+	logger func([]byte)
+
+	// patchType is either a JSON merge patch or, for built-in types, a strategic merge patch
+	patchType types.PatchType
+}
 
 // TODO switch to server side apply
-func (p *patch) Type() types.PatchType                { return types.MergePatchType }
-func (p *patch) Data(_ client.Object) ([]byte, error) { return json.Marshal(p.from) }
+func (p *patch) Type() types.PatchType { return p.patchType }
+func (p *patch) Data(_ client.Object) ([]byte, error) {
+	data, err := p.data()
+	if err == nil && p.logger != nil {
+		p.logger(bytes.Clone(data))
+	}
+	return data, err
+}
+
+func (p *patch) data() ([]byte, error) {
+	if len(p.removeLabels) == 0 && len(p.removeAnnotations) == 0 {
+		return json.Marshal(p.from)
+	}
+
+	data, err := json.Marshal(p.from)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // preserve integer precision
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	for field, keys := range map[string][]string{"labels": p.removeLabels, "annotations": p.removeAnnotations} {
+		for _, k := range keys {
+			if err := unstructured.SetNestedField(obj, nil, "metadata", field, k); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// isBuiltIn returns true if the object is of a built-in Kubernetes type, the only types supporting strategic merge patches.
+func (a *APIApplicator) isBuiltIn(o client.Object) bool {
+	gvk, err := a.client.GroupVersionKindFor(o)
+	if err != nil {
+		return false
+	}
+	return clientgoscheme.Scheme.Recognizes(gvk)
+}
 
 // apply the apply options, mutating the specified object and request opts
 func applyOpts(ctx context.Context, o client.Object, requestOpts *RequestOptions, opts []ApplyOption) error {
@@ -1,16 +1,20 @@
 package io_test
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -424,6 +428,551 @@ var _ = Describe("Applicator", func() {
 		})
 	})
 
+	It("should only set create-only fields on creation", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deployment-create-only",
+				Namespace: "default",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "foo"},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app": "foo", "selector": "bar"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "main", Image: "image:v1"},
+						},
+					},
+				},
+			},
+		}
+
+		By("setting the create-only field when creating the object", func() {
+			Expect(applicator.Apply(ctx, deployment.DeepCopy(), io.WithCreateOnlyFields("spec.selector"))).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				actual := &appsv1.Deployment{}
+				g.Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+				g.Expect(actual.Spec.Selector).To(Equal(deployment.Spec.Selector))
+			}).Should(Succeed())
+		})
+
+		By("stripping the create-only field when updating the object", func() {
+			updated := deployment.DeepCopy()
+			// spec.selector is immutable, changing it would be rejected by the API server
+			updated.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"selector": "bar"},
+			}
+			updated.Spec.Template.Spec.Containers[0].Image = "image:v2"
+
+			Expect(applicator.Apply(ctx, updated.DeepCopy(), io.WithCreateOnlyFields("spec.selector"))).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				actual := &appsv1.Deployment{}
+				g.Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+				g.Expect(actual.Spec.Selector).To(Equal(deployment.Spec.Selector))
+				g.Expect(actual.Spec.Template.Spec.Containers[0].Image).To(Equal("image:v2"))
+			}).Should(Succeed())
+		})
+
+		By("stripping the create-only field when updating the object with an update request", func() {
+			updated := deployment.DeepCopy()
+			updated.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"selector": "bar"},
+			}
+			updated.Spec.Template.Spec.Containers[0].Image = "image:v3"
+
+			Expect(applicator.Apply(ctx, updated.DeepCopy(), io.WithCreateOnlyFields("spec.selector"), io.AsUpdate())).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				actual := &appsv1.Deployment{}
+				g.Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+				g.Expect(actual.Spec.Selector).To(Equal(deployment.Spec.Selector))
+				g.Expect(actual.Spec.Template.Spec.Containers[0].Image).To(Equal("image:v3"))
+			}).Should(Succeed())
+		})
+	})
+
+	It("should merge labels and annotations", func() {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cm-merge-labels",
+				Namespace:   "default",
+				Labels:      map[string]string{"owned": "v1", "owned-stale": "v1"},
+				Annotations: map[string]string{"owned": "v1", "owned-stale": "v1"},
+			},
+		}
+
+		By("creating the object", func() {
+			Expect(applicator.Apply(ctx, cm.DeepCopy(), io.WithMergeLabels(), io.WithMergeAnnotations())).To(Succeed())
+		})
+
+		By("adding labels and annotations from another actor", func() {
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+			actual.Labels["foreign"] = "foreign"
+			actual.Annotations["foreign"] = "foreign"
+			Expect(c.Update(ctx, actual)).To(Succeed())
+		})
+
+		for _, tc := range []struct {
+			name string
+			opts []io.ApplyOption
+		}{
+			{name: "patch", opts: []io.ApplyOption{}},
+			{name: "update", opts: []io.ApplyOption{io.AsUpdate()}},
+		} {
+			By("preserving foreign labels and annotations with "+tc.name, func() {
+				desired := cm.DeepCopy()
+				desired.Labels = map[string]string{"owned": tc.name}
+				desired.Annotations = map[string]string{"owned": tc.name}
+
+				Expect(applicator.Apply(ctx, desired, append(tc.opts,
+					io.WithMergeLabels("owned-stale"),
+					io.WithMergeAnnotations("owned-stale"),
+				)...)).To(Succeed())
+
+				Eventually(func(g Gomega) {
+					actual := &corev1.ConfigMap{}
+					g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+					g.Expect(actual.Labels).To(Equal(map[string]string{"owned": tc.name, "foreign": "foreign"}))
+					g.Expect(actual.Annotations).To(Equal(map[string]string{"owned": tc.name, "foreign": "foreign"}))
+				}).Should(Succeed())
+			})
+		}
+	})
+
+	It("should adopt existing objects", func() {
+		owner := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cm-adopt-owner",
+				Namespace: "default",
+			},
+		}
+		Expect(c.Create(ctx, owner)).To(Succeed())
+
+		By("adopting an unowned object", func() {
+			unowned := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-adopt-unowned",
+					Namespace: "default",
+					Labels:    map[string]string{"foreign": "foreign"},
+				},
+			}
+			Expect(c.Create(ctx, unowned.DeepCopy())).To(Succeed())
+
+			desired := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      unowned.Name,
+					Namespace: unowned.Namespace,
+					Labels:    map[string]string{"owned": "owned"},
+				},
+				Data: map[string]string{"foo": "bar"},
+			}
+			Expect(applicator.Apply(ctx, desired, io.WithControllerRef(owner, scheme.Scheme), io.WithAdoptExisting())).To(Succeed())
+
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(unowned), actual)).To(Succeed())
+			Expect(actual.Labels).To(Equal(map[string]string{"foreign": "foreign", "owned": "owned"}))
+			Expect(actual.Data).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(metav1.IsControlledBy(actual, owner)).To(BeTrue())
+		})
+
+		By("refusing to adopt an object controlled by another owner", func() {
+			otherOwner := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-adopt-other-owner",
+					Namespace: "default",
+				},
+			}
+			Expect(c.Create(ctx, otherOwner)).To(Succeed())
+
+			foreign := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-adopt-foreign",
+					Namespace: "default",
+				},
+			}
+			Expect(controllerutil.SetControllerReference(otherOwner, foreign, scheme.Scheme)).To(Succeed())
+			Expect(c.Create(ctx, foreign.DeepCopy())).To(Succeed())
+
+			desired := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      foreign.Name,
+					Namespace: foreign.Namespace,
+				},
+				Data: map[string]string{"foo": "bar"},
+			}
+			err := applicator.Apply(ctx, desired, io.WithControllerRef(owner, scheme.Scheme), io.WithAdoptExisting())
+			Expect(err).To(MatchError(io.ObjectControlledByAnotherOwner{Owner: foreign.OwnerReferences[0]}))
+
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(foreign), actual)).To(Succeed())
+			Expect(actual.Data).To(BeEmpty())
+			Expect(metav1.IsControlledBy(actual, otherOwner)).To(BeTrue())
+		})
+	})
+
+	It("should delete objects only if owned", func() {
+		owner := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cm-delete-owner",
+				Namespace: "default",
+			},
+		}
+		Expect(c.Create(ctx, owner)).To(Succeed())
+
+		By("deleting an object with an owner reference to the owner", func() {
+			owned := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-delete-owned",
+					Namespace: "default",
+				},
+			}
+			Expect(controllerutil.SetOwnerReference(owner, owned, scheme.Scheme)).To(Succeed())
+			Expect(c.Create(ctx, owned.DeepCopy())).To(Succeed())
+
+			Expect(applicator.DeleteIfOwned(ctx, owned, owner, "")).To(Succeed())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(owned), &corev1.ConfigMap{}))).To(BeTrue())
+		})
+
+		By("deleting an object labelled as managed by the controller", func() {
+			labelled := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-delete-labelled",
+					Namespace: "default",
+					Labels:    map[string]string{meta.ManagedByKey: "controller"},
+				},
+			}
+			Expect(c.Create(ctx, labelled.DeepCopy())).To(Succeed())
+
+			Expect(applicator.DeleteIfOwned(ctx, labelled, owner, "controller")).To(Succeed())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(labelled), &corev1.ConfigMap{}))).To(BeTrue())
+		})
+
+		By("refusing to delete a foreign object", func() {
+			foreign := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-delete-foreign",
+					Namespace: "default",
+					Labels:    map[string]string{meta.ManagedByKey: "other-controller"},
+				},
+			}
+			Expect(c.Create(ctx, foreign.DeepCopy())).To(Succeed())
+
+			err := applicator.DeleteIfOwned(ctx, foreign, owner, "controller")
+			Expect(err).To(MatchError(io.ObjectNotOwned{Object: client.ObjectKeyFromObject(foreign), Owner: client.ObjectKeyFromObject(owner)}))
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(foreign), &corev1.ConfigMap{})).To(Succeed())
+		})
+
+		By("ignoring objects that don't exist", func() {
+			missing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-delete-missing",
+					Namespace: "default",
+				},
+			}
+			Expect(applicator.DeleteIfOwned(ctx, missing, owner, "controller")).To(Succeed())
+		})
+	})
+
+	It("should record the last-applied configuration", func() {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cm-last-applied",
+				Namespace:   "default",
+				Annotations: map[string]string{"foo": "bar"},
+			},
+			Data: map[string]string{"key": "v1"},
+		}
+
+		lastApplied := func() map[string]interface{} {
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+			Expect(actual.Annotations).To(HaveKey(io.LastAppliedAnnotationKey))
+
+			config := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(actual.Annotations[io.LastAppliedAnnotationKey]), &config)).To(Succeed())
+			return config
+		}
+
+		By("creating the object", func() {
+			Expect(applicator.Apply(ctx, cm.DeepCopy(), io.WithLastAppliedAnnotation())).To(Succeed())
+
+			config := lastApplied()
+			Expect(config).To(HaveKeyWithValue("data", map[string]interface{}{"key": "v1"}))
+			// the annotation is excluded from its own content
+			Expect(config).To(HaveKeyWithValue("metadata", map[string]interface{}{
+				"name":        "cm-last-applied",
+				"namespace":   "default",
+				"annotations": map[string]interface{}{"foo": "bar"},
+			}))
+		})
+
+		By("updating the object", func() {
+			desired := cm.DeepCopy()
+			desired.Annotations = nil
+			desired.Data = map[string]string{"key": "v2"}
+			Expect(applicator.Apply(ctx, desired, io.WithLastAppliedAnnotation())).To(Succeed())
+
+			config := lastApplied()
+			Expect(config).To(HaveKeyWithValue("data", map[string]interface{}{"key": "v2"}))
+			Expect(config).To(HaveKeyWithValue("metadata", map[string]interface{}{
+				"name":      "cm-last-applied",
+				"namespace": "default",
+			}))
+		})
+	})
+
+	It("should record the controller version without patching on version changes alone", func() {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cm-version",
+				Namespace: "default",
+			},
+			Data: map[string]string{"key": "v1"},
+		}
+
+		get := func() *corev1.ConfigMap {
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+			return actual
+		}
+
+		By("creating the object", func() {
+			Expect(applicator.Apply(ctx, cm.DeepCopy(), io.WithVersionAnnotation("1.0.0"))).To(Succeed())
+			Expect(get().Annotations).To(HaveKeyWithValue(io.ReconciledByVersionAnnotationKey, "1.0.0"))
+		})
+
+		By("applying the unchanged object with a new version", func() {
+			current := get()
+			Expect(applicator.Apply(ctx, current.DeepCopy(), io.WithVersionAnnotation("1.1.0"))).To(Succeed())
+
+			actual := get()
+			Expect(actual.ResourceVersion).To(Equal(current.ResourceVersion))
+			Expect(actual.Annotations).To(HaveKeyWithValue(io.ReconciledByVersionAnnotationKey, "1.0.0"))
+		})
+
+		By("applying a genuine change with a new version", func() {
+			desired := get()
+			desired.Data = map[string]string{"key": "v2"}
+			Expect(applicator.Apply(ctx, desired, io.WithVersionAnnotation("1.1.0"))).To(Succeed())
+			Expect(get().Annotations).To(HaveKeyWithValue(io.ReconciledByVersionAnnotationKey, "1.1.0"))
+		})
+	})
+
+	It("should reject invalid owner references", func() {
+		namespacedOwner := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"},
+		}
+		clusterScopedOwner := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "owner", UID: "owner-uid"},
+		}
+		newChild := func(namespace string) client.Object {
+			if namespace == "" {
+				return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "child"}}
+			}
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: namespace}}
+		}
+
+		for _, tc := range []struct {
+			name      string
+			owner     client.Object
+			namespace string
+			valid     bool
+		}{
+			{name: "namespaced owner of object in the same namespace", owner: namespacedOwner, namespace: "default", valid: true},
+			{name: "namespaced owner of object in a different namespace", owner: namespacedOwner, namespace: "other"},
+			{name: "namespaced owner of cluster-scoped object", owner: namespacedOwner},
+			{name: "cluster-scoped owner of namespaced object", owner: clusterScopedOwner, namespace: "default", valid: true},
+			{name: "cluster-scoped owner of cluster-scoped object", owner: clusterScopedOwner, valid: true},
+		} {
+			By(tc.name, func() {
+				for _, opt := range []io.ApplyOption{
+					io.WithControllerRef(tc.owner, scheme.Scheme),
+					io.WithOwnerRef(tc.owner, scheme.Scheme),
+				} {
+					child := newChild(tc.namespace)
+					err := opt(ctx, child, &io.RequestOptions{})
+					if tc.valid {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(child.GetOwnerReferences()).To(HaveLen(1))
+					} else {
+						Expect(err).To(MatchError(meta.ErrInvalidOwnerRef))
+						Expect(child.GetOwnerReferences()).To(BeEmpty())
+					}
+				}
+			})
+		}
+	})
+
+	It("should set the field manager", func() {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cm-field-manager",
+				Namespace: "default",
+			},
+			Data: map[string]string{"foo": "bar"},
+		}
+
+		hasManager := func(obj client.Object, manager string, op metav1.ManagedFieldsOperationType) bool {
+			for _, entry := range obj.GetManagedFields() {
+				if entry.Manager == manager && entry.Operation == op {
+					return true
+				}
+			}
+			return false
+		}
+
+		By("creating the object", func() {
+			Expect(applicator.Apply(ctx, cm.DeepCopy(), io.WithFieldManager("creator"))).To(Succeed())
+
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+			Expect(hasManager(actual, "creator", metav1.ManagedFieldsOperationUpdate)).To(BeTrue())
+		})
+
+		for _, tc := range []struct {
+			name string
+			opts []io.ApplyOption
+		}{
+			{name: "patcher", opts: []io.ApplyOption{}},
+			{name: "updater", opts: []io.ApplyOption{io.AsUpdate()}},
+		} {
+			By("updating the object with "+tc.name, func() {
+				desired := cm.DeepCopy()
+				desired.Data = map[string]string{"foo": tc.name}
+				Expect(applicator.Apply(ctx, desired, append(tc.opts, io.WithFieldManager(tc.name))...)).To(Succeed())
+
+				actual := &corev1.ConfigMap{}
+				Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+				Expect(hasManager(actual, tc.name, metav1.ManagedFieldsOperationUpdate)).To(BeTrue())
+			})
+		}
+
+		By("falling back to the client's field manager if empty", func() {
+			desired := cm.DeepCopy()
+			desired.Data = map[string]string{"foo": "default"}
+			Expect(applicator.Apply(ctx, desired, io.WithFieldManager(""))).To(Succeed())
+
+			actual := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+			Expect(actual.Data).To(Equal(map[string]string{"foo": "default"}))
+			for _, entry := range actual.GetManagedFields() {
+				Expect(entry.Manager).ToNot(BeEmpty())
+			}
+		})
+	})
+
+	It("should not patch objects that differ only in numeric types", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deployment-numeric",
+				Namespace: "default",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "numeric"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "numeric"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app"}},
+					},
+				},
+			},
+		}
+		Expect(c.Create(ctx, deployment)).To(Succeed())
+
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), current)).To(Succeed())
+
+		By("applying an integer round-tripped as a float", func() {
+			desired := current.DeepCopy()
+			Expect(unstructured.SetNestedField(desired.Object, float64(1), "spec", "replicas")).To(Succeed())
+			Expect(applicator.Apply(ctx, desired)).To(Succeed())
+
+			actual := &appsv1.Deployment{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+			Expect(actual.ResourceVersion).To(Equal(current.GetResourceVersion()))
+		})
+
+		By("applying a genuine change", func() {
+			desired := current.DeepCopy()
+			Expect(unstructured.SetNestedField(desired.Object, float64(2), "spec", "replicas")).To(Succeed())
+			Expect(applicator.Apply(ctx, desired)).To(Succeed())
+
+			actual := &appsv1.Deployment{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+			Expect(actual.Spec.Replicas).To(Equal(ptr.To[int32](2)))
+		})
+
+		By("applying a string change", func() {
+			actual := &appsv1.Deployment{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+			actual.Spec.Template.Spec.Containers[0].Image = "app:v2"
+			Expect(applicator.Apply(ctx, actual.DeepCopy())).To(Succeed())
+
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+			Expect(actual.Spec.Template.Spec.Containers[0].Image).To(Equal("app:v2"))
+		})
+	})
+
+	It("should scale objects through the scale subresource", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deployment-scale",
+				Namespace: "default",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "scale"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "scale"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "app"}},
+					},
+				},
+			},
+		}
+		Expect(c.Create(ctx, deployment)).To(Succeed())
+
+		By("scaling the deployment", func() {
+			Expect(applicator.ApplyScale(ctx, deployment.DeepCopy(), 3)).To(Succeed())
+
+			actual := &appsv1.Deployment{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), actual)).To(Succeed())
+			Expect(actual.Spec.Replicas).To(Equal(ptr.To[int32](3)))
+		})
+
+		By("failing for types without a scale subresource", func() {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cm-scale",
+					Namespace: "default",
+				},
+			}
+			Expect(c.Create(ctx, cm)).To(Succeed())
+
+			err := applicator.ApplyScale(ctx, cm, 3)
+			Expect(err).To(MatchError(io.ScaleSubresourceUnsupported{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap")}))
+		})
+
+		By("failing for objects that don't exist", func() {
+			missing := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deployment-scale-missing",
+					Namespace: "default",
+				},
+			}
+			Expect(applicator.ApplyScale(ctx, missing, 3)).To(MatchError(ContainSubstring("object does not exist")))
+		})
+	})
+
 	It("should patch status", func() {
 		svc := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,239 @@
+package io
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+type readCacheContextKey struct{}
+
+// readCache memoizes objects read through a ClientApplicator for the lifetime of a context.
+type readCache struct {
+	mu      sync.Mutex
+	objects map[readCacheKey]client.Object
+}
+
+type readCacheKey struct {
+	// client identifies the underlying client, so that ClientApplicators of the same client share entries while
+	// ClientApplicators of different clusters don't
+	client any
+	gvk    schema.GroupVersionKind
+	key    client.ObjectKey
+}
+
+// NewReadCacheContext returns a context in which Gets through a ClientApplicator are memoized, e.g. for the duration of a
+// single reconcile. Writes through the ClientApplicator invalidate the cached entry of the written object, while changes
+// made by other actors aren't observed until the context is discarded.
+// Gets with options (e.g. a resource version) always read from the underlying client.
+func NewReadCacheContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readCacheContextKey{}, &readCache{objects: map[readCacheKey]client.Object{}})
+}
+
+// readCacheFromContext returns the read cache of the context, or nil if the context has none.
+func readCacheFromContext(ctx context.Context) *readCache {
+	cache, _ := ctx.Value(readCacheContextKey{}).(*readCache)
+	return cache
+}
+
+// get copies the cached object into obj, returning false if no object of the same type is cached.
+func (r *readCache) get(key readCacheKey, obj client.Object) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cached, ok := r.objects[key]
+	if !ok || reflect.TypeOf(cached) != reflect.TypeOf(obj) {
+		return false
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(cached.DeepCopyObject()).Elem())
+	return true
+}
+
+func (r *readCache) set(key readCacheKey, obj client.Object) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.objects[key] = obj.DeepCopyObject().(client.Object)
+}
+
+func (r *readCache) delete(key readCacheKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.objects, key)
+}
+
+func (r *readCache) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.objects = map[readCacheKey]client.Object{}
+}
+
+// Get reads the object from the context's read cache if present (see NewReadCacheContext), otherwise from the underlying client.
+func (c *ClientApplicator) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	cache := readCacheFromContext(ctx)
+	if cache == nil || len(opts) > 0 {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+
+	cacheKey, ok := c.readCacheKey(key, obj)
+	if !ok {
+		return c.Client.Get(ctx, key, obj)
+	}
+
+	if cache.get(cacheKey, obj) {
+		return nil
+	}
+	if err := c.Client.Get(ctx, key, obj); err != nil {
+		return err
+	}
+	cache.set(cacheKey, obj)
+	return nil
+}
+
+// invalidate removes the object from the context's read cache, if any.
+func (c *ClientApplicator) invalidate(ctx context.Context, obj client.Object) {
+	cache := readCacheFromContext(ctx)
+	if cache == nil {
+		return
+	}
+
+	if cacheKey, ok := c.readCacheKey(client.ObjectKeyFromObject(obj), obj); ok {
+		cache.delete(cacheKey)
+	}
+}
+
+// readCacheKey returns the read cache key of the object, or false if the object can't be cached.
+func (c *ClientApplicator) readCacheKey(key client.ObjectKey, obj client.Object) (readCacheKey, bool) {
+	gvk, err := apiutil.GVKForObject(obj, c.Client.Scheme())
+	if err != nil {
+		return readCacheKey{}, false
+	}
+
+	// clients of non-comparable types can't be used as map keys, so entries are scoped to this ClientApplicator instead
+	var id any = c
+	if reflect.TypeOf(c.Client).Comparable() {
+		id = c.Client
+	}
+	return readCacheKey{client: id, gvk: gvk, key: key}, true
+}
+
+// Create creates the object, invalidating its read cache entry.
+func (c *ClientApplicator) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Update updates the object, invalidating its read cache entry.
+func (c *ClientApplicator) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch patches the object, invalidating its read cache entry.
+func (c *ClientApplicator) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete deletes the object, invalidating its read cache entry.
+func (c *ClientApplicator) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf deletes all matching objects, clearing the read cache.
+func (c *ClientApplicator) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	if cache := readCacheFromContext(ctx); cache != nil {
+		defer cache.clear()
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Apply applies the object, invalidating its read cache entry.
+func (c *ClientApplicator) Apply(ctx context.Context, obj client.Object, opts ...ApplyOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	return c.Applicator.Apply(ctx, obj, opts...)
+}
+
+// ApplyStatus applies the object's status, invalidating its read cache entry.
+func (c *ClientApplicator) ApplyStatus(ctx context.Context, obj client.Object, opts ...ApplyOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	return c.Applicator.ApplyStatus(ctx, obj, opts...)
+}
+
+// ApplyScale applies the object's scale, invalidating its read cache entry.
+// The scale is applied through the Applicator if it's a ScaleApplicator, else through an APIApplicator for the Client.
+func (c *ClientApplicator) ApplyScale(ctx context.Context, obj client.Object, replicas int32) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer c.invalidate(ctx, obj)
+	if scaleApplicator, ok := c.Applicator.(ScaleApplicator); ok {
+		return scaleApplicator.ApplyScale(ctx, obj, replicas)
+	}
+	return NewAPIPatchingApplicator(c.Client).ApplyScale(ctx, obj, replicas)
+}
+
+// Status returns a writer for the status subresource that invalidates read cache entries of written objects,
+// and rejects writes in read-only contexts.
+func (c *ClientApplicator) Status() client.SubResourceWriter {
+	return &invalidatingSubResourceWriter{SubResourceWriter: c.Client.Status(), c: c}
+}
+
+// invalidatingSubResourceWriter is a client.SubResourceWriter that invalidates read cache entries of written objects,
+// and rejects writes in read-only contexts.
+type invalidatingSubResourceWriter struct {
+	client.SubResourceWriter
+	c *ClientApplicator
+}
+
+func (w *invalidatingSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer w.c.invalidate(ctx, obj)
+	return w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+}
+
+func (w *invalidatingSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer w.c.invalidate(ctx, obj)
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *invalidatingSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := checkWritable(ctx, obj); err != nil {
+		return err
+	}
+	defer w.c.invalidate(ctx, obj)
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
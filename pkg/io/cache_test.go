@@ -0,0 +1,166 @@
+package io_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/reddit/achilles-sdk/pkg/io"
+)
+
+func TestClientApplicator_ReadCache(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	c := &countingClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cm).Build()}
+	applicator := &io.ClientApplicator{
+		Client:     c,
+		Applicator: io.NewAPIPatchingApplicator(c),
+	}
+
+	get := func(ctx context.Context) *corev1.ConfigMap {
+		t.Helper()
+		actual := &corev1.ConfigMap{}
+		if err := applicator.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+			t.Fatalf("getting ConfigMap: %s", err)
+		}
+		return actual
+	}
+
+	t.Run("without cache", func(t *testing.T) {
+		c.gets = 0
+		ctx := context.Background()
+		get(ctx)
+		get(ctx)
+		if c.gets != 2 {
+			t.Errorf("expected 2 gets, got %d", c.gets)
+		}
+	})
+
+	t.Run("unmodified", func(t *testing.T) {
+		c.gets = 0
+		ctx := io.NewReadCacheContext(context.Background())
+		first := get(ctx)
+		second := get(ctx)
+		if c.gets != 1 {
+			t.Errorf("expected 1 get, got %d", c.gets)
+		}
+		if second.Data["key"] != "value" || second.ResourceVersion != first.ResourceVersion {
+			t.Errorf("expected cached ConfigMap %v, got %v", first, second)
+		}
+
+		// mutating a returned object must not mutate the cached entry
+		second.Data["key"] = "mutated"
+		if third := get(ctx); third.Data["key"] != "value" {
+			t.Errorf("expected cached value %q, got %q", "value", third.Data["key"])
+		}
+	})
+
+	t.Run("invalidated by write", func(t *testing.T) {
+		c.gets = 0
+		ctx := io.NewReadCacheContext(context.Background())
+		current := get(ctx)
+
+		current.Data = map[string]string{"key": "updated"}
+		if err := applicator.Update(ctx, current); err != nil {
+			t.Fatalf("updating ConfigMap: %s", err)
+		}
+
+		if actual := get(ctx); actual.Data["key"] != "updated" {
+			t.Errorf("expected updated value, got %q", actual.Data["key"])
+		}
+		if c.gets != 2 {
+			t.Errorf("expected 2 gets, got %d", c.gets)
+		}
+	})
+
+	t.Run("invalidated by apply", func(t *testing.T) {
+		c.gets = 0
+		ctx := io.NewReadCacheContext(context.Background())
+		get(ctx)
+
+		desired := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cm.Name, Namespace: cm.Namespace},
+			Data:       map[string]string{"key": "applied"},
+		}
+		if err := applicator.Apply(ctx, desired); err != nil {
+			t.Fatalf("applying ConfigMap: %s", err)
+		}
+
+		if actual := get(ctx); actual.Data["key"] != "applied" {
+			t.Errorf("expected applied value, got %q", actual.Data["key"])
+		}
+	})
+}
+
+// countingClient counts Gets
+func TestClientApplicator_ReadOnlyContext(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cm).Build()
+	applicator := &io.ClientApplicator{
+		Client:     c,
+		Applicator: io.NewAPIPatchingApplicator(c),
+	}
+	ctx := io.NewReadOnlyContext(context.Background())
+
+	// reads are unaffected
+	current := &corev1.ConfigMap{}
+	if err := applicator.Get(ctx, client.ObjectKeyFromObject(cm), current); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+
+	updated := current.DeepCopy()
+	updated.Data["key"] = "updated"
+	created := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"}}
+
+	writes := map[string]func() error{
+		"create":        func() error { return applicator.Create(ctx, created.DeepCopy()) },
+		"update":        func() error { return applicator.Update(ctx, updated.DeepCopy()) },
+		"delete":        func() error { return applicator.Delete(ctx, current.DeepCopy()) },
+		"apply":         func() error { return applicator.Apply(ctx, updated.DeepCopy()) },
+		"apply status":  func() error { return applicator.ApplyStatus(ctx, updated.DeepCopy()) },
+		"status update": func() error { return applicator.Status().Update(ctx, updated.DeepCopy()) },
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			if err := write(); !errors.As(err, &io.WriteInReadOnlyContext{}) {
+				t.Errorf("expected read-only error, got %v", err)
+			}
+		})
+	}
+
+	// the objects are unchanged
+	actual := &corev1.ConfigMap{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	if actual.Data["key"] != "value" {
+		t.Errorf("expected unchanged value %q, got %q", "value", actual.Data["key"])
+	}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(created), &corev1.ConfigMap{}); !kerrors.IsNotFound(err) {
+		t.Errorf("expected ConfigMap not to be created, got %v", err)
+	}
+}
+
+type countingClient struct {
+	client.Client
+	gets int
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.gets++
+	return c.Client.Get(ctx, key, obj, opts...)
+}
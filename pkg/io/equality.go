@@ -0,0 +1,171 @@
+package io
+
+import (
+	stdjson "encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/reddit/achilles-sdk/pkg/encoding/json"
+)
+
+// semanticDeepEqual reports whether the unstructured values a and b are deeply equal, treating numeric values as equal
+// if they represent the same number regardless of their Go type. Numeric types of unstructured objects depend on how
+// the object was constructed, e.g. a value decoded from JSON as float64 is equivalent to the same value set as int64.
+func semanticDeepEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) || (a == nil) != (b == nil) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !semanticDeepEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) || (a == nil) != (b == nil) {
+			return false
+		}
+		for i := range a {
+			if !semanticDeepEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if an, ok := toNumber(a); ok {
+		bn, ok := toNumber(b)
+		return ok && an.equal(bn)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// number is a numeric value, represented as an int64 if integral and within range, otherwise as a float64.
+type number struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func (n number) equal(o number) bool {
+	if !n.isFloat && !o.isFloat {
+		return n.i == o.i
+	}
+	return n.float() == o.float()
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+// toNumber converts v to a number, returning false if v isn't numeric.
+func toNumber(v interface{}) (number, bool) {
+	switch v := v.(type) {
+	case int:
+		return number{i: int64(v)}, true
+	case int8:
+		return number{i: int64(v)}, true
+	case int16:
+		return number{i: int64(v)}, true
+	case int32:
+		return number{i: int64(v)}, true
+	case int64:
+		return number{i: v}, true
+	case uint:
+		return fromUint64(uint64(v)), true
+	case uint8:
+		return number{i: int64(v)}, true
+	case uint16:
+		return number{i: int64(v)}, true
+	case uint32:
+		return number{i: int64(v)}, true
+	case uint64:
+		return fromUint64(v), true
+	case float32:
+		return fromFloat64(float64(v)), true
+	case float64:
+		return fromFloat64(v), true
+	case stdjson.Number:
+		return fromLiteral(string(v))
+	case json.Number:
+		return fromLiteral(string(v))
+	}
+	return number{}, false
+}
+
+// fromLiteral converts a JSON number literal, e.g. a json.Number of an object decoded with UseNumber, to a number.
+// Integer literals are parsed as integers, so that values exceeding 2^53 don't lose precision by round-tripping through float64.
+func fromLiteral(v string) (number, bool) {
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return number{i: i}, true
+	}
+	if u, err := strconv.ParseUint(v, 10, 64); err == nil {
+		return fromUint64(u), true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return number{}, false
+	}
+	return fromFloat64(f), true
+}
+
+func fromUint64(v uint64) number {
+	if v > math.MaxInt64 {
+		return number{f: float64(v), isFloat: true}
+	}
+	return number{i: int64(v)}
+}
+
+func fromFloat64(v float64) number {
+	// integral floats within the int64 range are compared as integers to avoid loss of precision
+	if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+		return number{i: int64(v)}
+	}
+	return number{f: v, isFloat: true}
+}
+
+// SemanticDeepDerivative reports whether the unstructured value desired is a semantic subset of live, i.e. whether live
+// already reflects desired. Fields absent from desired (e.g. fields defaulted by the kube-apiserver) are ignored,
+// maps are compared key-wise, lists element-wise, and numeric values as in Apply regardless of their Go type.
+func SemanticDeepDerivative(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return len(d) == 0 && live == nil
+		}
+		for k, dv := range d {
+			if !SemanticDeepDerivative(dv, l[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return len(d) == 0 && live == nil
+		}
+		if len(d) != len(l) {
+			return false
+		}
+		for i := range d {
+			if !SemanticDeepDerivative(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return semanticDeepEqual(desired, live)
+}
@@ -0,0 +1,74 @@
+package io_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/reddit/achilles-sdk/pkg/io"
+)
+
+func TestApply_LargeIntegers(t *testing.T) {
+	ctx := context.Background()
+	// 2^53 + 1 isn't representable as a float64
+	const large int64 = 1<<53 + 1
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec:       corev1.PodSpec{ActiveDeadlineSeconds: ptr.To(large)},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+	applicator := io.NewAPIPatchingApplicator(c)
+
+	var patches []string
+	logger := io.WithPatchLogger(func(patch []byte) {
+		patches = append(patches, string(patch))
+	})
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), current); err != nil {
+		t.Fatalf("getting Pod: %s", err)
+	}
+
+	// a json.Number, e.g. of an object decoded with UseNumber, equal to the live value sends no patch
+	desired := current.DeepCopy()
+	if err := unstructured.SetNestedField(desired.Object, json.Number("9007199254740993"), "spec", "activeDeadlineSeconds"); err != nil {
+		t.Fatalf("setting activeDeadlineSeconds: %s", err)
+	}
+	if err := applicator.Apply(ctx, desired, logger); err != nil {
+		t.Fatalf("applying unchanged Pod: %s", err)
+	}
+	if len(patches) != 0 {
+		t.Fatalf("expected no patches without changes, got %v", patches)
+	}
+
+	// a json.Number differing from the live value only beyond float64 precision sends a patch with the exact value
+	desired = current.DeepCopy()
+	if err := unstructured.SetNestedField(desired.Object, json.Number("9007199254740995"), "spec", "activeDeadlineSeconds"); err != nil {
+		t.Fatalf("setting activeDeadlineSeconds: %s", err)
+	}
+	if err := applicator.Apply(ctx, desired, logger); err != nil {
+		t.Fatalf("patching Pod: %s", err)
+	}
+	if len(patches) != 1 || !strings.Contains(patches[0], `"activeDeadlineSeconds":9007199254740995`) {
+		t.Fatalf("expected patch of activeDeadlineSeconds, got %v", patches)
+	}
+
+	actual := &corev1.Pod{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), actual); err != nil {
+		t.Fatalf("getting Pod: %s", err)
+	}
+	if *actual.Spec.ActiveDeadlineSeconds != large+2 {
+		t.Errorf("expected activeDeadlineSeconds %d, got %d", large+2, *actual.Spec.ActiveDeadlineSeconds)
+	}
+}
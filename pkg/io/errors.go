@@ -1,5 +1,13 @@
 package io
 
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
 // ResourceVersionMissing is returned if an object is missing a resource version
 type ResourceVersionMissing struct {
 }
@@ -7,3 +15,40 @@ type ResourceVersionMissing struct {
 func (r ResourceVersionMissing) Error() string {
 	return "cannot use optimistic lock, object missing resource version"
 }
+
+// ScaleSubresourceUnsupported is returned if an object's type doesn't support the scale subresource
+type ScaleSubresourceUnsupported struct {
+	GroupVersionKind schema.GroupVersionKind
+}
+
+func (s ScaleSubresourceUnsupported) Error() string {
+	return fmt.Sprintf("type %s does not support the scale subresource", s.GroupVersionKind)
+}
+
+// ObjectControlledByAnotherOwner is returned if an existing object can't be adopted because it's controlled by another owner
+type ObjectControlledByAnotherOwner struct {
+	Owner metav1.OwnerReference
+}
+
+func (o ObjectControlledByAnotherOwner) Error() string {
+	return fmt.Sprintf("cannot adopt object controlled by %s %q", o.Owner.Kind, o.Owner.Name)
+}
+
+// ObjectNotOwned is returned if an object can't be deleted because it isn't owned by the expected owner
+type ObjectNotOwned struct {
+	Object client.ObjectKey
+	Owner  client.ObjectKey
+}
+
+func (o ObjectNotOwned) Error() string {
+	return fmt.Sprintf("refusing to delete object %s not owned by %s", o.Object, o.Owner)
+}
+
+// WriteInReadOnlyContext is returned if an object is written through a ClientApplicator in a read-only context
+type WriteInReadOnlyContext struct {
+	Object client.ObjectKey
+}
+
+func (w WriteInReadOnlyContext) Error() string {
+	return fmt.Sprintf("refusing to write object %s in read-only context", w.Object)
+}
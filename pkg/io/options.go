@@ -17,8 +17,38 @@ func WithRedditLabels(controllerName string) ApplyOption {
 	}
 }
 
+// WithRedditLabelKeys applies the subset of the standard set of labels for managed resources whose keys are listed.
+// This is useful for resource types that reject unknown labels.
+func WithRedditLabelKeys(controllerName string, keys ...string) ApplyOption {
+	return func(ctx context.Context, o client.Object, _ *RequestOptions) error {
+		meta.SetRedditLabelKeys(o, controllerName, keys...)
+		return nil
+	}
+}
+
+// WithMergeLabels unions the object's existing labels with the desired labels rather than replacing them, preserving
+// labels managed by other actors. Labels owned by the caller that should be deleted must be listed in removeKeys.
+func WithMergeLabels(removeKeys ...string) ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.MergeLabels = true
+		requestOpts.RemoveLabels = append(requestOpts.RemoveLabels, removeKeys...)
+		return nil
+	}
+}
+
+// WithMergeAnnotations unions the object's existing annotations with the desired annotations rather than replacing them,
+// preserving annotations managed by other actors. Annotations owned by the caller that should be deleted must be listed in removeKeys.
+func WithMergeAnnotations(removeKeys ...string) ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.MergeAnnotations = true
+		requestOpts.RemoveAnnotations = append(requestOpts.RemoveAnnotations, removeKeys...)
+		return nil
+	}
+}
+
 // WithControllerRef sets an owner reference on the object and controller flag to true.
 // When used in the context of OutputSet, this option is used by default unless WithoutOwnerRef is specified.
+// Returns an error wrapping meta.ErrInvalidOwnerRef if the owner is namespaced and the object is cluster-scoped or in a different namespace.
 func WithControllerRef(owner client.Object, scheme *runtime.Scheme) ApplyOption {
 	return func(ctx context.Context, o client.Object, opts *RequestOptions) error {
 		// skip if WithoutOwnerRefs is set or if the caller explicitly specifies ownerReferences
@@ -31,6 +61,7 @@ func WithControllerRef(owner client.Object, scheme *runtime.Scheme) ApplyOption
 
 // WithOwnerRef sets an owner reference on the object and controller flag to false.
 // Multiple owner references can be set on an object if their controller flag is false.
+// Returns an error wrapping meta.ErrInvalidOwnerRef if the owner is namespaced and the object is cluster-scoped or in a different namespace.
 func WithOwnerRef(owner client.Object, scheme *runtime.Scheme) ApplyOption {
 	return func(ctx context.Context, o client.Object, opts *RequestOptions) error {
 		// skip if WithoutOwnerRefs is set
@@ -63,6 +94,112 @@ func WithOptimisticLock() ApplyOption {
 	}
 }
 
+// WithKnownResourceVersion sets the resource version of the applied object to rv, the resource version of the existing
+// object, e.g. as held by callers that recently read the object, and enforces the optimistic lock (see WithOptimisticLock).
+// Combined with AsUpdate, the object is updated without first being read from the kube-apiserver, so the update is sent
+// even if the object is unchanged, and the object must exist. If rv is stale, the update fails with a conflict error
+// rather than overwriting concurrent changes. The object is still read if other options depend on the existing object,
+// i.e. WithMergeLabels, WithMergeAnnotations, WithAdoptExisting, and WithCreateOnlyFields.
+func WithKnownResourceVersion(rv string) ApplyOption {
+	return func(ctx context.Context, o client.Object, requestOpts *RequestOptions) error {
+		if rv == "" {
+			return ResourceVersionMissing{}
+		}
+		o.SetResourceVersion(rv)
+		requestOpts.EnforceOptimisticLock = true
+		requestOpts.knownResourceVersion = true
+		return nil
+	}
+}
+
+// WithCreateOnlyFields specifies dot-separated field paths (e.g. "spec.selector") that are only set when the object is
+// created. If the object already exists, the existing values of these fields are preserved, which prevents
+// "field is immutable" errors for fields that the API server only accepts at creation time.
+func WithCreateOnlyFields(paths ...string) ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.CreateOnlyFields = append(requestOpts.CreateOnlyFields, paths...)
+		return nil
+	}
+}
+
+// WithFieldManager sets the field manager (i.e. client.FieldOwner) on create, update, and patch requests,
+// which is recorded in the object's managedFields and in kube-apiserver audit logs.
+// If name is empty, the field manager defaults to that of the client.
+func WithFieldManager(name string) ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.FieldManager = name
+		return nil
+	}
+}
+
+// WithPatchLogger invokes logger with the JSON merge patch sent to the kube-apiserver when the object (or its status)
+// is patched, e.g. for debugging unexpected applies. logger isn't invoked if the object is unchanged, created, or updated
+// (see AsUpdate), since no patch is sent.
+func WithPatchLogger(logger func(patch []byte)) ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.PatchLogger = logger
+		return nil
+	}
+}
+
+// LastAppliedAnnotationKey is the annotation in which WithLastAppliedAnnotation records the applied object.
+const LastAppliedAnnotationKey = "infrared.reddit.com/last-applied-configuration"
+
+// WithLastAppliedAnnotation records the canonical JSON encoding of the applied object in the LastAppliedAnnotationKey
+// annotation, analogous to kubectl's "kubectl.kubernetes.io/last-applied-configuration". Comparing the annotation
+// against the live object allows subsequent applies to compute three-way diffs and detect out-of-band changes.
+// The annotation is computed after all other apply options, and excludes itself, status, and server-populated metadata.
+func WithLastAppliedAnnotation() ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.LastApplied = true
+		return nil
+	}
+}
+
+// ReconciledByVersionAnnotationKey is the annotation in which WithVersionAnnotation records the version of the controller
+// that last applied the object.
+const ReconciledByVersionAnnotationKey = "infrared.reddit.com/reconciled-by-version"
+
+// WithVersionAnnotation records the version of the controller (e.g. its build version) applying the object in the
+// ReconciledByVersionAnnotationKey annotation, which is useful for debugging rollouts.
+// The annotation is excluded when comparing the desired and existing object, so that rolling out a new version doesn't
+// patch all objects. It's updated on creation and whenever the object is otherwise patched or updated.
+func WithVersionAnnotation(version string) ApplyOption {
+	return func(ctx context.Context, o client.Object, requestOpts *RequestOptions) error {
+		annotations := o.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[ReconciledByVersionAnnotationKey] = version
+		o.SetAnnotations(annotations)
+		requestOpts.ignoredAnnotations = append(requestOpts.ignoredAnnotations, ReconciledByVersionAnnotationKey)
+		return nil
+	}
+}
+
+// WithAdoptExisting adopts objects created outside the controller rather than overwriting their ownership metadata.
+// If the object already exists and isn't controlled by another owner, the desired owner references and labels are added
+// to its existing ones. If the object is controlled by another owner, Apply returns ObjectControlledByAnotherOwner
+// without modifying it. Has no effect on object creation.
+func WithAdoptExisting() ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.AdoptExisting = true
+		return nil
+	}
+}
+
+// AsStrategicMerge patches objects of built-in Kubernetes types with a strategic merge patch rather than a JSON merge patch,
+// so that lists with merge keys are merged by key instead of replaced wholesale, e.g. containers are merged by name, retaining
+// containers added by other actors. Entries of such lists therefore aren't removed by omitting them from the applied object.
+// Custom resources don't support strategic merge patches and are patched with a JSON merge patch. Has no effect on creates
+// or updates (see AsUpdate).
+func AsStrategicMerge() ApplyOption {
+	return func(ctx context.Context, _ client.Object, requestOpts *RequestOptions) error {
+		requestOpts.StrategicMerge = true
+		return nil
+	}
+}
+
 // AsUpdate uses an update request to overwrite the entire object if it exists, rather than selective patching.
 // Using this option without the optimistic lock implies a full overwrite of the object, so use with caution.
 func AsUpdate() ApplyOption {
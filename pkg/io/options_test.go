@@ -0,0 +1,263 @@
+package io_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	intscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
+	testv1alpha1 "github.com/reddit/achilles-sdk/pkg/internal/tests/api/test/v1alpha1"
+	"github.com/reddit/achilles-sdk/pkg/io"
+)
+
+func TestWithPatchLogger(t *testing.T) {
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	applicator := io.NewAPIPatchingApplicator(c)
+
+	var patches []string
+	logger := io.WithPatchLogger(func(patch []byte) {
+		patches = append(patches, string(patch))
+	})
+
+	// creation sends no patch
+	if err := applicator.Apply(ctx, cm.DeepCopy(), logger); err != nil {
+		t.Fatalf("creating ConfigMap: %s", err)
+	}
+	if len(patches) != 0 {
+		t.Fatalf("expected no patches on creation, got %v", patches)
+	}
+
+	current := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), current); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+
+	// no diff sends no patch
+	if err := applicator.Apply(ctx, current.DeepCopy(), logger); err != nil {
+		t.Fatalf("applying unchanged ConfigMap: %s", err)
+	}
+	if len(patches) != 0 {
+		t.Fatalf("expected no patches without changes, got %v", patches)
+	}
+
+	desired := current.DeepCopy()
+	desired.Data["key"] = "updated"
+	if err := applicator.Apply(ctx, desired, logger); err != nil {
+		t.Fatalf("patching ConfigMap: %s", err)
+	}
+	expected := `{"metadata":{"name":"foo","namespace":"default","creationTimestamp":null},"data":{"key":"updated"}}`
+	if len(patches) != 1 || patches[0] != expected {
+		t.Fatalf("expected patch %s, got %v", expected, patches)
+	}
+
+	actual := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	if actual.Data["key"] != "updated" {
+		t.Errorf("expected patched value %q, got %q", "updated", actual.Data["key"])
+	}
+}
+
+func TestWithKnownResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	var gets int
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(cm).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	applicator := io.NewAPIPatchingApplicator(c)
+
+	current := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), current); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	staleResourceVersion := current.ResourceVersion
+	gets = 0
+
+	// updates with a known resource version skip the read
+	desired := cm.DeepCopy()
+	desired.Data["key"] = "updated"
+	if err := applicator.Apply(ctx, desired, io.AsUpdate(), io.WithKnownResourceVersion(current.ResourceVersion)); err != nil {
+		t.Fatalf("updating ConfigMap: %s", err)
+	}
+	if gets != 0 {
+		t.Errorf("expected no reads, got %d", gets)
+	}
+
+	actual := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	if actual.Data["key"] != "updated" {
+		t.Errorf("expected updated value %q, got %q", "updated", actual.Data["key"])
+	}
+
+	// stale resource versions surface as conflicts rather than overwriting the object
+	desired = cm.DeepCopy()
+	desired.Data["key"] = "stale"
+	err := applicator.Apply(ctx, desired, io.AsUpdate(), io.WithKnownResourceVersion(staleResourceVersion))
+	if !kerrors.IsConflict(err) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	if actual.Data["key"] != "updated" {
+		t.Errorf("expected value %q to be retained, got %q", "updated", actual.Data["key"])
+	}
+
+	// options depending on the existing object still read it
+	gets = 0
+	desired = cm.DeepCopy()
+	desired.Data["key"] = "merged"
+	if err := applicator.Apply(ctx, desired, io.AsUpdate(), io.WithMergeLabels(), io.WithKnownResourceVersion(actual.ResourceVersion)); err != nil {
+		t.Fatalf("updating ConfigMap: %s", err)
+	}
+	if gets != 1 {
+		t.Errorf("expected a single read, got %d", gets)
+	}
+
+	// the last-applied configuration is recorded without reading the object
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	gets = 0
+	desired = cm.DeepCopy()
+	desired.Data["key"] = "last-applied"
+	if err := applicator.Apply(ctx, desired, io.AsUpdate(), io.WithLastAppliedAnnotation(), io.WithKnownResourceVersion(actual.ResourceVersion)); err != nil {
+		t.Fatalf("updating ConfigMap: %s", err)
+	}
+	if gets != 0 {
+		t.Errorf("expected no reads, got %d", gets)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	if _, ok := actual.Annotations[io.LastAppliedAnnotationKey]; !ok {
+		t.Errorf("expected last-applied annotation, got annotations %v", actual.Annotations)
+	}
+
+	// an empty resource version is rejected
+	if err := applicator.Apply(ctx, cm.DeepCopy(), io.AsUpdate(), io.WithKnownResourceVersion("")); !errors.As(err, &io.ResourceVersionMissing{}) {
+		t.Errorf("expected missing resource version error, got %v", err)
+	}
+}
+
+func TestWithOptimisticLockCreatesMissingObject(t *testing.T) {
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	applicator := io.NewAPIPatchingApplicator(c)
+
+	// objects that don't yet exist have no resource version, so the optimistic lock doesn't apply to their creation
+	if err := applicator.Apply(ctx, cm.DeepCopy(), io.WithOptimisticLock()); err != nil {
+		t.Fatalf("creating ConfigMap: %s", err)
+	}
+
+	actual := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), actual); err != nil {
+		t.Fatalf("getting ConfigMap: %s", err)
+	}
+	if diff := cmp.Diff(actual.Data, cm.Data); diff != "" {
+		t.Errorf("unexpected data: (-got +want)\n%s", diff)
+	}
+}
+
+func TestAsStrategicMerge(t *testing.T) {
+	ctx := context.Background()
+	deployment := func(containers ...corev1.Container) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}},
+			},
+		}
+	}
+	app := corev1.Container{Name: "app", Image: "app:v1"}
+	sidecar := corev1.Container{Name: "sidecar", Image: "sidecar:v1"}
+
+	var patchTypes []types.PatchType
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(intscheme.MustNewScheme()).
+			WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patchTypes = append(patchTypes, patch.Type())
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+	}
+
+	// containers added by other actors are retained
+	c := newClient(deployment(app, sidecar))
+	updatedApp := corev1.Container{Name: "app", Image: "app:v2"}
+	if err := io.NewAPIPatchingApplicator(c).Apply(ctx, deployment(updatedApp), io.AsStrategicMerge()); err != nil {
+		t.Fatalf("applying Deployment: %s", err)
+	}
+	actual := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "foo", Namespace: "default"}, actual); err != nil {
+		t.Fatalf("getting Deployment: %s", err)
+	}
+	if diff := cmp.Diff([]corev1.Container{updatedApp, sidecar}, actual.Spec.Template.Spec.Containers); diff != "" {
+		t.Errorf("expected containers to be merged (-want +got):\n%s", diff)
+	}
+
+	// by default, lists are replaced
+	c = newClient(deployment(app, sidecar))
+	if err := io.NewAPIPatchingApplicator(c).Apply(ctx, deployment(updatedApp)); err != nil {
+		t.Fatalf("applying Deployment: %s", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "foo", Namespace: "default"}, actual); err != nil {
+		t.Fatalf("getting Deployment: %s", err)
+	}
+	if diff := cmp.Diff([]corev1.Container{updatedApp}, actual.Spec.Template.Spec.Containers); diff != "" {
+		t.Errorf("expected containers to be replaced (-want +got):\n%s", diff)
+	}
+
+	// custom resources fall back to a JSON merge patch
+	claim := &testv1alpha1.TestClaim{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	c = newClient(claim)
+	desired := claim.DeepCopy()
+	desired.Spec.TestField = "updated"
+	if err := io.NewAPIPatchingApplicator(c).Apply(ctx, desired, io.AsStrategicMerge()); err != nil {
+		t.Fatalf("applying TestClaim: %s", err)
+	}
+
+	expected := []types.PatchType{types.StrategicMergePatchType, types.MergePatchType, types.MergePatchType}
+	if diff := cmp.Diff(expected, patchTypes); diff != "" {
+		t.Errorf("unexpected patch types (-want +got):\n%s", diff)
+	}
+}
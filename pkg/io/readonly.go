@@ -0,0 +1,23 @@
+package io
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type readOnlyContextKey struct{}
+
+// NewReadOnlyContext returns a context in which writes through a ClientApplicator fail with WriteInReadOnlyContext
+// without reaching the kube-apiserver, e.g. for dry evaluations of reconcile logic. Reads are unaffected.
+func NewReadOnlyContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyContextKey{}, true)
+}
+
+// checkWritable returns WriteInReadOnlyContext if the context is read-only (see NewReadOnlyContext).
+func checkWritable(ctx context.Context, obj client.Object) error {
+	if readOnly, _ := ctx.Value(readOnlyContextKey{}).(bool); readOnly {
+		return WriteInReadOnlyContext{Object: client.ObjectKeyFromObject(obj)}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
 // contextKey is how we find *zap.SugaredLogger in a context.Context.
@@ -36,3 +38,31 @@ func ControllerCtx(ctx context.Context, controllerName string) (context.Context,
 
 	return ctx, logger, nil
 }
+
+const (
+	fieldRequestID = "requestId"
+	fieldTraceID   = "traceId"
+	fieldSpanID    = "spanId"
+)
+
+// TraceIDsFunc returns the IDs of the trace and span carried by ctx, or empty strings if ctx doesn't carry a span.
+// When using OpenTelemetry, the IDs can be obtained from trace.SpanContextFromContext(ctx).
+type TraceIDsFunc func(ctx context.Context) (traceID, spanID string)
+
+// ReconcileLogger returns log annotated with IDs correlating all logs of the reconcile carried by ctx: the reconcile ID
+// assigned by controller-runtime (or a newly generated ID for reconciles invoked outside a controller, e.g. in tests)
+// and, if traceIDs is not nil and ctx carries a span, the trace and span IDs.
+func ReconcileLogger(ctx context.Context, log *zap.SugaredLogger, traceIDs TraceIDsFunc) *zap.SugaredLogger {
+	requestID := controller.ReconcileIDFromContext(ctx)
+	if requestID == "" {
+		requestID = uuid.NewUUID()
+	}
+	log = log.With(fieldRequestID, requestID)
+
+	if traceIDs != nil {
+		if traceID, spanID := traceIDs(ctx); traceID != "" {
+			log = log.With(fieldTraceID, traceID, fieldSpanID, spanID)
+		}
+	}
+	return log
+}
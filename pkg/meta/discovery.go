@@ -0,0 +1,30 @@
+package meta
+
+import (
+	"fmt"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// ServerSupports returns true if the kube-apiserver serves the specified GVK, as reported by the discovery API.
+// This is useful for controllers targeting multiple Kubernetes versions, e.g. for choosing between API versions of a type.
+// Returns false if the GVK's group version isn't served, and an error if discovery otherwise fails.
+func ServerSupports(discoveryClient discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (bool, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("discovering resources of group version %s: %w", gvk.GroupVersion(), err)
+	}
+
+	for _, resource := range resources.APIResources {
+		// omit subresources, which may share the kind of their parent resource (e.g. "deployments/status")
+		if resource.Kind == gvk.Kind && !strings.Contains(resource.Name, "/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
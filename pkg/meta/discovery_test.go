@@ -0,0 +1,84 @@
+package meta
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestServerSupports(t *testing.T) {
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{
+		Resources: []*metav1.APIResourceList{
+			{
+				GroupVersion: "policy/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"},
+					{Name: "poddisruptionbudgets/status", Kind: "PodDisruptionBudget"},
+				},
+			},
+			{
+				GroupVersion: "apps/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "deployments/scale", Kind: "Scale", Group: "autoscaling", Version: "v1"},
+				},
+			},
+		},
+	}}
+
+	tcs := []struct {
+		name     string
+		gvk      schema.GroupVersionKind
+		expected bool
+	}{
+		{
+			name:     "served",
+			gvk:      schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+			expected: true,
+		},
+		{
+			name: "group version not served",
+			gvk:  schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"},
+		},
+		{
+			name: "kind not served",
+			gvk:  schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "Eviction"},
+		},
+		{
+			name: "kind only served as subresource",
+			gvk:  schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Scale"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			supported, err := ServerSupports(discoveryClient, tc.gvk)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if supported != tc.expected {
+				t.Errorf("expected supported %t, got %t", tc.expected, supported)
+			}
+		})
+	}
+
+	t.Run("discovery error", func(t *testing.T) {
+		discoveryErr := errors.New("connection refused")
+		failingClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+		failingClient.AddReactor("get", "resource", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, discoveryErr
+		})
+
+		supported, err := ServerSupports(failingClient, schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"})
+		if !errors.Is(err, discoveryErr) {
+			t.Errorf("expected discovery error, got %v", err)
+		}
+		if supported {
+			t.Error("expected unsupported on error")
+		}
+	})
+}
@@ -1,6 +1,12 @@
 package meta
 
 import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -61,6 +67,67 @@ func SetRedditLabels(obj client.Object, controllerName string) {
 	}
 }
 
+// SetRedditLabelKeys updates an object's meta.labels with the subset of common reddit labels whose keys are listed.
+// Keys that are not reddit label keys are ignored. If no keys are provided, the object's labels are left untouched.
+func SetRedditLabelKeys(obj client.Object, controllerName string, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	// initialize labels map if nil
+	if obj.GetLabels() == nil {
+		obj.SetLabels(map[string]string{})
+	}
+	redditLabels := RedditLabels(controllerName)
+	objLabels := obj.GetLabels()
+	for _, k := range keys {
+		if v, ok := redditLabels[k]; ok {
+			objLabels[k] = v
+		}
+	}
+}
+
+// ListManagedByController lists the objects of the given type carrying the reddit labels of the given controller,
+// for auditing or cleaning up managed objects independently of reconciliation. This includes both objects created by the
+// controller and pre-existing objects it adopted, as the labels are stamped whenever managed objects are applied,
+// but excludes objects whose labels are disabled (see the FSM builder's WithoutRedditLabels).
+// Objects are matched on their application name, component name, and managed-by labels, but not their application
+// version label, so that objects last applied by earlier versions are included. The type must be registered with the
+// client's scheme. opts further scope the list, e.g. to a namespace.
+func ListManagedByController(
+	ctx context.Context,
+	c client.Client,
+	controllerName string,
+	gvk schema.GroupVersionKind,
+	opts ...client.ListOption,
+) ([]client.Object, error) {
+	o, err := c.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, fmt.Errorf("constructing list for %s: %w", gvk, err)
+	}
+	list, ok := o.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("list type %T for %s is not a client.ObjectList", o, gvk)
+	}
+
+	selector := client.MatchingLabels{
+		ApplicationNameKey: ApplicationName,
+		ComponentNameKey:   ComponentName,
+		ManagedByKey:       controllerName,
+	}
+	if err := c.List(ctx, list, append([]client.ListOption{selector}, opts...)...); err != nil {
+		return nil, fmt.Errorf("listing %s: %w", gvk, err)
+	}
+
+	var objs []client.Object
+	if err := apimeta.EachListItem(list, func(item runtime.Object) error {
+		objs = append(objs, item.(client.Object))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
 // HasSuspendLabel checks if the label `SuspendKey` has been set in the object's meta.labels.
 func HasSuspendLabel(o client.Object) bool {
 	labels := o.GetLabels()
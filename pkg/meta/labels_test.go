@@ -0,0 +1,81 @@
+package meta
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestListManagedByController(t *testing.T) {
+	InitRedditLabels("app", "v2", "component")
+	t.Cleanup(func() { InitRedditLabels("", "", "") })
+
+	configMap := func(name, namespace string, mutate func(o client.Object)) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		mutate(cm)
+		return cm
+	}
+	labeled := func(controllerName string) func(o client.Object) {
+		return func(o client.Object) { SetRedditLabels(o, controllerName) }
+	}
+
+	created := configMap("created", "ns", labeled("controller"))
+	// adopted objects carry labels stamped over their pre-existing labels
+	adopted := configMap("adopted", "ns", func(o client.Object) {
+		o.SetLabels(map[string]string{"preexisting": "true"})
+		SetRedditLabels(o, "controller")
+	})
+	// objects applied by earlier versions of the application
+	previousVersion := configMap("previous-version", "other-ns", func(o client.Object) {
+		SetRedditLabels(o, "controller")
+		o.GetLabels()[ApplicationVersionKey] = "v1"
+	})
+	otherController := configMap("other-controller", "ns", labeled("other-controller"))
+	otherApplication := configMap("other-application", "ns", func(o client.Object) {
+		SetRedditLabels(o, "controller")
+		o.GetLabels()[ApplicationNameKey] = "other-app"
+	})
+	unlabeled := configMap("unlabeled", "ns", func(client.Object) {})
+
+	c := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithObjects(created, adopted, previousVersion, otherController, otherApplication, unlabeled).
+		Build()
+
+	ctx := context.Background()
+	gvk := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	objs, err := ListManagedByController(ctx, c, "controller", gvk)
+	if err != nil {
+		t.Fatalf("listing managed objects: %s", err)
+	}
+	assertNames(t, objs, "adopted", "created", "previous-version")
+
+	objs, err = ListManagedByController(ctx, c, "controller", gvk, client.InNamespace("ns"))
+	if err != nil {
+		t.Fatalf("listing managed objects in namespace: %s", err)
+	}
+	assertNames(t, objs, "adopted", "created")
+
+	if _, err := ListManagedByController(ctx, c, "controller", gvk.GroupVersion().WithKind("Unknown")); err == nil {
+		t.Errorf("expected error listing unregistered type")
+	}
+}
+
+func assertNames(t *testing.T, objs []client.Object, expected ...string) {
+	t.Helper()
+	var names []string
+	for _, o := range objs {
+		names = append(names, o.GetName())
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected objects %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,37 @@
+package meta
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// childNameHashLength is the number of hex characters of the hash appended to truncated child names
+const childNameHashLength = 8
+
+// ChildName returns a deterministic name for a child of parent, the parent's name and suffix joined by a hyphen.
+// Names exceeding the maximum length of object names (253 characters) are truncated, and suffixed with a hash
+// of the full name so that names of distinct children remain unique.
+func ChildName(parent client.Object, suffix string) string {
+	name := parent.GetName() + "-" + suffix
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:childNameHashLength]
+
+	// truncate on a rune boundary, leaving room for the separator and hash
+	truncated := name[:validation.DNS1123SubdomainMaxLength-childNameHashLength-1]
+	for !utf8.ValidString(truncated) {
+		truncated = truncated[:len(truncated)-1]
+	}
+	// names must end with an alphanumeric character
+	truncated = strings.TrimRight(truncated, "-.")
+
+	return truncated + "-" + hash
+}
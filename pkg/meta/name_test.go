@@ -0,0 +1,57 @@
+package meta
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestChildName(t *testing.T) {
+	parent := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	if name := ChildName(parent("parent"), "config"); name != "parent-config" {
+		t.Errorf("unexpected name %q", name)
+	}
+
+	longName := strings.Repeat("a", 250)
+	multibyteName := strings.Repeat("a", 240) + strings.Repeat("é", 10)
+
+	cases := []struct {
+		parent string
+		suffix string
+	}{
+		{parent: longName, suffix: "config"},
+		{parent: longName, suffix: "secret"},
+		{parent: longName + "b", suffix: "config"},
+		{parent: strings.Repeat("a", 242) + "-." + strings.Repeat("b", 10), suffix: "config"},
+		{parent: multibyteName, suffix: "config"},
+	}
+
+	seen := map[string]string{}
+	for _, tc := range cases {
+		name := ChildName(parent(tc.parent), tc.suffix)
+
+		if len(name) > validation.DNS1123SubdomainMaxLength {
+			t.Errorf("name of length %d exceeds limit: %q", len(name), name)
+		}
+		if !utf8.ValidString(name) {
+			t.Errorf("name is not valid UTF-8: %q", name)
+		}
+		if strings.Contains(name, "-.") || strings.Contains(name, ".-") {
+			t.Errorf("name contains invalid character sequence: %q", name)
+		}
+		if again := ChildName(parent(tc.parent), tc.suffix); again != name {
+			t.Errorf("expected stable name, got %q and %q", name, again)
+		}
+		if other, ok := seen[name]; ok {
+			t.Errorf("name %q of %s/%s collides with %s", name, tc.parent, tc.suffix, other)
+		}
+		seen[name] = tc.parent + "/" + tc.suffix
+	}
+}
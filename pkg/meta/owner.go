@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"errors"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -9,8 +10,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// ErrInvalidOwnerRef is returned if an owner reference is invalid in Kubernetes, which garbage collects objects with
+// invalid owner references. Namespaced owners may only own objects in the same namespace, while cluster-scoped owners
+// may own both cluster-scoped and namespaced objects.
+var ErrInvalidOwnerRef = errors.New("invalid owner reference")
+
 // SetControllerRef sets an owner reference on the given object that references the owner object with controller flag set to true
 func SetControllerRef(o client.Object, owner client.Object, scheme *runtime.Scheme) error {
+	if err := validateOwnerRef(o, owner); err != nil {
+		return fmt.Errorf("setting controller reference on %s %q: %w", MustGVKForObject(o, scheme).Kind, client.ObjectKeyFromObject(o), err)
+	}
+
 	// we set a controller reference to ensure that controller-runtime queues events when using `.Owns`
 	if err := ctrl.SetControllerReference(owner, o, scheme); err != nil {
 		gvkForObject := MustGVKForObject(o, scheme)
@@ -21,6 +31,10 @@ func SetControllerRef(o client.Object, owner client.Object, scheme *runtime.Sche
 
 // SetOwnerRef appends an owner reference on the given object that references the owner object with controller flag set to false
 func SetOwnerRef(o client.Object, owner client.Object, scheme *runtime.Scheme) error {
+	if err := validateOwnerRef(o, owner); err != nil {
+		return fmt.Errorf("setting owner reference on %s %q: %w", MustGVKForObject(o, scheme).Kind, client.ObjectKeyFromObject(o), err)
+	}
+
 	// we set an owner reference to ensure that controller-runtime queues events when using `.Owns`
 	if err := controllerutil.SetOwnerReference(owner, o, scheme); err != nil {
 		gvkForObject := MustGVKForObject(o, scheme)
@@ -28,3 +42,20 @@ func SetOwnerRef(o client.Object, owner client.Object, scheme *runtime.Scheme) e
 	}
 	return nil
 }
+
+// validateOwnerRef returns ErrInvalidOwnerRef if the owner is namespaced and the object is cluster-scoped or in a different namespace.
+// Scope is inferred from the objects' namespaces, so the namespace of namespaced objects must be populated.
+func validateOwnerRef(o client.Object, owner client.Object) error {
+	ownerNamespace := owner.GetNamespace()
+	if ownerNamespace == "" {
+		// cluster-scoped owners may own any object
+		return nil
+	}
+	if o.GetNamespace() == "" {
+		return fmt.Errorf("%w: cluster-scoped object cannot be owned by namespaced owner %s", ErrInvalidOwnerRef, client.ObjectKeyFromObject(owner))
+	}
+	if o.GetNamespace() != ownerNamespace {
+		return fmt.Errorf("%w: object in namespace %q cannot be owned by owner %s in a different namespace", ErrInvalidOwnerRef, o.GetNamespace(), client.ObjectKeyFromObject(owner))
+	}
+	return nil
+}
@@ -32,12 +32,50 @@ func NewDefaultProviderRateLimiter(rps int) *workqueue.TypedBucketRateLimiter[re
 	return NewGlobal(rps)
 }
 
+const (
+	// DefaultBackoffBaseDelay is the default base delay of the per-item exponential backoff of managed rate limiters.
+	DefaultBackoffBaseDelay = 1 * time.Second
+	// DefaultBackoffMaxDelay is the default maximum delay of the per-item exponential backoff of managed rate limiters.
+	DefaultBackoffMaxDelay = 60 * time.Second
+)
+
+// ManagedRateLimiterOption configures the per-item exponential backoff of a managed rate limiter.
+type ManagedRateLimiterOption func(*managedRateLimiterOptions)
+
+type managedRateLimiterOptions struct {
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// WithBackoffBaseDelay sets the delay of the first retry of an item, doubled for each consecutive retry.
+// Defaults to DefaultBackoffBaseDelay. Delays are capped at the maximum delay.
+func WithBackoffBaseDelay(delay time.Duration) ManagedRateLimiterOption {
+	return func(o *managedRateLimiterOptions) {
+		o.baseDelay = delay
+	}
+}
+
+// WithBackoffMaxDelay sets the maximum delay of retries of an item. Defaults to DefaultBackoffMaxDelay.
+func WithBackoffMaxDelay(delay time.Duration) ManagedRateLimiterOption {
+	return func(o *managedRateLimiterOptions) {
+		o.maxDelay = delay
+	}
+}
+
 // NewDefaultManagedRateLimiter returns a rate limiter that takes the maximum
 // delay between the passed provider and a per-item exponential backoff limiter.
-// The exponential backoff limiter has a base delay of 1s and a maximum of 60s.
-func NewDefaultManagedRateLimiter(provider workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimiter[reconcile.Request] {
+// The exponential backoff limiter has a base delay of 1s and a maximum of 60s, unless configured otherwise through opts.
+func NewDefaultManagedRateLimiter(provider workqueue.TypedRateLimiter[reconcile.Request], opts ...ManagedRateLimiterOption) workqueue.TypedRateLimiter[reconcile.Request] {
+	o := &managedRateLimiterOptions{
+		baseDelay: DefaultBackoffBaseDelay,
+		maxDelay:  DefaultBackoffMaxDelay,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	return workqueue.NewTypedMaxOfRateLimiter[reconcile.Request](
-		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](1*time.Second, 60*time.Second),
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](o.baseDelay, o.maxDelay),
 		provider,
 	)
 }
@@ -0,0 +1,46 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNewDefaultManagedRateLimiter(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []ManagedRateLimiterOption
+		expected []time.Duration
+	}{
+		{
+			name:     "defaults",
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:     "custom base delay",
+			opts:     []ManagedRateLimiterOption{WithBackoffBaseDelay(5 * time.Second)},
+			expected: []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second},
+		},
+		{
+			name:     "custom max delay",
+			opts:     []ManagedRateLimiterOption{WithBackoffBaseDelay(5 * time.Second), WithBackoffMaxDelay(8 * time.Second)},
+			expected: []time.Duration{5 * time.Second, 8 * time.Second, 8 * time.Second},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// a generous provider rate limit doesn't delay requests
+			rl := NewDefaultManagedRateLimiter(NewGlobal(1000), tc.opts...)
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "name", Namespace: "namespace"}}
+
+			for i, expected := range tc.expected {
+				if actual := rl.When(req); actual != expected {
+					t.Errorf("retry %d: expected delay %s, got %s", i, expected, actual)
+				}
+			}
+		})
+	}
+}
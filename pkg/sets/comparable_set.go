@@ -158,6 +158,29 @@ func (s *ComparableSet[K]) UnorderedList() []K {
 	return ks
 }
 
+// ForEach invokes f on each item of the set, in undefined order, until f returns false.
+func (s *ComparableSet[K]) ForEach(f func(K) bool) {
+	for item := range s.set {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// Reduce aggregates the items of the set by successively applying f to the accumulated value, starting at init, and each item.
+// Items are visited in undefined order, so f should be commutative. Reduce is a function rather than a method because
+// Go methods can't declare type parameters.
+// For example:
+// s = {1, 2, 3}
+// Reduce(s, 0, func(sum, item int) int { return sum + item }) = 6
+func Reduce[K comparable, U any](s *ComparableSet[K], init U, f func(U, K) U) U {
+	acc := init
+	for item := range s.set {
+		acc = f(acc, item)
+	}
+	return acc
+}
+
 // Len returns the size of the set.
 func (s *ComparableSet[K]) Len() int {
 	return len(s.set)
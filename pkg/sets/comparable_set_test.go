@@ -305,3 +305,48 @@ func TestComparableSet_Intersection(t *testing.T) {
 		})
 	}
 }
+
+func TestComparableSet_ForEach(t *testing.T) {
+	s := NewComparableSet(aComparable, bComparable, cComparable)
+
+	visited := NewComparableSet[testStruct]()
+	s.ForEach(func(item testStruct) bool {
+		visited.Insert(item)
+		return true
+	})
+	if !visited.Equal(s) {
+		t.Errorf("Expected all items to be visited: %#v", visited)
+	}
+
+	var count int
+	s.ForEach(func(testStruct) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Expected iteration to stop after the first item: %d", count)
+	}
+
+	NewComparableSet[testStruct]().ForEach(func(testStruct) bool {
+		t.Errorf("Unexpected iteration over empty set")
+		return true
+	})
+}
+
+func TestComparableSet_Reduce(t *testing.T) {
+	s := NewComparableSet(aComparable, bComparable, cComparable, dComparable, eComparable, fComparable)
+
+	sum := Reduce(s, 0, func(acc int, item testStruct) int {
+		return acc + item.Field2
+	})
+	if sum != 16 {
+		t.Errorf("Expected sum=16: %d", sum)
+	}
+
+	empty := Reduce(NewComparableSet[testStruct](), 0, func(acc int, item testStruct) int {
+		return acc + item.Field2
+	})
+	if empty != 0 {
+		t.Errorf("Expected sum=0 for empty set: %d", empty)
+	}
+}
@@ -36,6 +36,31 @@ func NewObjectSet(scheme *runtime.Scheme, objects ...client.Object) *ObjectSet {
 	return set
 }
 
+// NewObjectSetFromRefs returns a new ObjectSet with a given scheme and empty objects, with only their name and namespace set,
+// instantiated from the given refs. Returns an error if a ref's GVK isn't registered with the scheme.
+func NewObjectSetFromRefs(scheme *runtime.Scheme, refs ...api.TypedObjectRef) (*ObjectSet, error) {
+	set := NewObjectSet(scheme)
+	for _, ref := range refs {
+		obj, err := meta.NewObjectForGVK(scheme, ref.GroupVersionKind())
+		if err != nil {
+			return nil, fmt.Errorf("instantiating object for ref %s: %w", ref, err)
+		}
+		obj.SetName(ref.Name)
+		obj.SetNamespace(ref.Namespace)
+		set.Insert(obj)
+	}
+	return set, nil
+}
+
+// MustNewObjectSetFromRefs is like NewObjectSetFromRefs but panics if a ref's GVK isn't registered with the scheme.
+func MustNewObjectSetFromRefs(scheme *runtime.Scheme, refs ...api.TypedObjectRef) *ObjectSet {
+	set, err := NewObjectSetFromRefs(scheme, refs...)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
 // GetByRef gets an object from the set for a given TypedObjectRef. Returns nil if the object cannot be found.
 func (s *ObjectSet) GetByRef(ref api.TypedObjectRef) client.Object {
 	gvk := ref.GroupVersionKind()
@@ -164,6 +189,36 @@ func (s *ObjectSet) Intersection(other *ObjectSet) *ObjectSet {
 	return result
 }
 
+// Diff splits the receiver and other into three sets in a single pass:
+// added contains objects in s1 but not in s2, removed contains objects in s2 but not in s1,
+// and common contains objects in both s1 and s2.
+// Objects are compared by key, so objects with identical keys but differing values are placed in common.
+// The elements of common are sourced from the receiver.
+// For example:
+// s1 = {a1, a2, a3}
+// s2 = {a2, a3, a4}
+// s1.Diff(s2) = {a1}, {a4}, {a2, a3}
+func (s *ObjectSet) Diff(other *ObjectSet) (added, removed, common *ObjectSet) {
+	added = NewObjectSet(s.scheme)
+	removed = NewObjectSet(s.scheme)
+	common = NewObjectSet(s.scheme)
+
+	for key, obj := range s.set {
+		if _, ok := other.set[key]; ok {
+			common.set[key] = obj
+		} else {
+			added.set[key] = obj
+		}
+	}
+	for key, obj := range other.set {
+		if _, ok := s.set[key]; !ok {
+			removed.set[key] = obj
+		}
+	}
+
+	return added, removed, common
+}
+
 // IsSuperset returns true if and only if s1 is a superset of s2.
 func (s *ObjectSet) IsSuperset(other *ObjectSet) bool {
 	for _, obj := range other.set {
@@ -206,6 +261,17 @@ func (s *ObjectSet) List() []client.Object {
 	return res
 }
 
+// ListRefs returns refs to the contents of the set in the same stable order as List, i.e. sorted by GVK, then
+// namespace and name.
+func (s *ObjectSet) ListRefs() []api.TypedObjectRef {
+	objs := s.List()
+	refs := make([]api.TypedObjectRef, len(objs))
+	for i, o := range objs {
+		refs[i] = *meta.MustTypedObjectRefFromObject(o, s.scheme)
+	}
+	return refs
+}
+
 // Len returns the size of the set.
 func (s *ObjectSet) Len() int {
 	return len(s.set)
@@ -9,6 +9,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/reddit/achilles-sdk-api/api"
 	internalscheme "github.com/reddit/achilles-sdk/pkg/internal/scheme"
 	"github.com/reddit/achilles-sdk/pkg/meta"
 )
@@ -143,6 +144,29 @@ func TestObjectSet_List(t *testing.T) {
 	}
 }
 
+func TestObjectSet_ListRefs(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "z",
+			Namespace: "1",
+		},
+	}
+	expected := []api.TypedObjectRef{
+		*meta.MustTypedObjectRefFromObject(cm, scheme),
+		*meta.MustTypedObjectRefFromObject(a, scheme),
+		*meta.MustTypedObjectRefFromObject(b, scheme),
+		*meta.MustTypedObjectRefFromObject(c, scheme),
+		*meta.MustTypedObjectRefFromObject(d, scheme),
+	}
+
+	// sorted by GVK, then namespace and name, regardless of insertion order
+	for _, objs := range [][]client.Object{{a, b, c, d, cm}, {d, c, b, a, cm}, {cm, c, a, d, b}} {
+		if diff := cmp.Diff(NewObjectSet(scheme, objs...).ListRefs(), expected); diff != "" {
+			t.Errorf("ListRefs gave unexpected results:\n%s", diff)
+		}
+	}
+}
+
 func TestObjectSet_Difference(t *testing.T) {
 	s1 := NewObjectSet(scheme, a, b, c)
 	s2 := NewObjectSet(scheme, a, b, d, e)
@@ -347,3 +371,119 @@ func TestObjectSet_Intersection(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectSet_Diff(t *testing.T) {
+	cCopy := c.DeepCopy()
+	cCopy.Spec.AutomountServiceAccountToken = ptr.To(true)
+	cases := []struct {
+		name            string
+		s1              *ObjectSet
+		s2              *ObjectSet
+		expectedAdded   *ObjectSet
+		expectedRemoved *ObjectSet
+		expectedCommon  *ObjectSet
+	}{
+		{
+			name:            "disjoint",
+			s1:              NewObjectSet(scheme, a, b),
+			s2:              NewObjectSet(scheme, c, d),
+			expectedAdded:   NewObjectSet(scheme, a, b),
+			expectedRemoved: NewObjectSet(scheme, c, d),
+			expectedCommon:  NewObjectSet(scheme),
+		},
+		{
+			name:            "overlapping",
+			s1:              NewObjectSet(scheme, a, b, c),
+			s2:              NewObjectSet(scheme, b, c, d),
+			expectedAdded:   NewObjectSet(scheme, a),
+			expectedRemoved: NewObjectSet(scheme, d),
+			expectedCommon:  NewObjectSet(scheme, b, c),
+		},
+		{
+			name:            "identical",
+			s1:              NewObjectSet(scheme, a, b, c),
+			s2:              NewObjectSet(scheme, a, b, c),
+			expectedAdded:   NewObjectSet(scheme),
+			expectedRemoved: NewObjectSet(scheme),
+			expectedCommon:  NewObjectSet(scheme, a, b, c),
+		},
+		{
+			name:            "empty",
+			s1:              NewObjectSet(scheme),
+			s2:              NewObjectSet(scheme),
+			expectedAdded:   NewObjectSet(scheme),
+			expectedRemoved: NewObjectSet(scheme),
+			expectedCommon:  NewObjectSet(scheme),
+		},
+		{
+			name:            "values",
+			s1:              NewObjectSet(scheme, a, cCopy),
+			s2:              NewObjectSet(scheme, c, d),
+			expectedAdded:   NewObjectSet(scheme, a),
+			expectedRemoved: NewObjectSet(scheme, d),
+			expectedCommon:  NewObjectSet(scheme, cCopy), // result should contain values sourced from s1
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			added, removed, common := tc.s1.Diff(tc.s2)
+
+			if diff := cmp.Diff(added.set, tc.expectedAdded.set); diff != "" {
+				t.Errorf("added differs from expected: (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(removed.set, tc.expectedRemoved.set); diff != "" {
+				t.Errorf("removed differs from expected: (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(common.set, tc.expectedCommon.set); diff != "" {
+				t.Errorf("common differs from expected: (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewObjectSetFromRefs(t *testing.T) {
+	refs := []api.TypedObjectRef{
+		*meta.MustTypedObjectRefFromObject(a, scheme),
+		*meta.MustTypedObjectRefFromObject(b, scheme),
+	}
+
+	s, err := NewObjectSetFromRefs(scheme, refs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s.Len() != 2 {
+		t.Errorf("expected set of length 2, got %d", s.Len())
+	}
+
+	// membership works interchangeably against refs and live objects
+	if !s.HasAll(a, b) {
+		t.Error("expected set from refs to contain live objects")
+	}
+	if s.Has(c) {
+		t.Error("expected set from refs to not contain object c")
+	}
+	for _, ref := range refs {
+		if s.GetByRef(ref) == nil {
+			t.Errorf("expected set from refs to contain ref %s", ref)
+		}
+	}
+	if !NewObjectSet(scheme, a, b).Equal(s) {
+		t.Error("expected set from refs to equal set from live objects")
+	}
+	if diff := cmp.Diff([]client.Object{c}, NewObjectSet(scheme, a, c).Difference(s).List()); diff != "" {
+		t.Errorf("unexpected difference (-want +got):\n%s", diff)
+	}
+
+	// refs with unregistered GVKs error
+	unregistered := api.TypedObjectRef{Group: "unregistered.reddit.com", Version: "v1", Kind: "Unregistered", Name: "a"}
+	if _, err := NewObjectSetFromRefs(scheme, unregistered); err == nil {
+		t.Error("expected error for ref with unregistered GVK")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustNewObjectSetFromRefs to panic for ref with unregistered GVK")
+		}
+	}()
+	MustNewObjectSetFromRefs(scheme, unregistered)
+}
@@ -2,6 +2,7 @@ package status
 
 import (
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +15,7 @@ const (
 	ReasonFailure             = "ConditionsFailed"
 	ReadySuccessMessage       = "All conditions successful."
 	readyFailureMessagePrefix = "Non-successful conditions: "
+	childCausePrefix          = "Caused by child "
 )
 
 var (
@@ -31,12 +33,51 @@ var (
 // resource's "Ready" condition is true and observed generation matches the current generation.
 // The condition reason and message are not compared.
 func ResourceReady(res api.Conditioned) bool {
-	readyCondition := res.GetCondition(api.TypeReady)
-	return readyCondition.Type == api.TypeReady &&
+	return ResourceReadyForType(res, api.TypeReady)
+}
+
+// ResourceReadyForType is like ResourceReady, for resources whose top-level readiness is reported by a condition type other
+// than "Ready" (e.g. "Available").
+func ResourceReadyForType(res api.Conditioned, conditionType api.ConditionType) bool {
+	readyCondition := res.GetCondition(conditionType)
+	return readyCondition.Type == conditionType &&
 		readyCondition.Status == corev1.ConditionTrue &&
 		readyCondition.ObservedGeneration == res.GetGeneration()
 }
 
+// ConditionAge returns the time elapsed since the last transition of the resource's condition of the given type,
+// e.g. for detecting resources that have been unready for too long.
+// Returns zero if the resource has no such condition, or if its lastTransitionTime isn't set.
+func ConditionAge(res api.Conditioned, conditionType api.ConditionType) time.Duration {
+	for _, condition := range res.GetConditions() {
+		if condition.Type != conditionType {
+			continue
+		}
+		if condition.LastTransitionTime.IsZero() {
+			return 0
+		}
+		return time.Since(condition.LastTransitionTime.Time)
+	}
+	return 0
+}
+
+// ConditionsWithStatus returns all of the resource's conditions with the given status, in the order in which they appear
+// in the resource's status. Conditions with an empty status are treated as corev1.ConditionUnknown.
+// Returns nil if no conditions match.
+func ConditionsWithStatus(res api.Conditioned, status corev1.ConditionStatus) []api.Condition {
+	var matching []api.Condition
+	for _, condition := range res.GetConditions() {
+		conditionStatus := condition.Status
+		if conditionStatus == "" {
+			conditionStatus = corev1.ConditionUnknown
+		}
+		if conditionStatus == status {
+			matching = append(matching, condition)
+		}
+	}
+	return matching
+}
+
 // NewReadyCondition returns an api.Condition of type "Ready" whose value is the conjunction
 // of all provided conditions. Conditions in unknown status will result in a failed Ready condition.
 // ObservedGeneration is the generation of the object when the condition was last observed.
@@ -64,6 +105,74 @@ func NewReadyCondition(observedGeneration int64, conditions ...api.Condition) ap
 	}
 }
 
+// ChildCondition is the Ready condition of a child resource, e.g. a managed resource.
+type ChildCondition struct {
+	// Child describes the child resource, e.g. its kind and namespaced name.
+	Child string
+	// Condition is the child's Ready condition.
+	Condition api.Condition
+}
+
+// WithChildCause returns the provided Ready condition with the cause of the most severe non-ready child appended to its message.
+// Children with a False Ready condition take precedence over children with an Unknown Ready condition, ties are resolved
+// in favor of the first child. The condition is returned unchanged if it is successful or all children are ready.
+//
+// If the child's Ready condition itself carries a cause propagated from its own children, that cause is propagated instead,
+// so that the root cause of a nested chain of resources surfaces on every ancestor, and messages don't grow with
+// the depth of the chain (or indefinitely, for cyclic chains).
+func WithChildCause(ready api.Condition, children ...ChildCondition) api.Condition {
+	if ready.Status == corev1.ConditionTrue {
+		return ready
+	}
+
+	var cause *ChildCondition
+	for i, child := range children {
+		if child.Condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		if cause == nil || (child.Condition.Status == corev1.ConditionFalse && cause.Condition.Status != corev1.ConditionFalse) {
+			cause = &children[i]
+		}
+	}
+	if cause == nil {
+		return ready
+	}
+
+	causeMessage := childCausePrefix + childCauseMessage(*cause)
+	if ready.Message == "" {
+		ready.Message = causeMessage
+	} else {
+		ready.Message += ". " + causeMessage
+	}
+	return ready
+}
+
+// childCauseMessage returns the root cause of the non-ready child
+func childCauseMessage(child ChildCondition) string {
+	if _, cause, ok := strings.Cut(child.Condition.Message, childCausePrefix); ok {
+		return cause
+	}
+
+	msg := child.Child
+	if child.Condition.Reason != "" {
+		msg += " (" + string(child.Condition.Reason) + ")"
+	}
+	if child.Condition.Message != "" {
+		msg += ": " + child.Condition.Message
+	}
+	return msg
+}
+
+// WithObservedGeneration returns the provided condition with its observedGeneration set to the provided generation,
+// so that consumers can tell whether the condition reflects the resource's latest spec.
+// A condition whose observedGeneration is already set is returned unchanged, respecting callers that set it explicitly.
+func WithObservedGeneration(c api.Condition, observedGeneration int64) api.Condition {
+	if c.ObservedGeneration == 0 {
+		c.ObservedGeneration = observedGeneration
+	}
+	return c
+}
+
 func NewUnreadyCondition(observedGeneration int64) api.Condition {
 	return NewUnreadyConditionWithMessage(observedGeneration, "")
 }
@@ -91,3 +200,62 @@ func readyMessage(nonSuccessfulConditions []api.Condition) string {
 
 	return readyFailureMessagePrefix + strings.Join(failedConditionTypes, ", ")
 }
+
+// ToMetaCondition converts an api.Condition to a metav1.Condition, for interoperability with libraries that operate on
+// the latter. All fields are preserved, so the result converts back to an identical api.Condition with FromMetaCondition.
+// NOTE: metav1.Condition requires a non-empty reason when persisted, which api.Condition does not.
+func ToMetaCondition(c api.Condition) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(c.Type),
+		Status:             metav1.ConditionStatus(c.Status),
+		ObservedGeneration: c.ObservedGeneration,
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             string(c.Reason),
+		Message:            c.Message,
+	}
+}
+
+// FromMetaCondition converts a metav1.Condition to an api.Condition. It is the inverse of ToMetaCondition.
+func FromMetaCondition(c metav1.Condition) api.Condition {
+	return api.Condition{
+		Type:               api.ConditionType(c.Type),
+		Status:             corev1.ConditionStatus(c.Status),
+		ObservedGeneration: c.ObservedGeneration,
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             api.ConditionReason(c.Reason),
+		Message:            c.Message,
+	}
+}
+
+// SetConditionsAtomic replaces the status's conditions with the supplied conditions in a single assignment, rather than
+// setting them one at a time, so that intermediate condition sets are never observed. Existing conditions of types that
+// aren't supplied are removed. The lastTransitionTime of a condition whose status is unchanged is preserved, even if its
+// reason or message changed, while conditions that are new or whose status changed share the same, current lastTransitionTime.
+// This avoids spurious status patches caused by transition times changing on every reconcile.
+// If multiple conditions of the same type are supplied, the last one wins.
+func SetConditionsAtomic(status *api.ConditionedStatus, conditions ...api.Condition) {
+	existing := map[api.ConditionType]api.Condition{}
+	for _, c := range status.Conditions {
+		existing[c.Type] = c
+	}
+
+	now := metav1.Now()
+	byType := map[api.ConditionType]int{}
+	var replacement []api.Condition
+	for _, c := range conditions {
+		if e, ok := existing[c.Type]; ok && e.Status == c.Status {
+			c.LastTransitionTime = e.LastTransitionTime
+		} else {
+			c.LastTransitionTime = now
+		}
+
+		if i, ok := byType[c.Type]; ok {
+			replacement[i] = c
+			continue
+		}
+		byType[c.Type] = len(replacement)
+		replacement = append(replacement, c)
+	}
+
+	status.Conditions = replacement
+}
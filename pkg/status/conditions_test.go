@@ -2,6 +2,7 @@ package status_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -81,6 +82,30 @@ func TestResourceReadyFalse(t *testing.T) {
 	}
 }
 
+func TestResourceReadyForType(t *testing.T) {
+	conditions := []api.Condition{
+		{
+			Type:   "Available",
+			Status: corev1.ConditionTrue,
+		},
+		{
+			Type:   api.TypeReady,
+			Status: corev1.ConditionFalse,
+		},
+	}
+
+	res := newConditionedResource(conditions)
+	if !status.ResourceReadyForType(res, "Available") {
+		t.Error("expected resource to be ready for condition type Available")
+	}
+	if status.ResourceReadyForType(res, api.TypeReady) {
+		t.Error("expected resource to not be ready for condition type Ready")
+	}
+	if status.ResourceReadyForType(res, "Missing") {
+		t.Error("expected resource to not be ready for missing condition type")
+	}
+}
+
 func TestNewReadyConditionSuccess(t *testing.T) {
 	conditions := []api.Condition{
 		{
@@ -145,3 +170,260 @@ func TestNewReadyConditionFailure(t *testing.T) {
 		t.Errorf("Unexpected result for NewReadyCondition: \n%s", diff)
 	}
 }
+
+func TestMetaConditionRoundTrip(t *testing.T) {
+	lastTransitionTime := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	condition := api.Condition{
+		Type:               "Provisioned",
+		Status:             corev1.ConditionFalse,
+		ObservedGeneration: 3,
+		LastTransitionTime: lastTransitionTime,
+		Reason:             "QuotaExceeded",
+		Message:            "quota exceeded",
+	}
+	metaCondition := metav1.Condition{
+		Type:               "Provisioned",
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: 3,
+		LastTransitionTime: lastTransitionTime,
+		Reason:             "QuotaExceeded",
+		Message:            "quota exceeded",
+	}
+
+	if diff := cmp.Diff(metaCondition, status.ToMetaCondition(condition)); diff != "" {
+		t.Errorf("ToMetaCondition: (-want +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(condition, status.FromMetaCondition(metaCondition)); diff != "" {
+		t.Errorf("FromMetaCondition: (-want +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(condition, status.FromMetaCondition(status.ToMetaCondition(condition))); diff != "" {
+		t.Errorf("round trip: (-want +got)\n%s", diff)
+	}
+}
+
+func TestWithObservedGeneration(t *testing.T) {
+	condition := api.Condition{Type: "Provisioned", Status: corev1.ConditionTrue}
+
+	if actual := status.WithObservedGeneration(condition, 3); actual.ObservedGeneration != 3 {
+		t.Errorf("expected observed generation 3, got %d", actual.ObservedGeneration)
+	}
+
+	// explicitly set observed generations are respected
+	condition.ObservedGeneration = 2
+	if actual := status.WithObservedGeneration(condition, 3); actual.ObservedGeneration != 2 {
+		t.Errorf("expected observed generation 2, got %d", actual.ObservedGeneration)
+	}
+}
+
+func TestConditionsWithStatus(t *testing.T) {
+	conditions := []api.Condition{
+		{Type: "TypeA", Status: corev1.ConditionTrue},
+		{Type: "TypeB", Status: corev1.ConditionFalse},
+		{Type: "TypeC", Status: corev1.ConditionUnknown},
+		{Type: "TypeD", Status: corev1.ConditionTrue},
+		{Type: "TypeE"},
+	}
+
+	tcs := []struct {
+		name       string
+		conditions []api.Condition
+		status     corev1.ConditionStatus
+		expected   []api.Condition
+	}{
+		{
+			name:       "true",
+			conditions: conditions,
+			status:     corev1.ConditionTrue,
+			expected:   []api.Condition{conditions[0], conditions[3]},
+		},
+		{
+			name:       "false",
+			conditions: conditions,
+			status:     corev1.ConditionFalse,
+			expected:   []api.Condition{conditions[1]},
+		},
+		{
+			name:       "unknown includes empty status",
+			conditions: conditions,
+			status:     corev1.ConditionUnknown,
+			expected:   []api.Condition{conditions[2], conditions[4]},
+		},
+		{
+			name:       "no matching conditions",
+			conditions: conditions[:1],
+			status:     corev1.ConditionFalse,
+			expected:   nil,
+		},
+		{
+			name:     "no conditions",
+			status:   corev1.ConditionTrue,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := status.ConditionsWithStatus(newConditionedResource(tc.conditions), tc.status)
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Unexpected result for ConditionsWithStatus: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithChildCause(t *testing.T) {
+	ready := status.NewReadyCondition(mockGeneration, api.Condition{Type: "TypeA", Status: corev1.ConditionFalse})
+
+	cases := []struct {
+		name     string
+		ready    api.Condition
+		children []status.ChildCondition
+		expected string
+	}{
+		{
+			name:  "ready",
+			ready: status.NewReadyCondition(mockGeneration),
+			children: []status.ChildCondition{
+				{Child: "ConfigMap default/a", Condition: api.Condition{Status: corev1.ConditionFalse, Reason: "Failed"}},
+			},
+			expected: status.ReadySuccessMessage,
+		},
+		{
+			name:  "all children ready",
+			ready: ready,
+			children: []status.ChildCondition{
+				{Child: "ConfigMap default/a", Condition: api.Condition{Status: corev1.ConditionTrue}},
+			},
+			expected: "Non-successful conditions: TypeA",
+		},
+		{
+			name:  "most severe child",
+			ready: ready,
+			children: []status.ChildCondition{
+				{Child: "ConfigMap default/a", Condition: api.Condition{Status: corev1.ConditionTrue}},
+				{Child: "ConfigMap default/b", Condition: api.Condition{Status: corev1.ConditionUnknown, Reason: "Pending"}},
+				{Child: "ConfigMap default/c", Condition: api.Condition{Status: corev1.ConditionFalse, Reason: "QuotaExceeded", Message: "quota exceeded"}},
+				{Child: "ConfigMap default/d", Condition: api.Condition{Status: corev1.ConditionFalse, Reason: "Invalid"}},
+			},
+			expected: "Non-successful conditions: TypeA. Caused by child ConfigMap default/c (QuotaExceeded): quota exceeded",
+		},
+		{
+			name:  "nested child",
+			ready: ready,
+			children: []status.ChildCondition{
+				{Child: "ConfigMap default/a", Condition: api.Condition{
+					Status:  corev1.ConditionFalse,
+					Message: "Non-successful conditions: TypeB. Caused by child Secret default/b (Invalid): invalid data",
+				}},
+			},
+			expected: "Non-successful conditions: TypeA. Caused by child Secret default/b (Invalid): invalid data",
+		},
+		{
+			name:  "empty message",
+			ready: status.NewUnreadyCondition(mockGeneration),
+			children: []status.ChildCondition{
+				{Child: "ConfigMap default/a", Condition: api.Condition{Status: corev1.ConditionUnknown}},
+			},
+			expected: "Caused by child ConfigMap default/a",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := status.WithChildCause(tc.ready, tc.children...)
+			if actual.Message != tc.expected {
+				t.Errorf("unexpected message %q, want %q", actual.Message, tc.expected)
+			}
+			if actual.Status != tc.ready.Status || actual.Reason != tc.ready.Reason {
+				t.Errorf("unexpected change of status or reason: %v", actual)
+			}
+		})
+	}
+}
+
+func TestWithChildCause_Cycle(t *testing.T) {
+	// two resources that are each other's child propagate bounded messages
+	readyA := status.NewReadyCondition(mockGeneration, api.Condition{Type: "TypeA", Status: corev1.ConditionFalse})
+	readyB := status.NewReadyCondition(mockGeneration, api.Condition{Type: "TypeB", Status: corev1.ConditionFalse})
+
+	a, b := readyA, readyB
+	for range 5 {
+		a = status.WithChildCause(readyA, status.ChildCondition{Child: "Kind default/b", Condition: b})
+		b = status.WithChildCause(readyB, status.ChildCondition{Child: "Kind default/a", Condition: a})
+	}
+
+	if expected := "Non-successful conditions: TypeA. Caused by child Kind default/b (ConditionsFailed): Non-successful conditions: TypeB"; a.Message != expected {
+		t.Errorf("unexpected message %q, want %q", a.Message, expected)
+	}
+	if expected := "Non-successful conditions: TypeB. Caused by child Kind default/b (ConditionsFailed): Non-successful conditions: TypeB"; b.Message != expected {
+		t.Errorf("unexpected message %q, want %q", b.Message, expected)
+	}
+}
+
+func TestSetConditionsAtomic(t *testing.T) {
+	original := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	res := newConditionedResource([]api.Condition{
+		{Type: "Unchanged", Status: corev1.ConditionTrue, LastTransitionTime: original},
+		{Type: "NewMessage", Status: corev1.ConditionFalse, LastTransitionTime: original, Message: "waiting"},
+		{Type: "Changed", Status: corev1.ConditionFalse, LastTransitionTime: original},
+		{Type: "Stale", Status: corev1.ConditionTrue, LastTransitionTime: original},
+	})
+
+	status.SetConditionsAtomic(&res.ConditionedStatus,
+		api.Condition{Type: "Unchanged", Status: corev1.ConditionTrue},
+		api.Condition{Type: "NewMessage", Status: corev1.ConditionFalse, Message: "still waiting"},
+		api.Condition{Type: "Changed", Status: corev1.ConditionTrue},
+		api.Condition{Type: "Added", Status: corev1.ConditionFalse},
+	)
+
+	actual := map[api.ConditionType]api.Condition{}
+	for _, c := range res.GetConditions() {
+		actual[c.Type] = c
+	}
+	if len(actual) != 4 {
+		t.Fatalf("expected 4 conditions, got %v", res.GetConditions())
+	}
+
+	// conditions of types that aren't supplied are removed
+	if _, ok := actual["Stale"]; ok {
+		t.Errorf("expected condition Stale to be removed, got %v", res.GetConditions())
+	}
+
+	// transition times are preserved for conditions whose status is unchanged
+	for _, conditionType := range []api.ConditionType{"Unchanged", "NewMessage"} {
+		if actual := actual[conditionType].LastTransitionTime; !actual.Equal(&original) {
+			t.Errorf("expected condition %s to keep its transition time %s, got %s", conditionType, original, actual)
+		}
+	}
+	if actual["NewMessage"].Message != "still waiting" {
+		t.Errorf("expected condition NewMessage to be updated, got message %q", actual["NewMessage"].Message)
+	}
+
+	// transition times are updated for changed and new conditions
+	changed, added := actual["Changed"], actual["Added"]
+	if changed.Status != corev1.ConditionTrue || !changed.LastTransitionTime.After(original.Time) {
+		t.Errorf("expected condition Changed to be True with a new transition time, got %v", changed)
+	}
+	if !added.LastTransitionTime.Equal(&changed.LastTransitionTime) {
+		t.Errorf("expected changed conditions to share a transition time, got %s and %s", changed.LastTransitionTime, added.LastTransitionTime)
+	}
+}
+
+func TestConditionAge(t *testing.T) {
+	transitioned := metav1.NewTime(time.Now().Add(-time.Hour))
+	res := newConditionedResource([]api.Condition{
+		{Type: api.TypeReady, Status: corev1.ConditionFalse, LastTransitionTime: transitioned},
+		{Type: "NoTransitionTime", Status: corev1.ConditionTrue},
+	})
+
+	if age := status.ConditionAge(res, api.TypeReady); age < time.Hour || age > time.Hour+time.Minute {
+		t.Errorf("expected age of about 1h, got %s", age)
+	}
+	if age := status.ConditionAge(res, "NoTransitionTime"); age != 0 {
+		t.Errorf("expected zero age for condition without transition time, got %s", age)
+	}
+	if age := status.ConditionAge(res, "Missing"); age != 0 {
+		t.Errorf("expected zero age for missing condition, got %s", age)
+	}
+}
@@ -0,0 +1,80 @@
+package status
+
+import (
+	"errors"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	ReasonAdmissionWebhookDenied = "AdmissionWebhookDenied"
+	ReasonConflict               = "Conflict"
+	ReasonForbidden              = "Forbidden"
+	ReasonUnauthorized           = "Unauthorized"
+	ReasonInvalid                = "Invalid"
+	ReasonAPIServerUnavailable   = "APIServerUnavailable"
+
+	// maxErrorMessageLength is the maximum length, in characters, of messages of unrecognized errors
+	maxErrorMessageLength = 1024
+
+	webhookDeniedMarker = "denied the request"
+)
+
+// SanitizeErrorMessage returns a concise status condition message and reason for the given error, so that raw
+// kube-apiserver errors don't surface verbatim in status conditions. Errors are classified through errors.As, so wrapped
+// errors are supported. Unrecognized errors keep their message, truncated to a reasonable length, and an empty reason.
+func SanitizeErrorMessage(err error) (message, reason string) {
+	if err == nil {
+		return "", ""
+	}
+
+	// admission webhook denials are checked first, since they're also reported as forbidden
+	var apiStatus k8serrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		statusMessage := apiStatus.Status().Message
+		if strings.HasPrefix(statusMessage, "admission webhook ") {
+			if _, explanation, ok := strings.Cut(statusMessage, webhookDeniedMarker+": "); ok {
+				return "Request denied by admission webhook: " + truncate(explanation), ReasonAdmissionWebhookDenied
+			}
+			if strings.Contains(statusMessage, webhookDeniedMarker) {
+				return "Request denied by admission webhook.", ReasonAdmissionWebhookDenied
+			}
+		}
+	}
+
+	switch {
+	case k8serrors.IsConflict(err):
+		return "Object was modified concurrently, retrying.", ReasonConflict
+	case k8serrors.IsForbidden(err):
+		return "Controller is not permitted to perform the request.", ReasonForbidden
+	case k8serrors.IsUnauthorized(err):
+		return "Controller is not authenticated with the kube-apiserver.", ReasonUnauthorized
+	case k8serrors.IsInvalid(err):
+		// validation errors are actionable by users, so their message is retained
+		return truncate(err.Error()), ReasonInvalid
+	case k8serrors.IsTimeout(err), k8serrors.IsServerTimeout(err), k8serrors.IsTooManyRequests(err), k8serrors.IsServiceUnavailable(err):
+		return "Kube-apiserver is temporarily unavailable, retrying.", ReasonAPIServerUnavailable
+	}
+
+	return truncate(err.Error()), ""
+}
+
+// truncate truncates the message to maxErrorMessageLength characters
+func truncate(message string) string {
+	return TruncateMessage(message, maxErrorMessageLength)
+}
+
+// TruncateMessage truncates the message to its first maxLength characters followed by an ellipsis. Characters are
+// counted as runes, so multibyte characters aren't split. Messages of at most maxLength characters, or any message if
+// maxLength isn't positive, are returned unchanged.
+func TruncateMessage(message string, maxLength int) string {
+	if maxLength <= 0 || len(message) <= maxLength {
+		return message
+	}
+	runes := []rune(message)
+	if len(runes) <= maxLength {
+		return message
+	}
+	return string(runes[:maxLength]) + "..."
+}
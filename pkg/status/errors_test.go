@@ -0,0 +1,150 @@
+package status_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/reddit/achilles-sdk/pkg/status"
+)
+
+func TestSanitizeErrorMessage(t *testing.T) {
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	webhookDenied := func(message string) error {
+		return &k8serrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: message,
+		}}
+	}
+
+	longMessage := strings.Repeat("a", 2000)
+
+	tests := []struct {
+		name            string
+		err             error
+		expectedMessage string
+		expectedReason  string
+	}{
+		{
+			name:            "conflict",
+			err:             k8serrors.NewConflict(configMaps, "foo", errors.New("the object has been modified; please apply your changes to the latest version and try again")),
+			expectedMessage: "Object was modified concurrently, retrying.",
+			expectedReason:  status.ReasonConflict,
+		},
+		{
+			name:            "forbidden",
+			err:             k8serrors.NewForbidden(configMaps, "foo", errors.New(`User "system:serviceaccount:default:controller" cannot create resource "configmaps"`)),
+			expectedMessage: "Controller is not permitted to perform the request.",
+			expectedReason:  status.ReasonForbidden,
+		},
+		{
+			name:            "webhook denied",
+			err:             webhookDenied(`admission webhook "validate.example.com" denied the request: spec.replicas must be positive`),
+			expectedMessage: "Request denied by admission webhook: spec.replicas must be positive",
+			expectedReason:  status.ReasonAdmissionWebhookDenied,
+		},
+		{
+			name:            "webhook denied without explanation",
+			err:             webhookDenied(`admission webhook "validate.example.com" denied the request without explanation`),
+			expectedMessage: "Request denied by admission webhook.",
+			expectedReason:  status.ReasonAdmissionWebhookDenied,
+		},
+		{
+			name:            "wrapped",
+			err:             fmt.Errorf("applying outputs: %w", k8serrors.NewConflict(configMaps, "foo", errors.New("conflict"))),
+			expectedMessage: "Object was modified concurrently, retrying.",
+			expectedReason:  status.ReasonConflict,
+		},
+		{
+			name:            "throttled",
+			err:             k8serrors.NewTooManyRequests("too many requests", 1),
+			expectedMessage: "Kube-apiserver is temporarily unavailable, retrying.",
+			expectedReason:  status.ReasonAPIServerUnavailable,
+		},
+		{
+			name:            "unknown",
+			err:             errors.New("foo namespace not found"),
+			expectedMessage: "foo namespace not found",
+		},
+		{
+			name:            "unknown truncated",
+			err:             errors.New(longMessage),
+			expectedMessage: longMessage[:1024] + "...",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			message, reason := status.SanitizeErrorMessage(tc.err)
+			if message != tc.expectedMessage {
+				t.Errorf("expected message %q, got %q", tc.expectedMessage, message)
+			}
+			if reason != tc.expectedReason {
+				t.Errorf("expected reason %q, got %q", tc.expectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestTruncateMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		maxLength int
+		expected  string
+	}{
+		{
+			name:      "shorter",
+			message:   "short",
+			maxLength: 10,
+			expected:  "short",
+		},
+		{
+			name:      "at limit",
+			message:   "exactly 10",
+			maxLength: 10,
+			expected:  "exactly 10",
+		},
+		{
+			name:      "longer",
+			message:   "this message is too long",
+			maxLength: 12,
+			expected:  "this message...",
+		},
+		{
+			name:      "multibyte at limit",
+			message:   "héllo wörld",
+			maxLength: 11,
+			expected:  "héllo wörld",
+		},
+		{
+			name:      "multibyte longer",
+			message:   "日本語のメッセージ",
+			maxLength: 3,
+			expected:  "日本語...",
+		},
+		{
+			name:      "no limit",
+			message:   "this message is too long",
+			maxLength: 0,
+			expected:  "this message is too long",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := status.TruncateMessage(tc.message, tc.maxLength); actual != tc.expected {
+				t.Errorf("expected message %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}